@@ -23,6 +23,14 @@ type PistageClient interface {
 	RollbackOneway(ctx context.Context, in *RollbackPistageRequest, opts ...grpc.CallOption) (*RollbackReply, error)
 	RollbackStream(ctx context.Context, in *RollbackPistageRequest, opts ...grpc.CallOption) (Pistage_RollbackStreamClient, error)
 	GetWorkflowRuns(ctx context.Context, in *GetWorkflowRunsRequest, opts ...grpc.CallOption) (*GetWorkflowRunsReply, error)
+	Plan(ctx context.Context, in *PlanPistageRequest, opts ...grpc.CallOption) (*PlanPistageReply, error)
+	ListKhoriumSteps(ctx context.Context, in *ListKhoriumStepsRequest, opts ...grpc.CallOption) (*ListKhoriumStepsReply, error)
+	DescribeKhoriumStep(ctx context.Context, in *DescribeKhoriumStepRequest, opts ...grpc.CallOption) (*DescribeKhoriumStepReply, error)
+	Validate(ctx context.Context, in *ValidatePistageRequest, opts ...grpc.CallOption) (*ValidatePistageReply, error)
+	GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaReply, error)
+	Graph(ctx context.Context, in *GraphPistageRequest, opts ...grpc.CallOption) (*GraphPistageReply, error)
+	RunAnalytics(ctx context.Context, in *RunAnalyticsRequest, opts ...grpc.CallOption) (*RunAnalyticsReply, error)
+	GetQueue(ctx context.Context, in *GetQueueRequest, opts ...grpc.CallOption) (*GetQueueReply, error)
 }
 
 type pistageClient struct {
@@ -124,6 +132,78 @@ func (c *pistageClient) GetWorkflowRuns(ctx context.Context, in *GetWorkflowRuns
 	return out, nil
 }
 
+func (c *pistageClient) Plan(ctx context.Context, in *PlanPistageRequest, opts ...grpc.CallOption) (*PlanPistageReply, error) {
+	out := new(PlanPistageReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/Plan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pistageClient) ListKhoriumSteps(ctx context.Context, in *ListKhoriumStepsRequest, opts ...grpc.CallOption) (*ListKhoriumStepsReply, error) {
+	out := new(ListKhoriumStepsReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/ListKhoriumSteps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pistageClient) DescribeKhoriumStep(ctx context.Context, in *DescribeKhoriumStepRequest, opts ...grpc.CallOption) (*DescribeKhoriumStepReply, error) {
+	out := new(DescribeKhoriumStepReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/DescribeKhoriumStep", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pistageClient) Validate(ctx context.Context, in *ValidatePistageRequest, opts ...grpc.CallOption) (*ValidatePistageReply, error) {
+	out := new(ValidatePistageReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pistageClient) GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaReply, error) {
+	out := new(GetSchemaReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/GetSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pistageClient) Graph(ctx context.Context, in *GraphPistageRequest, opts ...grpc.CallOption) (*GraphPistageReply, error) {
+	out := new(GraphPistageReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/Graph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pistageClient) RunAnalytics(ctx context.Context, in *RunAnalyticsRequest, opts ...grpc.CallOption) (*RunAnalyticsReply, error) {
+	out := new(RunAnalyticsReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/RunAnalytics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pistageClient) GetQueue(ctx context.Context, in *GetQueueRequest, opts ...grpc.CallOption) (*GetQueueReply, error) {
+	out := new(GetQueueReply)
+	err := c.cc.Invoke(ctx, "/proto.Pistage/GetQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PistageServer is the server API for Pistage service.
 // All implementations must embed UnimplementedPistageServer
 // for forward compatibility
@@ -133,6 +213,14 @@ type PistageServer interface {
 	RollbackOneway(context.Context, *RollbackPistageRequest) (*RollbackReply, error)
 	RollbackStream(*RollbackPistageRequest, Pistage_RollbackStreamServer) error
 	GetWorkflowRuns(context.Context, *GetWorkflowRunsRequest) (*GetWorkflowRunsReply, error)
+	Plan(context.Context, *PlanPistageRequest) (*PlanPistageReply, error)
+	ListKhoriumSteps(context.Context, *ListKhoriumStepsRequest) (*ListKhoriumStepsReply, error)
+	DescribeKhoriumStep(context.Context, *DescribeKhoriumStepRequest) (*DescribeKhoriumStepReply, error)
+	Validate(context.Context, *ValidatePistageRequest) (*ValidatePistageReply, error)
+	GetSchema(context.Context, *GetSchemaRequest) (*GetSchemaReply, error)
+	Graph(context.Context, *GraphPistageRequest) (*GraphPistageReply, error)
+	RunAnalytics(context.Context, *RunAnalyticsRequest) (*RunAnalyticsReply, error)
+	GetQueue(context.Context, *GetQueueRequest) (*GetQueueReply, error)
 	mustEmbedUnimplementedPistageServer()
 }
 
@@ -155,6 +243,30 @@ func (UnimplementedPistageServer) RollbackStream(*RollbackPistageRequest, Pistag
 func (UnimplementedPistageServer) GetWorkflowRuns(context.Context, *GetWorkflowRunsRequest) (*GetWorkflowRunsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowRuns not implemented")
 }
+func (UnimplementedPistageServer) Plan(context.Context, *PlanPistageRequest) (*PlanPistageReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Plan not implemented")
+}
+func (UnimplementedPistageServer) ListKhoriumSteps(context.Context, *ListKhoriumStepsRequest) (*ListKhoriumStepsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKhoriumSteps not implemented")
+}
+func (UnimplementedPistageServer) DescribeKhoriumStep(context.Context, *DescribeKhoriumStepRequest) (*DescribeKhoriumStepReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeKhoriumStep not implemented")
+}
+func (UnimplementedPistageServer) Validate(context.Context, *ValidatePistageRequest) (*ValidatePistageReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedPistageServer) GetSchema(context.Context, *GetSchemaRequest) (*GetSchemaReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchema not implemented")
+}
+func (UnimplementedPistageServer) Graph(context.Context, *GraphPistageRequest) (*GraphPistageReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Graph not implemented")
+}
+func (UnimplementedPistageServer) RunAnalytics(context.Context, *RunAnalyticsRequest) (*RunAnalyticsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunAnalytics not implemented")
+}
+func (UnimplementedPistageServer) GetQueue(context.Context, *GetQueueRequest) (*GetQueueReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueue not implemented")
+}
 func (UnimplementedPistageServer) mustEmbedUnimplementedPistageServer() {}
 
 // UnsafePistageServer may be embedded to opt out of forward compatibility for this service.
@@ -264,6 +376,150 @@ func _Pistage_GetWorkflowRuns_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Pistage_Plan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanPistageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).Plan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/Plan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).Plan(ctx, req.(*PlanPistageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pistage_ListKhoriumSteps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKhoriumStepsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).ListKhoriumSteps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/ListKhoriumSteps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).ListKhoriumSteps(ctx, req.(*ListKhoriumStepsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pistage_DescribeKhoriumStep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeKhoriumStepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).DescribeKhoriumStep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/DescribeKhoriumStep",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).DescribeKhoriumStep(ctx, req.(*DescribeKhoriumStepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pistage_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidatePistageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).Validate(ctx, req.(*ValidatePistageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pistage_GetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).GetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/GetSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).GetSchema(ctx, req.(*GetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pistage_Graph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GraphPistageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).Graph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/Graph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).Graph(ctx, req.(*GraphPistageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pistage_RunAnalytics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunAnalyticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).RunAnalytics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/RunAnalytics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).RunAnalytics(ctx, req.(*RunAnalyticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pistage_GetQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PistageServer).GetQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Pistage/GetQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PistageServer).GetQueue(ctx, req.(*GetQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Pistage_ServiceDesc is the grpc.ServiceDesc for Pistage service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -283,6 +539,38 @@ var Pistage_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetWorkflowRuns",
 			Handler:    _Pistage_GetWorkflowRuns_Handler,
 		},
+		{
+			MethodName: "Plan",
+			Handler:    _Pistage_Plan_Handler,
+		},
+		{
+			MethodName: "ListKhoriumSteps",
+			Handler:    _Pistage_ListKhoriumSteps_Handler,
+		},
+		{
+			MethodName: "DescribeKhoriumStep",
+			Handler:    _Pistage_DescribeKhoriumStep_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _Pistage_Validate_Handler,
+		},
+		{
+			MethodName: "GetSchema",
+			Handler:    _Pistage_GetSchema_Handler,
+		},
+		{
+			MethodName: "Graph",
+			Handler:    _Pistage_Graph_Handler,
+		},
+		{
+			MethodName: "RunAnalytics",
+			Handler:    _Pistage_RunAnalytics_Handler,
+		},
+		{
+			MethodName: "GetQueue",
+			Handler:    _Pistage_GetQueue_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -5,9 +5,12 @@ import (
 	"context"
 	"io"
 	"net"
+	"sort"
 
 	"github.com/projecteru2/pistage/apiserver/grpc/proto"
 	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/executors/dryrun"
 	"github.com/projecteru2/pistage/stageserver"
 	"github.com/projecteru2/pistage/store"
 
@@ -20,14 +23,16 @@ type GRPCServer struct {
 
 	store  store.Store
 	stager *stageserver.StageServer
+	spec   common.SpecConfig
 
 	server *grpc.Server
 }
 
-func NewGRPCServer(store store.Store, stager *stageserver.StageServer) *GRPCServer {
+func NewGRPCServer(store store.Store, stager *stageserver.StageServer, spec common.SpecConfig) *GRPCServer {
 	return &GRPCServer{
 		store:  store,
 		stager: stager,
+		spec:   spec,
 	}
 }
 
@@ -50,8 +55,33 @@ func (g *GRPCServer) Stop() {
 	logrus.Info("[GRPCServer] graceful stopped")
 }
 
+// fromRequest builds a Pistage from the spec and the bundle of KhoriumSteps and specs relative to it,
+// inputs are the values given for the inputs of spec.
+func (g *GRPCServer) fromRequest(ctx context.Context, content string, bundle map[string][]byte, inputs map[string]string) (*common.Pistage, error) {
+	loader := store.NewSpecIncludeLoader(g.store, bundle, g.spec)
+	pistage, err := common.FromSpecWithLoader(ctx, []byte(content), loader)
+	if err != nil {
+		return nil, err
+	}
+	pistage.Vars = inputs
+	return pistage, pistage.SetBundle(bundle)
+}
+
+// fromApplyRequest builds a Pistage like fromRequest,
+// invalid inputs are rejected here instead of failing the run later.
+func (g *GRPCServer) fromApplyRequest(ctx context.Context, req *proto.ApplyPistageRequest) (*common.Pistage, error) {
+	pistage, err := g.fromRequest(ctx, req.GetContent(), req.GetBundle(), req.GetInputs())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pistage.ResolveVars(pistage.Vars); err != nil {
+		return nil, err
+	}
+	return pistage, nil
+}
+
 func (g *GRPCServer) ApplyOneway(ctx context.Context, req *proto.ApplyPistageRequest) (*proto.ApplyPistageOnewayReply, error) {
-	pistage, err := common.FromSpec([]byte(req.GetContent()))
+	pistage, err := g.fromApplyRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +97,7 @@ func (g *GRPCServer) ApplyOneway(ctx context.Context, req *proto.ApplyPistageReq
 }
 
 func (g *GRPCServer) ApplyStream(req *proto.ApplyPistageRequest, stream proto.Pistage_ApplyStreamServer) error {
-	pistage, err := common.FromSpec([]byte(req.GetContent()))
+	pistage, err := g.fromApplyRequest(stream.Context(), req)
 	if err != nil {
 		return err
 	}
@@ -94,7 +124,7 @@ func (g *GRPCServer) ApplyStream(req *proto.ApplyPistageRequest, stream proto.Pi
 }
 
 func (g *GRPCServer) RollbackOneway(ctx context.Context, req *proto.RollbackPistageRequest) (*proto.RollbackReply, error) {
-	pistage, err := common.FromSpec([]byte(req.GetContent()))
+	pistage, err := g.fromRequest(ctx, req.GetContent(), req.GetBundle(), req.GetInputs())
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +140,7 @@ func (g *GRPCServer) RollbackOneway(ctx context.Context, req *proto.RollbackPist
 }
 
 func (g *GRPCServer) RollbackStream(req *proto.RollbackPistageRequest, stream proto.Pistage_RollbackStreamServer) error {
-	pistage, err := common.FromSpec([]byte(req.GetContent()))
+	pistage, err := g.fromRequest(stream.Context(), req.GetContent(), req.GetBundle(), req.GetInputs())
 	if err != nil {
 		return err
 	}
@@ -147,6 +177,8 @@ func (g *GRPCServer) GetWorkflowRuns(ctx context.Context, req *proto.GetWorkflow
 			EndTime:      workflowRun.End,
 			WorkflowType: workflowRun.WorkflowType,
 			Status:       string(workflowRun.Status),
+			Inputs:       workflowRun.Vars,
+			Id:           workflowRun.ID,
 		})
 	}
 
@@ -158,3 +190,283 @@ func (g *GRPCServer) GetWorkflowRuns(ctx context.Context, req *proto.GetWorkflow
 		Runs:               runs,
 	}, nil
 }
+
+// Plan renders the pistage without executing anything,
+// jobs are ordered by the stages they would be executed.
+func (g *GRPCServer) Plan(ctx context.Context, req *proto.PlanPistageRequest) (*proto.PlanPistageReply, error) {
+	pistage, err := g.fromRequest(ctx, req.GetContent(), req.GetBundle(), req.GetInputs())
+	if err != nil {
+		return nil, err
+	}
+	if pistage, err = pistage.WithVars(); err != nil {
+		return nil, err
+	}
+	pistage = pistage.RenderVars()
+
+	ctx = store.WithKhoriumStepBundle(ctx, pistage.Bundle)
+	plan, err := dryrun.NewPlanner(g.store).PlanPistage(ctx, pistage)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.PlanPistageReply{
+		WorkflowType:       plan.WorkflowType,
+		WorkflowIdentifier: plan.WorkflowIdentifier,
+	}
+	for _, stage := range plan.Stages {
+		reply.Stages = append(reply.Stages, &proto.PlanStage{Jobs: stage})
+		for _, name := range stage {
+			job := plan.Jobs[name]
+			reply.Jobs = append(reply.Jobs, &proto.JobPlan{
+				Name:          job.Name,
+				DependsOn:     job.DependsOn,
+				Steps:         toProtoStepPlans(job.Steps),
+				RollbackSteps: toProtoStepPlans(job.RollbackSteps),
+			})
+		}
+	}
+	return reply, nil
+}
+
+// Validate checks the spec, and returns all the problems found with their locations,
+// the executor is checked against the executors registered on server.
+// Problems of spec are returned in reply, not as error.
+func (g *GRPCServer) Validate(ctx context.Context, req *proto.ValidatePistageRequest) (*proto.ValidatePistageReply, error) {
+	loader := store.NewSpecIncludeLoader(g.store, req.GetBundle(), g.spec)
+	errs := common.ValidateSpec(ctx, []byte(req.GetContent()), loader, executors.ExecutorNames())
+
+	reply := &proto.ValidatePistageReply{}
+	for _, err := range errs {
+		reply.Errors = append(reply.Errors, &proto.SpecError{
+			File:    err.File,
+			Line:    int32(err.Line),
+			Column:  int32(err.Column),
+			Message: err.Message,
+		})
+	}
+	return reply, nil
+}
+
+// GetSchema returns the JSON Schema of kind, pistage or khoriumstep, pistage by default.
+func (g *GRPCServer) GetSchema(ctx context.Context, req *proto.GetSchemaRequest) (*proto.GetSchemaReply, error) {
+	kind := req.GetKind()
+	if kind == "" {
+		kind = common.SchemaPistage
+	}
+	schema, err := common.JSONSchema(kind)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GetSchemaReply{Schema: string(schema)}, nil
+}
+
+// Graph returns the dependency graph of jobs of spec, or of a past run if RunId is given,
+// with the statuses and durations of jobs of the run.
+func (g *GRPCServer) Graph(ctx context.Context, req *proto.GraphPistageRequest) (*proto.GraphPistageReply, error) {
+	var (
+		pistage *common.Pistage
+		run     *common.Run
+		jobRuns []*common.JobRun
+		err     error
+	)
+	if req.GetRunId() != "" {
+		if run, err = g.store.GetPistageRun(req.GetRunId()); err != nil {
+			return nil, err
+		}
+		if pistage, err = g.store.GetPistageBySnapshotID(run.SnapshotID); err != nil {
+			return nil, err
+		}
+		if jobRuns, err = g.store.GetJobRunsByPistageRunId(run.ID); err != nil {
+			return nil, err
+		}
+	} else if pistage, err = g.fromRequest(ctx, req.GetContent(), req.GetBundle(), req.GetInputs()); err != nil {
+		return nil, err
+	}
+
+	graph, err := pistage.Graph(jobRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.GraphPistageReply{
+		WorkflowIdentifier: pistage.WorkflowIdentifier,
+		CriticalPath:       graph.CriticalPath,
+	}
+	if run != nil {
+		reply.RunId, reply.Status = run.ID, string(run.Status)
+	}
+	for _, stage := range graph.Stages {
+		reply.Stages = append(reply.Stages, &proto.PlanStage{Jobs: stage})
+		for _, name := range stage {
+			job := graph.Jobs[name]
+			reply.Jobs = append(reply.Jobs, &proto.GraphJob{
+				Name:           job.Name,
+				DependsOn:      job.DependsOn,
+				Status:         string(job.Status),
+				DurationMillis: job.Duration.Milliseconds(),
+			})
+		}
+	}
+	return reply, nil
+}
+
+// RunAnalytics returns how a completed run is scheduled and executed,
+// computed from the times of its jobs and the snapshot it's created from.
+func (g *GRPCServer) RunAnalytics(ctx context.Context, req *proto.RunAnalyticsRequest) (*proto.RunAnalyticsReply, error) {
+	run, err := g.store.GetPistageRun(req.GetRunId())
+	if err != nil {
+		return nil, err
+	}
+	pistage, err := g.store.GetPistageBySnapshotID(run.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	jobRuns, err := g.store.GetJobRunsByPistageRunId(run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := pistage.Analyze(run, jobRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.RunAnalyticsReply{
+		WorkflowIdentifier: pistage.WorkflowIdentifier,
+		RunId:              run.ID,
+		Status:             string(run.Status),
+		QueueMillis:        a.Queue.Milliseconds(),
+		DurationMillis:     a.Duration.Milliseconds(),
+		CriticalPath:       a.CriticalPath,
+		Parallelism:        a.Parallelism,
+		MaxParallelism:     int32(a.MaxParallelism),
+		Optimize:           a.Optimize,
+		Bottleneck:         a.Bottleneck,
+	}
+	for _, job := range a.Jobs {
+		reply.Jobs = append(reply.Jobs, &proto.JobAnalytics{
+			Name:            job.Name,
+			Status:          string(job.Status),
+			WaitMillis:      job.Wait.Milliseconds(),
+			QueueMillis:     job.Queue.Milliseconds(),
+			ExecutionMillis: job.Execution.Milliseconds(),
+			DurationMillis:  job.Duration.Milliseconds(),
+			Critical:        job.Critical,
+		})
+	}
+	return reply, nil
+}
+
+// GetQueue returns the pistages waiting for a runner and the jobs waiting to start,
+// in the order they'll be scheduled.
+func (g *GRPCServer) GetQueue(ctx context.Context, req *proto.GetQueueRequest) (*proto.GetQueueReply, error) {
+	status := g.stager.Status()
+	now := common.EpochMillis()
+	toProto := func(entries []*stageserver.QueueEntry) []*proto.QueueEntry {
+		var result []*proto.QueueEntry
+		for _, entry := range entries {
+			if req.GetWorkflowIdentifier() != "" && entry.WorkflowIdentifier != req.GetWorkflowIdentifier() {
+				continue
+			}
+			result = append(result, &proto.QueueEntry{
+				WorkflowType:       entry.WorkflowType,
+				WorkflowIdentifier: entry.WorkflowIdentifier,
+				Job:                entry.Job,
+				Priority:           int32(entry.Priority),
+				Position:           int32(entry.Position),
+				WaitingMillis:      now - entry.Queued,
+			})
+		}
+		return result
+	}
+
+	return &proto.GetQueueReply{
+		Pistages:          toProto(status.Pistages),
+		Jobs:              toProto(status.Jobs),
+		RunningPistages:   int32(status.RunningPistages),
+		RunningJobs:       int32(status.RunningJobs),
+		MaxConcurrentJobs: int32(status.MaxConcurrentJobs),
+	}, nil
+}
+
+func toProtoStepPlans(steps []*dryrun.StepPlan) []*proto.StepPlan {
+	plans := make([]*proto.StepPlan, 0, len(steps))
+	for _, step := range steps {
+		plans = append(plans, &proto.StepPlan{
+			Name:        step.Name,
+			Uses:        step.Uses,
+			Commands:    step.Commands,
+			OnError:     step.OnError,
+			Post:        step.Post,
+			Environment: step.Environment,
+			Inputs:      step.Inputs,
+		})
+	}
+	return plans
+}
+
+// ListKhoriumSteps lists the KhoriumSteps known, with names starting with prefix.
+func (g *GRPCServer) ListKhoriumSteps(ctx context.Context, req *proto.ListKhoriumStepsRequest) (*proto.ListKhoriumStepsReply, error) {
+	summaries, err := g.store.ListKhoriumSteps(ctx, req.GetPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.ListKhoriumStepsReply{}
+	for _, summary := range summaries {
+		reply.Steps = append(reply.Steps, &proto.KhoriumStepSummary{
+			Name:        summary.Name,
+			Description: summary.Description,
+			Versions:    summary.Versions,
+		})
+	}
+	return reply, nil
+}
+
+// DescribeKhoriumStep resolves the KhoriumStep by name,
+// with its inputs, outputs, the commit resolved to, and the versions of its repository.
+func (g *GRPCServer) DescribeKhoriumStep(ctx context.Context, req *proto.DescribeKhoriumStepRequest) (*proto.DescribeKhoriumStepReply, error) {
+	ks, err := g.store.GetRegisteredKhoriumStep(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	versions, err := g.store.GetKhoriumStepVersions(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &proto.DescribeKhoriumStepReply{
+		Name:        req.GetName(),
+		Description: ks.Description,
+		Commit:      ks.Commit,
+		Digest:      ks.Digest,
+		SignedBy:    ks.SignedBy,
+		Versions:    versions,
+		Inputs:      map[string]*proto.KhoriumStepInput{},
+		Outputs:     map[string]*proto.KhoriumStepOutput{},
+		Main:        ks.Run.Main,
+		Post:        ks.Run.Post,
+	}
+	for name, input := range ks.Inputs {
+		reply.Inputs[name] = &proto.KhoriumStepInput{
+			Description:  input.Description,
+			DefaultValue: input.Default,
+			Required:     input.Required,
+			Type:         input.Type,
+			Options:      input.Options,
+			Pattern:      input.Pattern,
+			Deprecated:   input.Deprecated,
+		}
+	}
+	for name, output := range ks.Outputs {
+		reply.Outputs[name] = &proto.KhoriumStepOutput{Description: output.Description}
+	}
+	for _, step := range ks.Run.Steps {
+		reply.Steps = append(reply.Steps, &proto.CompositeStep{Name: step.Name, Uses: step.Uses, Run: step.Run})
+	}
+	for file := range ks.Files {
+		reply.Files = append(reply.Files, file)
+	}
+	sort.Strings(reply.Files)
+	return reply, nil
+}
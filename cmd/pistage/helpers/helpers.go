@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/secrets"
 	"github.com/projecteru2/pistage/store"
 	"github.com/projecteru2/pistage/store/mysql"
 
@@ -12,7 +13,12 @@ import (
 
 // InitStorage initiates storage, only one storage can be used.
 func InitStorage(config *common.Config) (store.Store, error) {
-	return mysql.NewMySQLStore(&config.Storage, store.NewKhoriumManager(config.Khorium))
+	return mysql.NewMySQLStore(&config.Storage, store.NewKhoriumManager(config.Khorium), store.NewCacheManager(config.Cache))
+}
+
+// InitSecretProvider initiates the secret providers in config, chained in order.
+func InitSecretProvider(config *common.Config) (secrets.SecretProvider, error) {
+	return secrets.NewSecretProvider(config.Secrets)
 }
 
 // SetupLog initiates logrus default logger.
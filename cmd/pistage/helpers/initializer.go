@@ -5,7 +5,11 @@ import (
 
 	"github.com/projecteru2/pistage/common"
 	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/executors/docker"
+	"github.com/projecteru2/pistage/executors/dryrun"
 	"github.com/projecteru2/pistage/executors/eru"
+	"github.com/projecteru2/pistage/executors/kubernetes"
+	"github.com/projecteru2/pistage/executors/plugin"
 	"github.com/projecteru2/pistage/executors/shell"
 	"github.com/projecteru2/pistage/executors/ssh"
 	"github.com/projecteru2/pistage/store"
@@ -33,7 +37,7 @@ func initShell(ctx context.Context, config *common.Config, store store.Store) er
 
 // initSSH initializes ssh executor provider.
 func initSSH(ctx context.Context, config *common.Config, store store.Store) error {
-	sshProvider, err := ssh.NewSSHJobExecutorProvider(config, store)
+	sshProvider, err := ssh.NewSSHJobExecutorProvider(ctx, config, store)
 	if err != nil {
 		return err
 	}
@@ -41,13 +45,57 @@ func initSSH(ctx context.Context, config *common.Config, store store.Store) erro
 	return nil
 }
 
+// initKubernetes initializes kubernetes executor provider.
+func initKubernetes(ctx context.Context, config *common.Config, store store.Store) error {
+	kubernetesProvider, err := kubernetes.NewKubernetesJobExecutorProvider(config, store)
+	if err != nil {
+		return err
+	}
+	executors.RegisterExecutorProvider(kubernetesProvider)
+	return nil
+}
+
+// initDocker initializes docker executor provider.
+func initDocker(ctx context.Context, config *common.Config, store store.Store) error {
+	dockerProvider, err := docker.NewDockerJobExecutorProvider(config, store)
+	if err != nil {
+		return err
+	}
+	executors.RegisterExecutorProvider(dockerProvider)
+	return nil
+}
+
+// initDryRun initializes dryrun executor provider.
+func initDryRun(ctx context.Context, config *common.Config, store store.Store) error {
+	dryrunProvider, err := dryrun.NewDryRunJobExecutorProvider(store)
+	if err != nil {
+		return err
+	}
+	executors.RegisterExecutorProvider(dryrunProvider)
+	return nil
+}
+
+// initPlugin initializes the executor provider of a plugin.
+func initPlugin(ctx context.Context, config common.PluginConfig, store store.Store) error {
+	pluginProvider, err := plugin.NewPluginJobExecutorProvider(ctx, config, store)
+	if err != nil {
+		return err
+	}
+	executors.RegisterExecutorProvider(pluginProvider)
+	return nil
+}
+
 var initializers = map[string]func(context.Context, *common.Config, store.Store) error{
-	"eru":   initEru,
-	"shell": initShell,
-	"ssh":   initSSH,
+	"eru":        initEru,
+	"shell":      initShell,
+	"ssh":        initSSH,
+	"kubernetes": initKubernetes,
+	"docker":     initDocker,
+	"dryrun":     initDryRun,
 }
 
 // InitExecutorProvider initiates and registers executor providers.
+// All the plugins are registered by their names.
 func InitExecutorProvider(ctx context.Context, config *common.Config, store store.Store) error {
 	for _, provider := range config.JobExecutors {
 		f, ok := initializers[provider]
@@ -58,5 +106,10 @@ func InitExecutorProvider(ctx context.Context, config *common.Config, store stor
 			return err
 		}
 	}
+	for _, pluginConfig := range config.Plugins {
+		if err := initPlugin(ctx, pluginConfig, store); err != nil {
+			return err
+		}
+	}
 	return nil
 }
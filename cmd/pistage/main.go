@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/projecteru2/pistage/cmd/pistage/secret"
 	"github.com/projecteru2/pistage/cmd/pistage/server"
 	"github.com/projecteru2/pistage/cmd/pistage/version"
 
@@ -33,6 +34,46 @@ func main() {
 					},
 				},
 			},
+			{
+				Name:  "secrets",
+				Usage: "Manage the encrypted secrets file",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "genkey",
+						Usage:  "Generate a key for the encrypted secrets file",
+						Action: secret.GenerateKey,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "key-file",
+								Usage:    "Path to write the key",
+								Required: true,
+							},
+						},
+					},
+					{
+						Name:   "encrypt",
+						Usage:  "Encrypt a YAML map of secret names to values",
+						Action: secret.Encrypt,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "key-file",
+								Usage:    "Path to the key",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "in",
+								Usage:    "Path to the plaintext YAML",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "out",
+								Usage:    "Path to write the encrypted file",
+								Required: true,
+							},
+						},
+					},
+				},
+			},
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -0,0 +1,45 @@
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projecteru2/pistage/secrets"
+)
+
+// GenerateKey writes a new random key for the encrypted secrets file.
+func GenerateKey(c *cli.Context) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.String("key-file"), []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600)
+}
+
+// Encrypt encrypts a YAML map of secret names to values with the key,
+// the output can be used as the path of file secret provider.
+func Encrypt(c *cli.Context) error {
+	key, err := secrets.ReadKey(c.String("key-file"))
+	if err != nil {
+		return err
+	}
+	plaintext, err := ioutil.ReadFile(c.String("in"))
+	if err != nil {
+		return err
+	}
+	values := map[string]string{}
+	if err := yaml.Unmarshal(plaintext, &values); err != nil {
+		return errors.WithMessagef(err, "%s is not a map of names to values", c.String("in"))
+	}
+
+	sealed, err := secrets.Seal(key, plaintext)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.String("out"), sealed, 0600)
+}
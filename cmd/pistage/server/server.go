@@ -29,6 +29,11 @@ func StartPistage(c *cli.Context) error {
 	}
 	defer store.Close()
 
+	secretProvider, err := helpers.InitSecretProvider(config)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := signalcontext.OnInterrupt()
 	defer cancel()
 	if err := helpers.InitExecutorProvider(ctx, config, store); err != nil {
@@ -40,11 +45,11 @@ func StartPistage(c *cli.Context) error {
 		return err
 	}
 
-	s := stageserver.NewStageServer(config, store)
+	s := stageserver.NewStageServer(config, store, secretProvider)
 	s.Start()
 	logrus.Info("[Stager] started")
 
-	g := grpc.NewGRPCServer(store, s)
+	g := grpc.NewGRPCServer(store, s, config.Spec)
 	go g.Serve(l)
 	logrus.Info("[GRPCServer] started")
 
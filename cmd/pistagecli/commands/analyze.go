@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/apiserver/grpc/proto"
+	"github.com/projecteru2/pistage/common"
+)
+
+func analyze(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("usage: pistagecli analyze <run>")
+	}
+
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.RunAnalytics(c.Context, &proto.RunAnalyticsRequest{RunId: c.Args().First()})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Analytics of %s, run %s (%s)\n", reply.WorkflowIdentifier, reply.RunId, reply.Status)
+	fmt.Printf("queued: %s\n", millis(reply.QueueMillis))
+	fmt.Printf("duration: %s\n", millis(reply.DurationMillis))
+	fmt.Printf("parallelism: %.2f average, %d at most\n", reply.Parallelism, reply.MaxParallelism)
+	fmt.Printf("critical path: %s\n\n", strings.Join(reply.CriticalPath, " -> "))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tSTATUS\tWAIT\tQUEUE\tEXECUTION\tDURATION\tCRITICAL")
+	for _, job := range reply.Jobs {
+		critical := ""
+		if job.Critical {
+			critical = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", job.Name, job.Status,
+			millis(job.WaitMillis), millis(job.QueueMillis), millis(job.ExecutionMillis), millis(job.DurationMillis), critical)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if reply.Optimize != "" {
+		fmt.Printf("optimize: job %s executes longest on the critical path\n", reply.Optimize)
+	}
+	switch reply.Bottleneck {
+	case common.BottleneckStager:
+		fmt.Println("bottleneck: the run waited for a stager worker, consider more stage_server_workers")
+	case common.BottleneckExecutor:
+		fmt.Println("bottleneck: jobs on the critical path waited for executors, consider more executor capacity, e.g. eru nodes")
+	default:
+		fmt.Println("bottleneck: none, the run is bound by executing jobs")
+	}
+	return nil
+}
+
+func millis(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+func AnalyzeCommands() *cli.Command {
+	return &cli.Command{
+		Name:      "analyze",
+		Usage:     "Show the critical path and how jobs of a completed run are scheduled, to find what to optimize",
+		ArgsUsage: "<run>",
+		Action: func(c *cli.Context) error {
+			return analyze(c)
+		},
+	}
+}
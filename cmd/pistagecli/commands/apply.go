@@ -2,7 +2,6 @@ package commands
 
 import (
 	"io"
-	"io/ioutil"
 
 	"github.com/projecteru2/pistage/apiserver/grpc/proto"
 
@@ -11,7 +10,7 @@ import (
 )
 
 func applyOneway(c *cli.Context) error {
-	content, err := ioutil.ReadFile(c.String("file"))
+	content, bundle, err := readSpec(c)
 	if err != nil {
 		return err
 	}
@@ -21,7 +20,7 @@ func applyOneway(c *cli.Context) error {
 		return err
 	}
 
-	reply, err := client.ApplyOneway(c.Context, &proto.ApplyPistageRequest{Content: string(content)})
+	reply, err := client.ApplyOneway(c.Context, &proto.ApplyPistageRequest{Content: content, Bundle: bundle, Inputs: readVars(c)})
 	if err != nil {
 		return err
 	}
@@ -35,7 +34,7 @@ func applyOneway(c *cli.Context) error {
 }
 
 func applyStream(c *cli.Context) error {
-	content, err := ioutil.ReadFile(c.String("file"))
+	content, bundle, err := readSpec(c)
 	if err != nil {
 		return err
 	}
@@ -45,7 +44,7 @@ func applyStream(c *cli.Context) error {
 		return err
 	}
 
-	stream, err := client.ApplyStream(c.Context, &proto.ApplyPistageRequest{Content: string(content)})
+	stream, err := client.ApplyStream(c.Context, &proto.ApplyPistageRequest{Content: content, Bundle: bundle, Inputs: readVars(c)})
 	if err != nil {
 		return err
 	}
@@ -78,7 +77,7 @@ func rollback(c *cli.Context) error {
 }
 
 func rollbackOneway(c *cli.Context) error {
-	content, err := ioutil.ReadFile(c.String("file"))
+	content, bundle, err := readSpec(c)
 	if err != nil {
 		return err
 	}
@@ -88,7 +87,7 @@ func rollbackOneway(c *cli.Context) error {
 		return err
 	}
 
-	reply, err := client.RollbackOneway(c.Context, &proto.RollbackPistageRequest{Content: string(content)})
+	reply, err := client.RollbackOneway(c.Context, &proto.RollbackPistageRequest{Content: content, Bundle: bundle, Inputs: readVars(c)})
 	if err != nil {
 		return err
 	}
@@ -102,7 +101,7 @@ func rollbackOneway(c *cli.Context) error {
 }
 
 func rollbackStream(c *cli.Context) error {
-	content, err := ioutil.ReadFile(c.String("file"))
+	content, bundle, err := readSpec(c)
 	if err != nil {
 		return err
 	}
@@ -112,7 +111,7 @@ func rollbackStream(c *cli.Context) error {
 		return err
 	}
 
-	stream, err := client.RollbackStream(c.Context, &proto.RollbackPistageRequest{Content: string(content)})
+	stream, err := client.RollbackStream(c.Context, &proto.RollbackPistageRequest{Content: content, Bundle: bundle, Inputs: readVars(c)})
 	if err != nil {
 		return err
 	}
@@ -144,6 +143,7 @@ func ApplyCommands() *cli.Command {
 				Value:   "pistage.yml",
 				Usage:   "Pistage yaml description file",
 			},
+			varFlag(),
 			&cli.BoolFlag{
 				Name:  "stream",
 				Value: false,
@@ -167,6 +167,7 @@ func RollbackCommands() *cli.Command {
 				Value:   "pistage.yml",
 				Usage:   "Pistage yaml description file",
 			},
+			varFlag(),
 			&cli.BoolFlag{
 				Name:  "stream",
 				Value: false,
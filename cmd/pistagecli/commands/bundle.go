@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/store"
+)
+
+// localIncludeLoader reads local includes next to spec, and bundles them by their relative paths.
+// Git and template includes are loaded by server, they're empty here.
+type localIncludeLoader struct {
+	dir    string
+	bundle map[string][]byte
+	remote bool
+}
+
+// LoadInclude implements common.IncludeLoader.
+func (l *localIncludeLoader) LoadInclude(ctx context.Context, include common.Include) ([]byte, error) {
+	if include.Local == "" {
+		l.remote = true
+		return []byte("{}"), nil
+	}
+	content, err := ioutil.ReadFile(filepath.Join(l.dir, filepath.FromSlash(include.Local)))
+	if err != nil {
+		return nil, err
+	}
+	l.bundle[include.Local] = content
+	return content, nil
+}
+
+// readSpec reads the spec file, and bundles the specs and KhoriumSteps relative to it,
+// e.g. for step ./steps/deploy, all the files under steps/deploy next to spec
+// are bundled as steps/deploy/<path of file>.
+// If spec includes git or template specs, it's only fully resolved by server,
+// so only the steps known here are bundled, and errors are left to server.
+func readSpec(c *cli.Context) (string, map[string][]byte, error) {
+	return readSpecFile(c, c.String("file"), false)
+}
+
+// readSpecFile reads the spec file like readSpec,
+// if lenient, errors of spec are always left to server.
+func readSpecFile(c *cli.Context, file string, lenient bool) (string, map[string][]byte, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	bundle := map[string][]byte{}
+	loader := &localIncludeLoader{dir: filepath.Dir(file), bundle: bundle}
+	pistage, err := common.FromSpecWithLoader(c.Context, content, loader)
+	if err != nil {
+		if !loader.remote && !lenient {
+			return "", nil, err
+		}
+		return string(content), bundle, nil
+	}
+
+	for _, name := range pistage.KhoriumStepNames() {
+		if !store.IsBundledKhoriumStep(name) {
+			continue
+		}
+
+		dir := filepath.Join(filepath.Dir(file), name)
+		prefix := path.Clean(name)
+		traverse := func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			c, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			bundle[path.Join(prefix, filepath.ToSlash(rel))] = c
+			return nil
+		}
+		if err := filepath.Walk(dir, traverse); err != nil {
+			return "", nil, err
+		}
+	}
+	return string(content), bundle, nil
+}
@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/apiserver/grpc/proto"
+	"github.com/projecteru2/pistage/common"
+)
+
+// colors of nodes by the status of jobs, in dot and mermaid.
+var graphColors = map[common.RunStatus]string{
+	common.RunStatusPending:  "white",
+	common.RunStatusRunning:  "lightblue",
+	common.RunStatusFinished: "palegreen",
+	common.RunStatusFailed:   "salmon",
+	common.RunStatusCanceled: "lightgrey",
+}
+
+func graph(c *cli.Context) error {
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GraphPistageRequest{RunId: c.String("run")}
+	if req.RunId == "" {
+		if req.Content, req.Bundle, err = readSpec(c); err != nil {
+			return err
+		}
+		req.Inputs = readVars(c)
+	}
+	reply, err := client.Graph(c.Context, req)
+	if err != nil {
+		return err
+	}
+
+	jobs := make([]*common.GraphJob, 0, len(reply.Jobs))
+	for _, job := range reply.Jobs {
+		jobs = append(jobs, &common.GraphJob{
+			Name:      job.Name,
+			DependsOn: job.DependsOn,
+			Status:    common.RunStatus(job.Status),
+			Duration:  millis(job.DurationMillis),
+		})
+	}
+	g, err := common.NewGraph(jobs)
+	if err != nil {
+		return err
+	}
+	// the critical path computed by server wins, it's the same unless server is different.
+	g.CriticalPath = reply.CriticalPath
+
+	switch format := c.String("format"); format {
+	case "dot":
+		fmt.Print(graphDot(reply.WorkflowIdentifier, g))
+	case "mermaid":
+		fmt.Print(graphMermaid(g))
+	case "ascii":
+		fmt.Print(graphASCII(reply, g))
+	default:
+		return errors.Errorf("unknown format: %s", format)
+	}
+	return nil
+}
+
+// graphLabel is the name of job, with its duration if it has run.
+func graphLabel(job *common.GraphJob, sep string) string {
+	if job.Duration == 0 {
+		return job.Name
+	}
+	return job.Name + sep + job.Duration.String()
+}
+
+// graphCritical returns the jobs and edges on the critical path of g, edges are keyed by from->to.
+func graphCritical(g *common.Graph) (map[string]bool, map[string]bool) {
+	nodes, edges := map[string]bool{}, map[string]bool{}
+	for i, name := range g.CriticalPath {
+		nodes[name] = true
+		if i > 0 {
+			edges[g.CriticalPath[i-1]+"->"+name] = true
+		}
+	}
+	return nodes, edges
+}
+
+func graphDot(name string, g *common.Graph) string {
+	nodes, edges := graphCritical(g)
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "digraph %q {\n", name)
+	fmt.Fprintln(b, "  rankdir=LR;")
+	fmt.Fprintln(b, "  node [shape=box, style=\"rounded,filled\", fillcolor=white];")
+	for _, stage := range g.Stages {
+		for _, jobName := range stage {
+			job := g.Jobs[jobName]
+			attrs := []string{fmt.Sprintf("label=%q", graphLabel(job, "\n"))}
+			if color, ok := graphColors[job.Status]; ok {
+				attrs = append(attrs, "fillcolor="+color)
+			}
+			if nodes[jobName] {
+				attrs = append(attrs, "color=red", "penwidth=2")
+			}
+			fmt.Fprintf(b, "  %q [%s];\n", jobName, strings.Join(attrs, ", "))
+		}
+	}
+	for _, stage := range g.Stages {
+		for _, jobName := range stage {
+			for _, dependency := range g.Jobs[jobName].DependsOn {
+				attrs := ""
+				if edges[dependency+"->"+jobName] {
+					attrs = " [color=red, penwidth=2]"
+				}
+				fmt.Fprintf(b, "  %q -> %q%s;\n", dependency, jobName, attrs)
+			}
+		}
+	}
+	fmt.Fprintln(b, "}")
+	return b.String()
+}
+
+func graphMermaid(g *common.Graph) string {
+	nodes, edges := graphCritical(g)
+	// names of jobs may not be valid ids of mermaid.
+	ids := map[string]string{}
+	b := &strings.Builder{}
+	fmt.Fprintln(b, "graph LR")
+	for _, stage := range g.Stages {
+		for _, jobName := range stage {
+			job := g.Jobs[jobName]
+			ids[jobName] = fmt.Sprintf("j%d", len(ids))
+			fmt.Fprintf(b, "  %s[\"%s\"]\n", ids[jobName], strings.ReplaceAll(graphLabel(job, "<br/>"), `"`, "#quot;"))
+			if _, ok := graphColors[job.Status]; ok {
+				fmt.Fprintf(b, "  class %s %s\n", ids[jobName], job.Status)
+			}
+			if nodes[jobName] {
+				fmt.Fprintf(b, "  class %s critical\n", ids[jobName])
+			}
+		}
+	}
+	var criticalLinks []string
+	link := 0
+	for _, stage := range g.Stages {
+		for _, jobName := range stage {
+			for _, dependency := range g.Jobs[jobName].DependsOn {
+				fmt.Fprintf(b, "  %s --> %s\n", ids[dependency], ids[jobName])
+				if edges[dependency+"->"+jobName] {
+					criticalLinks = append(criticalLinks, fmt.Sprint(link))
+				}
+				link++
+			}
+		}
+	}
+	for _, status := range []common.RunStatus{
+		common.RunStatusPending, common.RunStatusRunning, common.RunStatusFinished,
+		common.RunStatusFailed, common.RunStatusCanceled,
+	} {
+		fmt.Fprintf(b, "  classDef %s fill:%s\n", status, graphColors[status])
+	}
+	fmt.Fprintln(b, "  classDef critical stroke:red,stroke-width:2px")
+	if len(criticalLinks) > 0 {
+		fmt.Fprintf(b, "  linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(criticalLinks, ","))
+	}
+	return b.String()
+}
+
+func graphASCII(reply *proto.GraphPistageReply, g *common.Graph) string {
+	nodes, _ := graphCritical(g)
+	b := &strings.Builder{}
+	if reply.RunId != "" {
+		fmt.Fprintf(b, "Graph of %s, run %s (%s)\n", reply.WorkflowIdentifier, reply.RunId, reply.Status)
+	} else {
+		fmt.Fprintf(b, "Graph of %s\n", reply.WorkflowIdentifier)
+	}
+	for i, stage := range g.Stages {
+		fmt.Fprintf(b, "stage %d:\n", i+1)
+		for _, jobName := range stage {
+			job := g.Jobs[jobName]
+			mark := " "
+			if nodes[jobName] {
+				mark = "*"
+			}
+			fmt.Fprintf(b, "  %s %s", mark, jobName)
+			if job.Status != "" {
+				fmt.Fprintf(b, " [%s]", job.Status)
+			}
+			if job.Duration != 0 {
+				fmt.Fprintf(b, " %s", job.Duration)
+			}
+			if len(job.DependsOn) > 0 {
+				fmt.Fprintf(b, " <- %s", strings.Join(job.DependsOn, ", "))
+			}
+			fmt.Fprintln(b)
+		}
+	}
+	fmt.Fprintf(b, "critical path: %s", strings.Join(g.CriticalPath, " -> "))
+	if d := g.Duration(); d != 0 {
+		fmt.Fprintf(b, " (%s)", d)
+	}
+	fmt.Fprintln(b)
+	return b.String()
+}
+
+func GraphCommands() *cli.Command {
+	return &cli.Command{
+		Name:  "graph",
+		Usage: "Render the dependency graph of jobs of a Pistage, or of a past run",
+		Action: func(c *cli.Context) error {
+			return graph(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Value:   "pistage.yml",
+				Usage:   "Pistage yaml description file",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "ascii",
+				Usage: "Format of graph, dot, mermaid or ascii",
+			},
+			&cli.StringFlag{
+				Name:  "run",
+				Value: "",
+				Usage: "If set, will render the graph of this run, colored by the statuses of jobs",
+			},
+			varFlag(),
+		},
+	}
+}
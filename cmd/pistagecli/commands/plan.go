@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/apiserver/grpc/proto"
+)
+
+func plan(c *cli.Context) error {
+	content, bundle, err := readSpec(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.Plan(c.Context, &proto.PlanPistageRequest{Content: content, Bundle: bundle, Inputs: readVars(c)})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Plan of %s\n", reply.WorkflowIdentifier)
+	for i, stage := range reply.Stages {
+		fmt.Printf("stage %d: %s\n", i+1, strings.Join(stage.Jobs, ", "))
+	}
+
+	rollback := c.Bool("rollback")
+	for _, job := range reply.Jobs {
+		fmt.Printf("\njob %s", job.Name)
+		if len(job.DependsOn) > 0 {
+			fmt.Printf(" (depends on %s)", strings.Join(job.DependsOn, ", "))
+		}
+		fmt.Println()
+
+		steps := job.Steps
+		if rollback {
+			steps = job.RollbackSteps
+		}
+		printStepPlans(steps, c.Bool("env"))
+	}
+	return nil
+}
+
+func printStepPlans(steps []*proto.StepPlan, env bool) {
+	for _, step := range steps {
+		if step.Uses != "" {
+			fmt.Printf("  step %s uses %s\n", step.Name, step.Uses)
+		} else {
+			fmt.Printf("  step %s\n", step.Name)
+		}
+		for _, name := range sortedKeys(step.Inputs) {
+			fmt.Printf("    with %s=%s\n", name, step.Inputs[name])
+		}
+		if env {
+			for _, name := range sortedKeys(step.Environment) {
+				fmt.Printf("    env %s=%s\n", name, step.Environment[name])
+			}
+		}
+		for _, cmd := range step.Commands {
+			fmt.Printf("    $ %s\n", cmd)
+		}
+		for _, cmd := range step.OnError {
+			fmt.Printf("    on error $ %s\n", cmd)
+		}
+		if step.Post != "" {
+			fmt.Printf("    post $ %s\n", step.Post)
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func PlanCommands() *cli.Command {
+	return &cli.Command{
+		Name:  "plan",
+		Usage: "Show what would be executed by a Pistage, without executing anything",
+		Action: func(c *cli.Context) error {
+			return plan(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Value:   "pistage.yml",
+				Usage:   "Pistage yaml description file",
+			},
+			varFlag(),
+			&cli.BoolFlag{
+				Name:  "rollback",
+				Value: false,
+				Usage: "If set, will show rollback steps instead of steps",
+			},
+			&cli.BoolFlag{
+				Name:  "env",
+				Value: false,
+				Usage: "If set, will also show the environment of each step",
+			},
+		},
+	}
+}
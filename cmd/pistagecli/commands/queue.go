@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/apiserver/grpc/proto"
+)
+
+func queue(c *cli.Context) error {
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.GetQueue(c.Context, &proto.GetQueueRequest{WorkflowIdentifier: c.String("identifier")})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("running: %d pistages, %d jobs", reply.RunningPistages, reply.RunningJobs)
+	if reply.MaxConcurrentJobs > 0 {
+		fmt.Printf(" of at most %d", reply.MaxConcurrentJobs)
+	}
+	fmt.Print("\n\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POSITION\tPISTAGE\tJOB\tTYPE\tPRIORITY\tWAITING")
+	for _, entries := range [][]*proto.QueueEntry{reply.Pistages, reply.Jobs} {
+		for _, entry := range entries {
+			job := entry.Job
+			if job == "" {
+				job = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", entry.Position, entry.WorkflowIdentifier, job,
+				entry.WorkflowType, entry.Priority, millis(entry.WaitingMillis))
+		}
+	}
+	return w.Flush()
+}
+
+func QueueCommands() *cli.Command {
+	return &cli.Command{
+		Name:  "queue",
+		Usage: "Show the pistages waiting for a runner and the jobs waiting to start, in the order they'll be scheduled",
+		Action: func(c *cli.Context) error {
+			return queue(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "identifier",
+				Value: "",
+				Usage: "If set, will only show the pistage with this workflow identifier and its jobs",
+			},
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/apiserver/grpc/proto"
+)
+
+func schema(c *cli.Context) error {
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.GetSchema(c.Context, &proto.GetSchemaRequest{Kind: c.String("kind")})
+	if err != nil {
+		return err
+	}
+
+	if output := c.String("output"); output != "" {
+		return ioutil.WriteFile(output, []byte(reply.Schema+"\n"), 0644)
+	}
+	fmt.Println(reply.Schema)
+	return nil
+}
+
+func SchemaCommands() *cli.Command {
+	return &cli.Command{
+		Name:  "schema",
+		Usage: "Print the JSON Schema of Pistage spec or khoriumstep.yml, for editors to complete and validate",
+		Action: func(c *cli.Context) error {
+			return schema(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "kind",
+				Value: "pistage",
+				Usage: "Kind of schema, pistage or khoriumstep",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "If set, will write the schema to this file instead of stdout",
+			},
+		},
+	}
+}
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/apiserver/grpc/proto"
+)
+
+func listSteps(c *cli.Context) error {
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.ListKhoriumSteps(c.Context, &proto.ListKhoriumStepsRequest{Prefix: c.String("prefix")})
+	if err != nil {
+		return err
+	}
+
+	for _, step := range reply.Steps {
+		fmt.Println(step.Name)
+		if step.Description != "" {
+			fmt.Printf("  %s\n", step.Description)
+		}
+		if len(step.Versions) > 0 {
+			fmt.Printf("  versions: %s\n", strings.Join(step.Versions, ", "))
+		}
+	}
+	return nil
+}
+
+func describeStep(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("usage: pistagecli step describe <name>")
+	}
+
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.DescribeKhoriumStep(c.Context, &proto.DescribeKhoriumStepRequest{Name: c.Args().First()})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(reply.Name)
+	if reply.Description != "" {
+		fmt.Printf("  %s\n", reply.Description)
+	}
+	fmt.Printf("commit: %s\n", reply.Commit)
+	fmt.Printf("digest: %s\n", reply.Digest)
+	if reply.SignedBy != "" {
+		fmt.Printf("signed by: %s\n", reply.SignedBy)
+	}
+	if len(reply.Versions) > 0 {
+		fmt.Printf("versions: %s\n", strings.Join(reply.Versions, ", "))
+	}
+
+	if len(reply.Inputs) > 0 {
+		fmt.Println("\ninputs:")
+	}
+	for _, name := range sortedInputNames(reply.Inputs) {
+		input := reply.Inputs[name]
+		fmt.Printf("  %s (%s)\n", name, inputDetails(input))
+		if input.Description != "" {
+			fmt.Printf("    %s\n", input.Description)
+		}
+		if input.Deprecated != "" {
+			fmt.Printf("    deprecated: %s\n", input.Deprecated)
+		}
+	}
+
+	if len(reply.Outputs) > 0 {
+		fmt.Println("\noutputs:")
+	}
+	outputs := make([]string, 0, len(reply.Outputs))
+	for name := range reply.Outputs {
+		outputs = append(outputs, name)
+	}
+	sort.Strings(outputs)
+	for _, name := range outputs {
+		fmt.Printf("  %s\n", name)
+		if description := reply.Outputs[name].Description; description != "" {
+			fmt.Printf("    %s\n", description)
+		}
+	}
+
+	fmt.Println("\nrun:")
+	if reply.Main != "" {
+		fmt.Printf("  main $ %s\n", reply.Main)
+	}
+	if reply.Post != "" {
+		fmt.Printf("  post $ %s\n", reply.Post)
+	}
+	for _, step := range reply.Steps {
+		if step.Uses != "" {
+			fmt.Printf("  step %s uses %s\n", step.Name, step.Uses)
+			continue
+		}
+		fmt.Printf("  step %s\n", step.Name)
+		for _, cmd := range step.Run {
+			fmt.Printf("    $ %s\n", cmd)
+		}
+	}
+
+	if c.Bool("files") {
+		fmt.Println("\nfiles:")
+		for _, file := range reply.Files {
+			fmt.Printf("  %s\n", file)
+		}
+	}
+	return nil
+}
+
+func sortedInputNames(inputs map[string]*proto.KhoriumStepInput) []string {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// inputDetails describes type, options, pattern and default of input in one line.
+func inputDetails(input *proto.KhoriumStepInput) string {
+	details := []string{"string"}
+	if input.Type != "" {
+		details[0] = input.Type
+	}
+	if len(input.Options) > 0 {
+		details = append(details, "one of "+strings.Join(input.Options, "|"))
+	}
+	if input.Pattern != "" {
+		details = append(details, "matches "+input.Pattern)
+	}
+	if input.Required {
+		details = append(details, "required")
+	}
+	if input.DefaultValue != "" {
+		details = append(details, "default "+input.DefaultValue)
+	}
+	return strings.Join(details, ", ")
+}
+
+func StepCommands() *cli.Command {
+	return &cli.Command{
+		Name:  "step",
+		Usage: "Discover KhoriumSteps",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List KhoriumSteps known by pistage",
+				Action: func(c *cli.Context) error {
+					return listSteps(c)
+				},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "prefix",
+						Usage: "Only list KhoriumSteps with names starting with prefix",
+					},
+				},
+			},
+			{
+				Name:      "describe",
+				Usage:     "Show the inputs, outputs and versions of a KhoriumStep",
+				ArgsUsage: "<name>",
+				Action: func(c *cli.Context) error {
+					return describeStep(c)
+				},
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "files",
+						Value: false,
+						Usage: "If set, will also list the files of KhoriumStep",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/projecteru2/pistage/apiserver/grpc/proto"
+)
+
+func validate(c *cli.Context) error {
+	file := c.String("file")
+	content, bundle, err := readSpecFile(c, file, true)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.Validate(c.Context, &proto.ValidatePistageRequest{Content: content, Bundle: bundle})
+	if err != nil {
+		return err
+	}
+	if len(reply.Errors) == 0 {
+		fmt.Printf("%s is valid\n", file)
+		return nil
+	}
+
+	for _, e := range reply.Errors {
+		location := file
+		if e.File != "" {
+			location = e.File
+		}
+		if e.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", location, e.Line, e.Column)
+		}
+		fmt.Printf("%s: %s\n", location, e.Message)
+	}
+	return fmt.Errorf("%d problems found in %s", len(reply.Errors), file)
+}
+
+func ValidateCommands() *cli.Command {
+	return &cli.Command{
+		Name:  "validate",
+		Usage: "Check a Pistage, and report all the problems with their locations",
+		Action: func(c *cli.Context) error {
+			return validate(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Value:   "pistage.yml",
+				Usage:   "Pistage yaml description file",
+			},
+		},
+	}
+}
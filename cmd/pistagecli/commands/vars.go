@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// vars holds the inputs given by --var name=value,
+// it's not a StringSliceFlag since values may contain commas, e.g. lists.
+type vars map[string]string
+
+// Set implements cli.Generic.
+func (v vars) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("%q is not name=value", value)
+	}
+	v[parts[0]] = parts[1]
+	return nil
+}
+
+// String implements cli.Generic.
+func (v vars) String() string {
+	var pairs []string
+	for _, name := range sortedKeys(v) {
+		pairs = append(pairs, name+"="+v[name])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func varFlag() cli.Flag {
+	return &cli.GenericFlag{
+		Name:  "var",
+		Value: vars{},
+		Usage: "Input of pistage as name=value, can be given multiple times",
+	}
+}
+
+// readVars returns the inputs given by --var.
+func readVars(c *cli.Context) map[string]string {
+	v, _ := c.Generic("var").(vars)
+	return v
+}
@@ -23,6 +23,13 @@ func main() {
 		Commands: []*cli.Command{
 			commands.ApplyCommands(),
 			commands.RollbackCommands(),
+			commands.PlanCommands(),
+			commands.GraphCommands(),
+			commands.AnalyzeCommands(),
+			commands.QueueCommands(),
+			commands.ValidateCommands(),
+			commands.SchemaCommands(),
+			commands.StepCommands(),
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
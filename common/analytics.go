@@ -0,0 +1,234 @@
+package common
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrorRunNotCompleted is returned when analyzing a run still pending or running.
+var ErrorRunNotCompleted = errors.New("Run not completed")
+
+// Bottlenecks of a run, see RunAnalytics.
+const (
+	// BottleneckStager means the run waited long for a stager worker, StageServerWorkers may be too few.
+	BottleneckStager = "stager"
+	// BottleneckExecutor means jobs on critical path waited long for their executors,
+	// e.g. workloads can't be created on eru in time.
+	BottleneckExecutor = "executor"
+)
+
+// bottleneckRatio is how much of the elapsed time of a run waiting must take to be a bottleneck.
+const bottleneckRatio = 0.1
+
+// JobAnalytics is how a job of a run is scheduled and executed.
+type JobAnalytics struct {
+	Name   string
+	Status RunStatus
+
+	// Wait is from the job is ready, when all its dependencies finished
+	// or the run started for jobs without dependencies, to it's started.
+	Wait time.Duration
+
+	// Queue is from the job is started to its executor is prepared.
+	Queue time.Duration
+
+	// Execution is from the executor is prepared to the job ends.
+	Execution time.Duration
+
+	// Duration is from the job is started to it ends, Queue and Execution included.
+	Duration time.Duration
+
+	// Critical tells if the job is on the critical path.
+	Critical bool
+}
+
+// RunAnalytics is how a completed run is scheduled and executed.
+type RunAnalytics struct {
+	// Queue is from the run is applied to it's started by a stager worker.
+	Queue time.Duration
+
+	// Duration is from the run is started to it ends.
+	Duration time.Duration
+
+	// Jobs are the jobs run, in the order of stages, see JobDependencies.
+	Jobs []*JobAnalytics
+
+	// CriticalPath is the chain of jobs that ended last, each one is the dependency ended last
+	// of the next one, in the order they're executed. Shortening any job not on it doesn't help.
+	CriticalPath []string
+
+	// Parallelism is the average number of jobs running at the same time,
+	// from the first job started to the last one ended, MaxParallelism is the most.
+	Parallelism    float64
+	MaxParallelism int
+
+	// Optimize is the job on critical path executing longest, the one to optimize first.
+	Optimize string
+
+	// Bottleneck is BottleneckStager or BottleneckExecutor if waiting for them takes
+	// a considerable part of the run, or empty if the run is bound by executing jobs.
+	Bottleneck string
+}
+
+// Analyze computes how run of p is scheduled and executed from the times of its jobRuns,
+// p must be the snapshot the run is created from.
+func (p *Pistage) Analyze(run *Run, jobRuns []*JobRun) (*RunAnalytics, error) {
+	if run.Status == RunStatusPending || run.Status == RunStatusRunning {
+		return nil, errors.WithMessagef(ErrorRunNotCompleted, "run: %s, status: %s", run.ID, run.Status)
+	}
+
+	graph, err := p.Graph(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := map[string]*JobRun{}
+	for _, jobRun := range jobRuns {
+		runs[jobRun.JobName] = jobRun
+	}
+
+	a := &RunAnalytics{
+		Queue:    millisBetween(run.Queued, run.Start),
+		Duration: millisBetween(run.Start, run.End),
+	}
+	jobs := map[string]*JobAnalytics{}
+	for _, stage := range graph.Stages {
+		for _, name := range stage {
+			jobRun, ok := runs[name]
+			if !ok {
+				continue
+			}
+
+			// jobs without dependencies are ready when the run starts.
+			ready := run.Start
+			for _, dependency := range graph.Jobs[name].DependsOn {
+				if dr, ok := runs[dependency]; ok && dr.End > ready {
+					ready = dr.End
+				}
+			}
+
+			prepared := jobRun.Prepared
+			if prepared == 0 {
+				prepared = jobRun.Start
+			}
+			job := &JobAnalytics{
+				Name:      name,
+				Status:    jobRun.Status,
+				Wait:      millisBetween(ready, jobRun.Start),
+				Queue:     millisBetween(jobRun.Start, prepared),
+				Execution: millisBetween(prepared, jobRun.End),
+				Duration:  millisBetween(jobRun.Start, jobRun.End),
+			}
+			jobs[name] = job
+			a.Jobs = append(a.Jobs, job)
+		}
+	}
+
+	a.CriticalPath = criticalPathOfRuns(graph, runs)
+	var executorQueue, longest time.Duration
+	for _, name := range a.CriticalPath {
+		job := jobs[name]
+		job.Critical = true
+		executorQueue += job.Queue
+		if job.Execution > longest || a.Optimize == "" {
+			a.Optimize, longest = name, job.Execution
+		}
+	}
+
+	a.Parallelism, a.MaxParallelism = parallelism(jobRuns)
+
+	elapsed := a.Queue + a.Duration
+	switch {
+	case a.Queue > 0 && a.Queue >= executorQueue && float64(a.Queue) >= float64(elapsed)*bottleneckRatio:
+		a.Bottleneck = BottleneckStager
+	case executorQueue > a.Queue && float64(executorQueue) >= float64(elapsed)*bottleneckRatio:
+		a.Bottleneck = BottleneckExecutor
+	}
+	return a, nil
+}
+
+// criticalPathOfRuns walks back from the job ended last, through the dependencies ended last.
+func criticalPathOfRuns(graph *Graph, runs map[string]*JobRun) []string {
+	var last string
+	for name, jobRun := range runs {
+		if _, ok := graph.Jobs[name]; !ok {
+			continue
+		}
+		if last == "" || jobRun.End > runs[last].End || (jobRun.End == runs[last].End && name < last) {
+			last = name
+		}
+	}
+
+	var path []string
+	for name := last; name != ""; {
+		path = append([]string{name}, path...)
+		previous := ""
+		for _, dependency := range graph.Jobs[name].DependsOn {
+			dr, ok := runs[dependency]
+			if ok && (previous == "" || dr.End > runs[previous].End) {
+				previous = dependency
+			}
+		}
+		name = previous
+	}
+	return path
+}
+
+// parallelism returns the average and the most number of jobRuns running at the same time.
+func parallelism(jobRuns []*JobRun) (float64, int) {
+	type event struct {
+		at    int64
+		delta int
+	}
+	var (
+		events      []event
+		busy        int64
+		first, last int64
+	)
+	for _, jobRun := range jobRuns {
+		if jobRun.Start == 0 || jobRun.End < jobRun.Start {
+			continue
+		}
+		events = append(events, event{jobRun.Start, 1}, event{jobRun.End, -1})
+		busy += jobRun.End - jobRun.Start
+		if first == 0 || jobRun.Start < first {
+			first = jobRun.Start
+		}
+		if jobRun.End > last {
+			last = jobRun.End
+		}
+	}
+	if len(events) == 0 {
+		return 0, 0
+	}
+
+	// jobs ending are counted before jobs starting at the same time.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at != events[j].at {
+			return events[i].at < events[j].at
+		}
+		return events[i].delta < events[j].delta
+	})
+	running, most := 0, 0
+	for _, e := range events {
+		running += e.delta
+		if running > most {
+			most = running
+		}
+	}
+
+	if last == first {
+		return float64(most), most
+	}
+	return float64(busy) / float64(last-first), most
+}
+
+// millisBetween returns the duration from start to end in epoch millis, 0 if either is unknown.
+func millisBetween(start, end int64) time.Duration {
+	if start == 0 || end < start {
+		return 0
+	}
+	return time.Duration(end-start) * time.Millisecond
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPistageAnalyze(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := FromSpec([]byte(`
+jobs:
+  checkout:
+    steps: [{name: checkout, run: [git pull]}]
+  lint:
+    depends_on: [checkout]
+    steps: [{name: lint, run: [make lint]}]
+  test:
+    depends_on: [checkout]
+    steps: [{name: test, run: [make test]}]
+  deploy:
+    depends_on: [lint, test]
+    steps: [{name: deploy, run: [make deploy]}]
+`))
+	assert.NoError(err)
+
+	run := &Run{ID: "1", Status: RunStatusFinished, Queued: 1000, Start: 2000, End: 100000}
+	jobRuns := []*JobRun{
+		{JobName: "checkout", Status: RunStatusFinished, Start: 3000, Prepared: 4000, End: 10000},
+		{JobName: "lint", Status: RunStatusFinished, Start: 10000, Prepared: 11000, End: 20000},
+		{JobName: "test", Status: RunStatusFinished, Start: 10000, Prepared: 40000, End: 90000},
+		{JobName: "deploy", Status: RunStatusFinished, Start: 91000, Prepared: 92000, End: 99000},
+	}
+
+	a, err := p.Analyze(run, jobRuns)
+	assert.NoError(err)
+	assert.Equal(time.Second, a.Queue)
+	assert.Equal(98*time.Second, a.Duration)
+	assert.Equal([]string{"checkout", "test", "deploy"}, a.CriticalPath)
+	assert.Equal("test", a.Optimize)
+	assert.Equal(BottleneckExecutor, a.Bottleneck)
+	assert.Equal(2, a.MaxParallelism)
+	assert.InDelta(float64(7+10+80+8)/96, a.Parallelism, 0.001)
+
+	names := []string{}
+	for _, job := range a.Jobs {
+		names = append(names, job.Name)
+	}
+	assert.Equal([]string{"checkout", "lint", "test", "deploy"}, names)
+	deploy := a.Jobs[3]
+	assert.Equal(time.Second, deploy.Wait)
+	assert.Equal(time.Second, deploy.Queue)
+	assert.Equal(7*time.Second, deploy.Execution)
+	assert.Equal(8*time.Second, deploy.Duration)
+	assert.True(deploy.Critical)
+	assert.False(a.Jobs[1].Critical)
+
+	// bound by executing jobs.
+	jobRuns[2].Prepared = 10000
+	a, err = p.Analyze(run, jobRuns)
+	assert.NoError(err)
+	assert.Equal("", a.Bottleneck)
+
+	// waiting for a stager worker.
+	a, err = p.Analyze(
+		&Run{ID: "2", Status: RunStatusFailed, Queued: 1000, Start: 50000, End: 60000},
+		[]*JobRun{{JobName: "checkout", Status: RunStatusFailed, Start: 50000, Prepared: 51000, End: 60000}},
+	)
+	assert.NoError(err)
+	assert.Equal(BottleneckStager, a.Bottleneck)
+	assert.Equal([]string{"checkout"}, a.CriticalPath)
+	assert.Equal(1.0, a.Parallelism)
+
+	_, err = p.Analyze(&Run{ID: "2", Status: RunStatusRunning}, nil)
+	assert.ErrorIs(err, ErrorRunNotCompleted)
+}
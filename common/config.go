@@ -15,11 +15,22 @@ type Config struct {
 
 	DefaultJobExecutor           string `yaml:"default_job_executor" default:"eru"`
 	DefaultJobExecuteTimeoutSecs int    `yaml:"default_job_execute_timeout" default:"1200"`
+	// DefaultJobCleanupTimeoutSecs limits the cleanup of each job, it's not counted in the execute timeout,
+	// so workloads are cleaned up even if the run times out or is canceled.
+	DefaultJobCleanupTimeoutSecs int `yaml:"default_job_cleanup_timeout" default:"300"`
 
-	Eru     EruConfig           `yaml:"eru"`
-	SSH     SSHConfig           `yaml:"ssh"`
-	Storage SQLDataSourceConfig `yaml:"storage"`
-	Khorium KhoriumConfig       `yaml:"khorium"`
+	Eru        EruConfig           `yaml:"eru"`
+	SSH        SSHConfig           `yaml:"ssh"`
+	Shell      ShellConfig         `yaml:"shell"`
+	Kubernetes KubernetesConfig    `yaml:"kubernetes"`
+	Docker     DockerConfig        `yaml:"docker"`
+	Storage    SQLDataSourceConfig `yaml:"storage"`
+	Khorium    KhoriumConfig       `yaml:"khorium"`
+	Cache      CacheConfig         `yaml:"cache"`
+	Secrets    SecretsConfig       `yaml:"secrets"`
+	Spec       SpecConfig          `yaml:"spec"`
+	Scheduler  SchedulerConfig     `yaml:"scheduler"`
+	Plugins    []PluginConfig      `yaml:"plugins"`
 }
 
 type EruConfig struct {
@@ -32,17 +43,294 @@ type EruConfig struct {
 	DefaultJobImage   string `yaml:"default_job_image"`
 	DefaultUser       string `yaml:"default_user" default:"root"`
 	DefaultNetwork    string `yaml:"default_network" default:"host"`
+
+	// ServiceReadyTimeoutSecs is how long to wait for services of a job to be ready.
+	ServiceReadyTimeoutSecs int `yaml:"service_ready_timeout" default:"120"`
 }
 
+// SSHConfig is the config for ssh executor.
+// SSHAuthConfig and MaxJobsPerHost are the defaults for all hosts.
+// Address is kept for a single host setup, it's used as a host without labels.
 type SSHConfig struct {
-	User       string `yaml:"user"`
-	PrivateKey string `yaml:"private_key"`
-	Address    string `yaml:"address"`
+	SSHAuthConfig `yaml:",inline"`
+
+	Address   string              `yaml:"address"`
+	Hosts     []SSHHostConfig     `yaml:"hosts"`
+	ProxyJump []SSHJumpHostConfig `yaml:"proxy_jump"`
+
+	// Strategy is how to select a host among all matched hosts,
+	// can be least_loaded or round_robin.
+	Strategy            string `yaml:"strategy" default:"least_loaded"`
+	MaxJobsPerHost      int    `yaml:"max_jobs_per_host"`
+	MaxIdleConnsPerHost int    `yaml:"max_idle_conns_per_host" default:"2"`
 }
 
+// SSHAuthConfig is how to login a host and how to verify it.
+// Auth methods are tried in the order of agent, private key, password, and keyboard-interactive,
+// keyboard-interactive answers all the questions with Password.
+// HostKeyPolicy can be:
+//   - strict: host key must be found in KnownHosts.
+//   - tofu: trust on first use, unknown host key is appended to KnownHosts.
+//   - insecure: host key is not verified.
+//
+// A mismatched host key is always rejected unless the policy is insecure.
+// UseAgent is a pointer, so a host can turn off the agent used by default with an explicit false.
+type SSHAuthConfig struct {
+	User          string `yaml:"user"`
+	PrivateKey    string `yaml:"private_key"`
+	Passphrase    string `yaml:"passphrase"`
+	Password      string `yaml:"password"`
+	UseAgent      *bool  `yaml:"use_agent"`
+	KnownHosts    string `yaml:"known_hosts" default:"~/.ssh/known_hosts"`
+	HostKeyPolicy string `yaml:"host_key_policy" default:"strict"`
+}
+
+// WithDefaults returns the auth config with empty fields filled by d.
+func (a SSHAuthConfig) WithDefaults(d SSHAuthConfig) SSHAuthConfig {
+	if a.User == "" {
+		a.User = d.User
+	}
+	if a.PrivateKey == "" {
+		a.PrivateKey = d.PrivateKey
+		a.Passphrase = d.Passphrase
+	}
+	if a.Password == "" {
+		a.Password = d.Password
+	}
+	if a.UseAgent == nil {
+		a.UseAgent = d.UseAgent
+	}
+	if a.KnownHosts == "" {
+		a.KnownHosts = d.KnownHosts
+	}
+	if a.HostKeyPolicy == "" {
+		a.HostKeyPolicy = d.HostKeyPolicy
+	}
+	return a
+}
+
+// SSHHostConfig is the config for one ssh host.
+// Empty auth fields and ProxyJump fall back to the ones in SSHConfig,
+// MaxJobs is the max number of jobs running on this host at the same time,
+// 0 means no limit.
+type SSHHostConfig struct {
+	SSHAuthConfig `yaml:",inline"`
+
+	Address   string              `yaml:"address"`
+	Labels    []string            `yaml:"labels"`
+	MaxJobs   int                 `yaml:"max_jobs"`
+	ProxyJump []SSHJumpHostConfig `yaml:"proxy_jump"`
+}
+
+// SSHJumpHostConfig is a bastion host to jump through,
+// jump hosts are connected in order, the last one connects the target host.
+// Empty auth fields fall back to the ones of the target host.
+type SSHJumpHostConfig struct {
+	SSHAuthConfig `yaml:",inline"`
+
+	Address string `yaml:"address"`
+}
+
+// GetHosts returns all hosts, with the defaults filled.
+func (c SSHConfig) GetHosts() []SSHHostConfig {
+	hosts := c.Hosts
+	if c.Address != "" {
+		hosts = append([]SSHHostConfig{{Address: c.Address}}, hosts...)
+	}
+
+	r := make([]SSHHostConfig, 0, len(hosts))
+	for _, host := range hosts {
+		host.SSHAuthConfig = host.SSHAuthConfig.WithDefaults(c.SSHAuthConfig)
+		if host.MaxJobs == 0 {
+			host.MaxJobs = c.MaxJobsPerHost
+		}
+		if len(host.ProxyJump) == 0 {
+			host.ProxyJump = c.ProxyJump
+		}
+
+		jumps := make([]SSHJumpHostConfig, 0, len(host.ProxyJump))
+		for _, jump := range host.ProxyJump {
+			jump.SSHAuthConfig = jump.SSHAuthConfig.WithDefaults(host.SSHAuthConfig)
+			jumps = append(jumps, jump)
+		}
+		host.ProxyJump = jumps
+		r = append(r, host)
+	}
+	return r
+}
+
+// ShellConfig is the config for shell executor.
+// Workspaces are created under WorkspaceRoot, the system temp dir is used if it's empty.
+// WorkspaceCleanup can be:
+//   - always: workspace is removed after the job.
+//   - keep_on_failure: workspace is kept only if the job failed.
+//   - keep_last: only the KeepWorkspaces most recent workspaces are kept.
+type ShellConfig struct {
+	WorkspaceRoot    string `yaml:"workspace_root"`
+	WorkspaceCleanup string `yaml:"workspace_cleanup" default:"always"`
+	KeepWorkspaces   int    `yaml:"keep_workspaces" default:"10"`
+
+	// Isolation runs commands in new mount and PID namespaces,
+	// a user namespace is also created if pistage is not running as root.
+	// It only works on Linux, and is ignored if namespaces are not available.
+	Isolation bool `yaml:"isolation"`
+
+	// UID and GID are the user and group commands run as,
+	// 0 means not to change, changing them requires pistage running as root on Linux,
+	// the shell executor fails to start otherwise.
+	UID int `yaml:"uid"`
+	GID int `yaml:"gid"`
+
+	Rlimits ShellRlimitConfig `yaml:"rlimits"`
+}
+
+// ShellRlimitConfig is the resource limits of the processes of a job,
+// 0 means no limit. Jobs can lower them with rlimits in spec, but never exceed them.
+type ShellRlimitConfig struct {
+	CPUSecs   int   `yaml:"cpu"`
+	MemoryMB  int64 `yaml:"memory"`
+	OpenFiles int   `yaml:"open_files"`
+}
+
+// KubernetesConfig is the config for kubernetes executor.
+// If Kubeconfig is empty, in-cluster config will be used.
+type KubernetesConfig struct {
+	Kubeconfig          string                   `yaml:"kubeconfig"`
+	Namespace           string                   `yaml:"namespace" default:"default"`
+	ServiceAccount      string                   `yaml:"service_account"`
+	DefaultJobImage     string                   `yaml:"default_job_image"`
+	DefaultWorkingDir   string                   `yaml:"default_working_dir" default:"/pistage"`
+	PodReadyTimeoutSecs int                      `yaml:"pod_ready_timeout" default:"300"`
+	NodeSelector        map[string]string        `yaml:"node_selector"`
+	Resources           KubernetesResourceConfig `yaml:"resources"`
+}
+
+// KubernetesResourceConfig is the resource requests and limits of the job pod,
+// the keys are resource names like cpu and memory, values are quantities like 500m and 1Gi.
+type KubernetesResourceConfig struct {
+	Requests map[string]string `yaml:"requests"`
+	Limits   map[string]string `yaml:"limits"`
+}
+
+// DockerConfig is the config for docker executor.
+// Host can be a Docker or Podman socket, like unix:///var/run/docker.sock,
+// or unix:///run/user/1000/podman/podman.sock.
+type DockerConfig struct {
+	Host              string `yaml:"host" default:"unix:///var/run/docker.sock"`
+	APIVersion        string `yaml:"api_version" default:"v1.41"`
+	DefaultJobImage   string `yaml:"default_job_image"`
+	DefaultWorkingDir string `yaml:"default_working_dir" default:"/pistage"`
+	DefaultUser       string `yaml:"default_user"`
+	DefaultNetwork    string `yaml:"default_network" default:"bridge"`
+	DefaultPrivileged bool   `yaml:"default_privileged"`
+}
+
+// PluginConfig is the config for an executor plugin, it's registered as an executor by Name.
+// If Socket is given, pistage connects to the plugin listening on it,
+// otherwise Command is launched with PISTAGE_PLUGIN_SOCKET in environment,
+// the plugin should listen on it within StartTimeoutSecs.
+type PluginConfig struct {
+	Name             string            `yaml:"name"`
+	Command          []string          `yaml:"command"`
+	Socket           string            `yaml:"socket"`
+	Env              map[string]string `yaml:"env"`
+	StartTimeoutSecs int               `yaml:"start_timeout" default:"10"`
+}
+
+// KhoriumConfig is the config for KhoriumSteps.
+// Repositories of KhoriumSteps are mirrored under CacheDir,
+// with each resolved commit checked out once.
+// Tags and commits are immutable once resolved, branches are
+// resolved again after RefTTLSecs.
+// Steps loaded from other sources are kept in memory until they're not used for StepTTLSecs.
+//
+// Repositories on SSHHosts are cloned over SSH with SSHKey,
+// others over HTTPS, with credentials from CredentialHelper if it's set,
+// or GitLabUsername and GitLabAccessToken for non GitHub hosts.
+// Steps with file:// can only be loaded under FileRoots.
+//
+// If AllowedNamespaces is set, only git and tarball steps under them can be used,
+// e.g. github.com/projecteru2 allows github.com/projecteru2/checkout.
+// If PublicKeys, the PEM files of ed25519 public keys, are set, steps with
+// a signature file khoriumstep.yml.sig must be signed by one of them, and with RequireSignature steps without
+// signature are rejected too, all steps are rejected if RequireSignature is set without PublicKeys.
+// Steps bundled with spec are never checked.
 type KhoriumConfig struct {
-	GitLabUsername    string `yaml:"gitlab_username"`
-	GitLabAccessToken string `yaml:"gitlab_access_token"`
+	GitLabUsername    string   `yaml:"gitlab_username"`
+	GitLabAccessToken string   `yaml:"gitlab_access_token"`
+	CredentialHelper  string   `yaml:"credential_helper"`
+	SSHHosts          []string `yaml:"ssh_hosts"`
+	SSHKey            string   `yaml:"ssh_key"`
+	SSHKnownHosts     string   `yaml:"ssh_known_hosts"`
+	FileRoots         []string `yaml:"file_roots"`
+	AllowedNamespaces []string `yaml:"allowed_namespaces"`
+	PublicKeys        []string `yaml:"public_keys"`
+	RequireSignature  bool     `yaml:"require_signature"`
+	CacheDir          string   `yaml:"cache_dir" default:"/tmp/pistage-khorium"`
+	RefTTLSecs        int      `yaml:"ref_ttl" default:"300"`
+	StepTTLSecs       int      `yaml:"step_ttl" default:"3600"`
+}
+
+// CacheConfig is the config for job caches.
+// Caches are stored as tarballs under Dir, caches not used for MaxAgeSecs
+// will be evicted, the least recently used caches are evicted when
+// the total size exceeds MaxSizeMB.
+type CacheConfig struct {
+	Dir        string `yaml:"dir" default:"/tmp/pistage-cache"`
+	MaxAgeSecs int    `yaml:"max_age" default:"604800"`
+	MaxSizeMB  int64  `yaml:"max_size" default:"10240"`
+}
+
+// SpecConfig is the config for specs.
+// Templates included by specs are read from TemplateDirs,
+// e.g. template go/service is go/service.yml under the first dir having it.
+type SpecConfig struct {
+	TemplateDirs []string `yaml:"template_dirs"`
+}
+
+// SchedulerConfig is the config for scheduling pistages and their jobs.
+// MaxConcurrentJobs limits the jobs running at the same time of all pistages,
+// Quotas limit them by workflow type, 0 or not given means unlimited.
+type SchedulerConfig struct {
+	MaxConcurrentJobs int            `yaml:"max_concurrent_jobs"`
+	Quotas            map[string]int `yaml:"quotas"`
+}
+
+// SecretsConfig is the config for secrets referenced by {{ secrets.NAME }} in spec.
+// Providers are asked in order, the first one having the secret wins,
+// they can be env, file and vault.
+type SecretsConfig struct {
+	Providers []string           `yaml:"providers"`
+	Env       SecretsEnvConfig   `yaml:"env"`
+	File      SecretsFileConfig  `yaml:"file"`
+	Vault     SecretsVaultConfig `yaml:"vault"`
+}
+
+// SecretsEnvConfig is the config for secrets from environment variables of pistage,
+// secret NAME is read from environment variable Prefix+NAME.
+type SecretsEnvConfig struct {
+	Prefix string `yaml:"prefix" default:"PISTAGE_SECRET_"`
+}
+
+// SecretsFileConfig is the config for secrets from an encrypted file.
+// Path is a YAML map of names to values, sealed with AES-256-GCM by `pistage secrets encrypt`,
+// KeyFile holds the base64 encoded 32 bytes key.
+type SecretsFileConfig struct {
+	Path    string `yaml:"path"`
+	KeyFile string `yaml:"key_file"`
+}
+
+// SecretsVaultConfig is the config for secrets from a Vault compatible KV version 2 engine,
+// secret NAME is the key NAME of the secret at Path under Mount.
+// Token is read from TokenFile if it's empty.
+type SecretsVaultConfig struct {
+	Address     string `yaml:"address"`
+	Token       string `yaml:"token"`
+	TokenFile   string `yaml:"token_file"`
+	Namespace   string `yaml:"namespace"`
+	Mount       string `yaml:"mount" default:"secret"`
+	Path        string `yaml:"path"`
+	TimeoutSecs int    `yaml:"timeout" default:"10"`
 }
 
 type SQLDataSourceConfig struct {
@@ -79,6 +367,9 @@ func (c *Config) initDefault() {
 	if c.DefaultJobExecuteTimeoutSecs == 0 {
 		c.DefaultJobExecuteTimeoutSecs = 1200
 	}
+	if c.DefaultJobCleanupTimeoutSecs == 0 {
+		c.DefaultJobCleanupTimeoutSecs = 300
+	}
 	if c.Eru.DefaultWorkingDir == "" {
 		c.Eru.DefaultWorkingDir = "/pistage"
 	}
@@ -91,6 +382,80 @@ func (c *Config) initDefault() {
 	if c.Eru.DefaultNetwork == "" {
 		c.Eru.DefaultNetwork = "host"
 	}
+	if c.Eru.ServiceReadyTimeoutSecs == 0 {
+		c.Eru.ServiceReadyTimeoutSecs = 120
+	}
+	if c.SSH.KnownHosts == "" {
+		c.SSH.KnownHosts = "~/.ssh/known_hosts"
+	}
+	if c.SSH.HostKeyPolicy == "" {
+		c.SSH.HostKeyPolicy = "strict"
+	}
+	if c.SSH.Strategy == "" {
+		c.SSH.Strategy = "least_loaded"
+	}
+	if c.SSH.MaxIdleConnsPerHost == 0 {
+		c.SSH.MaxIdleConnsPerHost = 2
+	}
+	for i := range c.Plugins {
+		if c.Plugins[i].StartTimeoutSecs == 0 {
+			c.Plugins[i].StartTimeoutSecs = 10
+		}
+	}
+	if c.Shell.WorkspaceCleanup == "" {
+		c.Shell.WorkspaceCleanup = "always"
+	}
+	if c.Shell.KeepWorkspaces == 0 {
+		c.Shell.KeepWorkspaces = 10
+	}
+	if c.Kubernetes.Namespace == "" {
+		c.Kubernetes.Namespace = "default"
+	}
+	if c.Kubernetes.DefaultWorkingDir == "" {
+		c.Kubernetes.DefaultWorkingDir = "/pistage"
+	}
+	if c.Kubernetes.PodReadyTimeoutSecs == 0 {
+		c.Kubernetes.PodReadyTimeoutSecs = 300
+	}
+	if c.Docker.Host == "" {
+		c.Docker.Host = "unix:///var/run/docker.sock"
+	}
+	if c.Docker.APIVersion == "" {
+		c.Docker.APIVersion = "v1.41"
+	}
+	if c.Docker.DefaultWorkingDir == "" {
+		c.Docker.DefaultWorkingDir = "/pistage"
+	}
+	if c.Docker.DefaultNetwork == "" {
+		c.Docker.DefaultNetwork = "bridge"
+	}
+	if c.Khorium.CacheDir == "" {
+		c.Khorium.CacheDir = "/tmp/pistage-khorium"
+	}
+	if c.Khorium.RefTTLSecs == 0 {
+		c.Khorium.RefTTLSecs = 300
+	}
+	if c.Khorium.StepTTLSecs == 0 {
+		c.Khorium.StepTTLSecs = 3600
+	}
+	if c.Cache.Dir == "" {
+		c.Cache.Dir = "/tmp/pistage-cache"
+	}
+	if c.Cache.MaxAgeSecs == 0 {
+		c.Cache.MaxAgeSecs = 604800
+	}
+	if c.Cache.MaxSizeMB == 0 {
+		c.Cache.MaxSizeMB = 10240
+	}
+	if c.Secrets.Env.Prefix == "" {
+		c.Secrets.Env.Prefix = "PISTAGE_SECRET_"
+	}
+	if c.Secrets.Vault.Mount == "" {
+		c.Secrets.Vault.Mount = "secret"
+	}
+	if c.Secrets.Vault.TimeoutSecs == 0 {
+		c.Secrets.Vault.TimeoutSecs = 10
+	}
 }
 
 func LoadConfigFromFile(path string) (*Config, error) {
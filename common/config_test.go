@@ -0,0 +1,45 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSSHConfigGetHosts(t *testing.T) {
+	assert := assert.New(t)
+
+	config := SSHConfig{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+user: pistage
+use_agent: true
+max_jobs_per_host: 2
+hosts:
+  - address: a
+  - address: b
+    user: root
+    use_agent: false
+    max_jobs: 4
+    proxy_jump:
+      - address: bastion
+`), &config))
+
+	hosts := config.GetHosts()
+	assert.Len(hosts, 2)
+
+	assert.Equal("pistage", hosts[0].User)
+	assert.True(*hosts[0].UseAgent)
+	assert.Equal(2, hosts[0].MaxJobs)
+
+	// explicit false of host wins over the default.
+	assert.Equal("root", hosts[1].User)
+	assert.False(*hosts[1].UseAgent)
+	assert.Equal(4, hosts[1].MaxJobs)
+	assert.Equal("root", hosts[1].ProxyJump[0].User)
+	assert.False(*hosts[1].ProxyJump[0].UseAgent)
+
+	// nothing is set.
+	hosts = SSHConfig{Address: "c"}.GetHosts()
+	assert.Nil(hosts[0].UseAgent)
+}
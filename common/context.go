@@ -10,6 +10,8 @@ import (
 //   - EruFileCollector
 //   - ShellFileCollector
 //   - SSHFileCollector
+//   - KubernetesFileCollector
+//   - DockerFileCollector
 // For each implementation, refer to the code for the meaning of
 // identifier and files.
 type FileCollector interface {
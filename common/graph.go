@@ -0,0 +1,127 @@
+package common
+
+import (
+	"sort"
+	"time"
+)
+
+// GraphJob is a job in Graph, Status and Duration are set if the job has run.
+type GraphJob struct {
+	Name      string
+	DependsOn []string
+	Status    RunStatus
+	Duration  time.Duration
+}
+
+// Graph is the dependency graph of jobs, for visualization.
+type Graph struct {
+	Jobs map[string]*GraphJob
+
+	// Stages are the jobs can be executed parallelly, in order, see JobDependencies.
+	Stages [][]string
+
+	// CriticalPath is the longest chain of jobs, weighted by durations if any job has run,
+	// otherwise by the number of jobs. Jobs are in the order they're executed.
+	CriticalPath []string
+}
+
+// Graph returns the graph of jobs of p, with the statuses and durations of jobRuns of a run,
+// jobRuns can be nil if p hasn't run.
+func (p *Pistage) Graph(jobRuns []*JobRun) (*Graph, error) {
+	runs := map[string]*JobRun{}
+	for _, jobRun := range jobRuns {
+		runs[jobRun.JobName] = jobRun
+	}
+
+	jobs := make([]*GraphJob, 0, len(p.Jobs))
+	for name, job := range p.Jobs {
+		gj := &GraphJob{Name: name, DependsOn: job.DependsOn}
+		if run, ok := runs[name]; ok {
+			gj.Status = run.Status
+			if run.End > run.Start {
+				gj.Duration = time.Duration(run.End-run.Start) * time.Millisecond
+			}
+		}
+		jobs = append(jobs, gj)
+	}
+	return NewGraph(jobs)
+}
+
+// NewGraph builds a Graph of jobs, with stages and critical path computed.
+func NewGraph(jobs []*GraphJob) (*Graph, error) {
+	g := &Graph{Jobs: map[string]*GraphJob{}}
+	tp := newTopo()
+	for _, job := range jobs {
+		g.Jobs[job.Name] = job
+		tp.addDependencies(job.Name, job.DependsOn...)
+	}
+	for _, job := range jobs {
+		for _, dependency := range job.DependsOn {
+			if _, ok := g.Jobs[dependency]; !ok {
+				return nil, ErrorJobNotFound
+			}
+		}
+	}
+
+	stages, err := tp.graph()
+	if err != nil {
+		return nil, err
+	}
+	for _, stage := range stages {
+		sort.Strings(stage)
+	}
+	g.Stages = stages
+	g.CriticalPath = g.criticalPath()
+	return g, nil
+}
+
+// criticalPath finds the longest chain of jobs, jobs in stages are visited after their dependencies.
+func (g *Graph) criticalPath() []string {
+	weighted := false
+	for _, job := range g.Jobs {
+		if job.Duration > 0 {
+			weighted = true
+		}
+	}
+	weight := func(job *GraphJob) time.Duration {
+		if weighted {
+			return job.Duration
+		}
+		return 1
+	}
+
+	var (
+		finish   = map[string]time.Duration{}
+		previous = map[string]string{}
+		last     string
+	)
+	for _, stage := range g.Stages {
+		for _, name := range stage {
+			job := g.Jobs[name]
+			for _, dependency := range job.DependsOn {
+				if _, ok := previous[name]; !ok || finish[dependency] > finish[previous[name]] {
+					previous[name] = dependency
+				}
+			}
+			finish[name] = finish[previous[name]] + weight(job)
+			if last == "" || finish[name] > finish[last] {
+				last = name
+			}
+		}
+	}
+
+	var path []string
+	for name := last; name != ""; name = previous[name] {
+		path = append([]string{name}, path...)
+	}
+	return path
+}
+
+// Duration returns the sum of durations of jobs on CriticalPath.
+func (g *Graph) Duration() time.Duration {
+	var d time.Duration
+	for _, name := range g.CriticalPath {
+		d += g.Jobs[name].Duration
+	}
+	return d
+}
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPistageGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := FromSpec([]byte(`
+jobs:
+  checkout:
+    steps: [{name: checkout, run: [git pull]}]
+  lint:
+    depends_on: [checkout]
+    steps: [{name: lint, run: [make lint]}]
+  test:
+    depends_on: [checkout]
+    steps: [{name: test, run: [make test]}]
+  build:
+    depends_on: [test]
+    steps: [{name: build, run: [make]}]
+  deploy:
+    depends_on: [lint, build]
+    steps: [{name: deploy, run: [make deploy]}]
+`))
+	assert.NoError(err)
+
+	// by number of jobs without runs.
+	g, err := p.Graph(nil)
+	assert.NoError(err)
+	assert.Equal([][]string{{"checkout"}, {"lint", "test"}, {"build"}, {"deploy"}}, g.Stages)
+	assert.Equal([]string{"checkout", "test", "build", "deploy"}, g.CriticalPath)
+	assert.Equal(RunStatus(""), g.Jobs["deploy"].Status)
+
+	// by durations with runs.
+	g, err = p.Graph([]*JobRun{
+		{JobName: "checkout", Status: RunStatusFinished, Start: 0, End: 1000},
+		{JobName: "lint", Status: RunStatusFinished, Start: 1000, End: 61000},
+		{JobName: "test", Status: RunStatusFinished, Start: 1000, End: 11000},
+		{JobName: "build", Status: RunStatusFinished, Start: 11000, End: 21000},
+		{JobName: "deploy", Status: RunStatusFailed, Start: 61000, End: 66000},
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"checkout", "lint", "deploy"}, g.CriticalPath)
+	assert.Equal(66*time.Second, g.Duration())
+	assert.Equal(RunStatusFailed, g.Jobs["deploy"].Status)
+	assert.Equal(time.Minute, g.Jobs["lint"].Duration)
+
+	_, err = NewGraph([]*GraphJob{{Name: "a", DependsOn: []string{"missing"}}})
+	assert.ErrorIs(err, ErrorJobNotFound)
+}
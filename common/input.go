@@ -0,0 +1,222 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrorInputIsInvalid is returned when a value doesn't match the type, options or pattern of KhoriumStepInput.
+	ErrorInputIsInvalid = errors.New("Input is invalid")
+
+	// ErrorBadInputDefinition is returned when KhoriumStepInput itself is invalid, e.g. an unknown type.
+	ErrorBadInputDefinition = errors.New("Bad input definition")
+)
+
+// Types of KhoriumStepInput.
+const (
+	InputTypeString = "string"
+	InputTypeNumber = "number"
+	InputTypeBool   = "bool"
+	InputTypeEnum   = "enum"
+	InputTypeList   = "list"
+)
+
+// InputErrors holds the errors of all invalid inputs,
+// so they can be reported at once instead of one by one.
+type InputErrors []error
+
+func (e InputErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is tells if any of the errors is target, so errors.Is works with InputErrors.
+func (e InputErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Inputs are the typed parameters of a KhoriumStep or of a workflow, keys are the names.
+type Inputs map[string]*KhoriumStepInput
+
+// ResolveInputs resolves the values of inputs of ks from vars, see Inputs.Resolve.
+func (ks *KhoriumStep) ResolveInputs(vars map[string]string) (map[string]string, error) {
+	return ks.Inputs.Resolve(vars)
+}
+
+// Deprecations returns the warnings of deprecated inputs of ks given in vars.
+func (ks *KhoriumStep) Deprecations(vars map[string]string) []string {
+	return ks.Inputs.Deprecations(ks.Name, vars)
+}
+
+// Resolve resolves the values of inputs from vars,
+// default values are used for inputs not given.
+// Values are validated and normalized by their types,
+// bools are true or false, items of lists are joined by newlines.
+// Errors of all invalid inputs are returned together as InputErrors.
+func (in Inputs) Resolve(vars map[string]string) (map[string]string, error) {
+	var errs InputErrors
+	inputs := map[string]string{}
+	for _, name := range in.names() {
+		input := in[name]
+		value, ok := vars[name]
+		switch {
+		case input.Required && !ok:
+			errs = append(errs, errors.WithMessagef(ErrorInputIsRequired, "input: %s", name))
+			continue
+		case !ok && input.Default == "":
+			inputs[name] = ""
+			continue
+		case !ok:
+			value = input.Default
+		}
+
+		value, err := input.normalize(value)
+		if err != nil {
+			errs = append(errs, errors.WithMessagef(err, "input: %s", name))
+			continue
+		}
+		inputs[name] = value
+	}
+
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return inputs, nil
+}
+
+// Deprecations returns the warnings of deprecated inputs given in vars, owner is who the inputs belong to.
+func (in Inputs) Deprecations(owner string, vars map[string]string) []string {
+	var warnings []string
+	for _, name := range in.names() {
+		input := in[name]
+		if _, ok := vars[name]; ok && input.Deprecated != "" {
+			warnings = append(warnings, fmt.Sprintf("input %s of %s is deprecated: %s", name, owner, input.Deprecated))
+		}
+	}
+	return warnings
+}
+
+// validate checks the types, options, patterns and defaults of all inputs.
+func (in Inputs) validate() error {
+	var errs InputErrors
+	for _, name := range in.names() {
+		input := in[name]
+		if input == nil {
+			errs = append(errs, errors.WithMessagef(ErrorBadInputDefinition, "input: %s, empty definition", name))
+			continue
+		}
+
+		switch input.Type {
+		case "", InputTypeString, InputTypeNumber, InputTypeBool, InputTypeList:
+		case InputTypeEnum:
+			if len(input.Options) == 0 {
+				errs = append(errs, errors.WithMessagef(ErrorBadInputDefinition, "input: %s, enum without options", name))
+				continue
+			}
+		default:
+			errs = append(errs, errors.WithMessagef(ErrorBadInputDefinition, "input: %s, unknown type %s", name, input.Type))
+			continue
+		}
+
+		if _, err := regexp.Compile(input.Pattern); err != nil {
+			errs = append(errs, errors.WithMessagef(ErrorBadInputDefinition, "input: %s, pattern: %v", name, err))
+			continue
+		}
+		if input.Default != "" {
+			if _, err := input.normalize(input.Default); err != nil {
+				errs = append(errs, errors.WithMessagef(ErrorBadInputDefinition, "input: %s, default: %v", name, err))
+			}
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func (in Inputs) names() []string {
+	names := make([]string, 0, len(in))
+	for name := range in {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// normalize validates value by the type of input, returns the normalized value.
+// Pattern must match the whole value, or each item of a list.
+func (input *KhoriumStepInput) normalize(value string) (string, error) {
+	items := []string{value}
+
+	switch input.Type {
+	case "", InputTypeString:
+	case InputTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", errors.WithMessagef(ErrorInputIsInvalid, "%q is not a number", value)
+		}
+	case InputTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", errors.WithMessagef(ErrorInputIsInvalid, "%q is not a bool", value)
+		}
+		return strconv.FormatBool(b), nil
+	case InputTypeEnum:
+		if !contains(input.Options, value) {
+			return "", errors.WithMessagef(ErrorInputIsInvalid, "%q is not one of %s", value, strings.Join(input.Options, ", "))
+		}
+	case InputTypeList:
+		items = splitList(value)
+		value = strings.Join(items, "\n")
+	default:
+		return "", errors.WithMessagef(ErrorBadInputDefinition, "unknown type %s", input.Type)
+	}
+
+	if input.Pattern == "" {
+		return value, nil
+	}
+	re, err := regexp.Compile("^(?:" + input.Pattern + ")$")
+	if err != nil {
+		return "", errors.WithMessagef(ErrorBadInputDefinition, "pattern: %v", err)
+	}
+	for _, item := range items {
+		if !re.MatchString(item) {
+			return "", errors.WithMessagef(ErrorInputIsInvalid, "%q doesn't match %s", item, input.Pattern)
+		}
+	}
+	return value, nil
+}
+
+// splitList splits value by commas or newlines, empty items are dropped.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
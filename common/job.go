@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -18,6 +19,9 @@ var (
 
 	// ErrorStepHasNoName is returned when the step has no name.
 	ErrorStepHasNoName = errors.New("Step has no name")
+
+	// ErrorBadByteSize is returned when the size can't be parsed.
+	ErrorBadByteSize = errors.New("Bad byte size")
 )
 
 type Job struct {
@@ -29,6 +33,28 @@ type Job struct {
 	Timeout       int               `yaml:"timeout" json:"timeout"`
 	Environment   map[string]string `yaml:"env" json:"env"`
 	Files         []string          `yaml:"files" json:"files"`
+	Cache         []*Cache          `yaml:"cache" json:"cache"`
+	RunsOn        *RunsOn           `yaml:"runs_on" json:"runs_on"`
+
+	// Resources and placement of the workload, currently only eru executor uses them.
+	// Empty values fall back to the defaults in config.
+	Resources  *Resources        `yaml:"resources" json:"resources"`
+	Pod        string            `yaml:"pod" json:"pod"`
+	Nodes      []string          `yaml:"nodes" json:"nodes"`
+	NodeLabels map[string]string `yaml:"nodelabels" json:"nodelabels"`
+	Network    string            `yaml:"network" json:"network"`
+	User       string            `yaml:"user" json:"user"`
+	Privileged *bool             `yaml:"privileged" json:"privileged"`
+	Volumes    []string          `yaml:"volumes" json:"volumes"`
+	DNS        []string          `yaml:"dns" json:"dns"`
+
+	// Rlimits are the resource limits of the processes of the job, currently only shell executor uses them.
+	// Limits in config are the defaults, and the caps the job can't exceed.
+	Rlimits *Rlimits `yaml:"rlimits" json:"rlimits"`
+
+	// Services are sidecar workloads the job needs, like databases,
+	// keys are the names of services.
+	Services map[string]*Service `yaml:"services" json:"services"`
 
 	fileCollector FileCollector `yaml:"-" json:"-"`
 }
@@ -53,6 +79,88 @@ func LoadJob(content []byte) (*Job, error) {
 	return j, nil
 }
 
+// Cache describes the files to be cached between runs.
+// Key and RestoreKeys are templates, hashFiles can be used inside
+// to build the key from the content of files in the working dir, e.g.
+//   key: go-{{ hashFiles('go.sum') }}
+// If Key misses, the most recent cache with key prefixed by
+// one of the RestoreKeys will be restored, in the given order.
+type Cache struct {
+	Key         string   `yaml:"key" json:"key"`
+	RestoreKeys []string `yaml:"restore_keys" json:"restore_keys"`
+	Paths       []string `yaml:"paths" json:"paths"`
+}
+
+// RunsOn selects where the job runs.
+// Currently only ssh executor uses it, to select hosts with all the labels.
+type RunsOn struct {
+	Labels []string `yaml:"labels" json:"labels"`
+}
+
+// Service is a sidecar workload running alongside the job,
+// it's started before the job and stopped after the job.
+// Command is the command of the service, the default command of image is used if it's empty.
+// Ports are TCP ports the service listens on, the service is considered ready
+// when all the ports are connectable.
+type Service struct {
+	Image       string            `yaml:"image" json:"image"`
+	Command     []string          `yaml:"command" json:"command"`
+	Environment map[string]string `yaml:"env" json:"env"`
+	Ports       []int             `yaml:"ports" json:"ports"`
+}
+
+// Resources is the resources a job needs,
+// CPU is the number of cores, can be fractional like 0.5,
+// Memory and Storage are sizes like 512M or 10G, 0 means no limit.
+type Resources struct {
+	CPU     float64  `yaml:"cpu" json:"cpu"`
+	Memory  ByteSize `yaml:"memory" json:"memory"`
+	Storage ByteSize `yaml:"storage" json:"storage"`
+}
+
+// Rlimits is the resource limits of processes,
+// CPU is the CPU time in seconds, Memory is a size like 512M, 0 means the default.
+type Rlimits struct {
+	CPU       int      `yaml:"cpu" json:"cpu"`
+	Memory    ByteSize `yaml:"memory" json:"memory"`
+	OpenFiles int      `yaml:"open_files" json:"open_files"`
+}
+
+// ByteSize is a size in bytes,
+// in yaml it can be written as a number of bytes, or with a unit of K, M, G or T,
+// units are all binary, 1K is 1024 bytes.
+type ByteSize int64
+
+// UnmarshalYAML parses the size with unit.
+func (b *ByteSize) UnmarshalYAML(value *yaml.Node) error {
+	size, err := ParseByteSize(value.Value)
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(size)
+	return nil
+}
+
+// ParseByteSize parses size like 512M, 1.5G, 1Gi or 1GB into bytes.
+func ParseByteSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := int64(1)
+	if len(s) > 0 {
+		if i := strings.IndexByte("KMGT", s[len(s)-1]); i >= 0 {
+			multiplier = int64(1) << (10 * (i + 1))
+			s = s[:len(s)-1]
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, errors.WithMessagef(ErrorBadByteSize, "size: %s", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
 type Step struct {
 	Name        string            `yaml:"name" json:"name"`
 	Uses        string            `yaml:"uses" json:"uses"`
@@ -93,6 +201,15 @@ type Run struct {
 	Status             RunStatus `json:"status"`
 	Start              int64     `json:"start"`
 	End                int64     `json:"end"`
+
+	// Vars are the resolved values of inputs this run is applied with.
+	Vars map[string]string `json:"vars"`
+
+	// SnapshotID is the id of snapshot of the pistage this run is created from.
+	SnapshotID string `json:"snapshot_id"`
+
+	// Queued is when the run is applied, Start - Queued is the time waiting for a stager worker.
+	Queued int64 `json:"queued"`
 }
 
 type JobRun struct {
@@ -105,6 +222,23 @@ type JobRun struct {
 	Start              int64              `json:"start"`
 	End                int64              `json:"end"`
 	LogTracer          io.ReadWriteCloser `json:"-"`
+
+	// Prepared is when the executor of job is prepared, e.g. the workload is created on eru,
+	// Prepared - Start is the time waiting for the executor.
+	Prepared int64 `json:"prepared"`
+}
+
+// StepRun records what a KhoriumStep used in a JobRun actually is,
+// Rollback tells if the step is one of rollback steps.
+type StepRun struct {
+	ID       string `json:"id"`
+	JobRunID string `json:"job_run_id"`
+	StepName string `json:"step_name"`
+	Uses     string `json:"uses"`
+	Commit   string `json:"commit"`
+	Digest   string `json:"digest"`
+	SignedBy string `json:"signed_by"`
+	Rollback bool   `json:"rollback"`
 }
 
 var (
@@ -114,21 +248,39 @@ var (
 	// ErrorMustSpecifyRun is returned when run is not given in the specification.
 	ErrorMustSpecifyRun = errors.New("Must specify run")
 
-	// ErrorMustSpecifyMain is returned when main is not given in run part.
-	ErrorMustSpecifyMain = errors.New("Must specify main")
+	// ErrorMustSpecifyMain is returned when neither main nor steps is given in run part.
+	ErrorMustSpecifyMain = errors.New("Must specify main or steps")
+
+	// ErrorMainWithSteps is returned when both main and steps are given in run part.
+	ErrorMainWithSteps = errors.New("Can't specify both main and steps")
+
+	// ErrorStepHasNothingToRun is returned when a step in composite KhoriumStep has neither run nor uses.
+	ErrorStepHasNothingToRun = errors.New("Step has nothing to run")
 )
 
 // KhoriumStep is the predefined step.
 // It can be used as a step to execute during a job.
 type KhoriumStep struct {
-	Name        string                       `yaml:"name" json:"name"`
-	Description string                       `yaml:"description" json:"description"`
-	Inputs      map[string]*KhoriumStepInput `yaml:"inputs" json:"inputs"`
-	Run         *KhoriumStepRun              `yaml:"run" json:"run"`
+	Name        string          `yaml:"name" json:"name"`
+	Description string          `yaml:"description" json:"description"`
+	Inputs      Inputs          `yaml:"inputs" json:"inputs"`
+	Run         *KhoriumStepRun `yaml:"run" json:"run"`
+
+	// Outputs are written by the step into the file $KHORIUMSTEP_OUTPUT as name=value lines,
+	// only the outputs declared here are read back.
+	Outputs map[string]*KhoriumStepOutput `yaml:"outputs" json:"outputs"`
 
 	// Files contains all the files within this KhoriumStep, filename with path as key, content as value.
 	// They can be binary executable, or scripts, as a tarball.
 	Files map[string][]byte `yaml:"-" json:"-"`
+
+	// Commit is the commit of repository the version is resolved to.
+	Commit string `yaml:"-" json:"commit"`
+
+	// Digest is the sha256 digest of Files, signature file excluded,
+	// SignedBy is the fingerprint of the key the digest is signed by, if it's verified.
+	Digest   string `yaml:"-" json:"digest"`
+	SignedBy string `yaml:"-" json:"signed_by"`
 }
 
 func (ks *KhoriumStep) Validate() error {
@@ -138,45 +290,134 @@ func (ks *KhoriumStep) Validate() error {
 	if ks.Run == nil {
 		return ErrorMustSpecifyRun
 	}
-	if ks.Run.Main == "" {
+	if ks.Run.Main == "" && len(ks.Run.Steps) == 0 {
 		return ErrorMustSpecifyMain
 	}
-	return nil
+	if ks.Run.Main != "" && len(ks.Run.Steps) != 0 {
+		return ErrorMainWithSteps
+	}
+	for i, step := range ks.Run.Steps {
+		// a null step, e.g. steps: [~], has nothing to run either.
+		if step == nil || len(step.Run) == 0 && step.Uses == "" {
+			return errors.WithMessagef(ErrorStepHasNothingToRun, "step: %d", i)
+		}
+	}
+	return ks.Inputs.validate()
+}
+
+// IsComposite tells if this KhoriumStep is made of other steps.
+func (ks *KhoriumStep) IsComposite() bool {
+	return ks.Run != nil && len(ks.Run.Steps) != 0
+}
+
+// Copy returns a deep copy of ks, so the copy can be changed without affecting ks.
+// Contents of Files are shared, they're never changed.
+func (ks *KhoriumStep) Copy() *KhoriumStep {
+	copied := *ks
+	if ks.Inputs != nil {
+		copied.Inputs = make(Inputs, len(ks.Inputs))
+		for name, input := range ks.Inputs {
+			if input != nil {
+				i := *input
+				i.Options = append([]string(nil), input.Options...)
+				input = &i
+			}
+			copied.Inputs[name] = input
+		}
+	}
+	if ks.Outputs != nil {
+		copied.Outputs = make(map[string]*KhoriumStepOutput, len(ks.Outputs))
+		for name, output := range ks.Outputs {
+			if output != nil {
+				o := *output
+				output = &o
+			}
+			copied.Outputs[name] = output
+		}
+	}
+	if ks.Files != nil {
+		copied.Files = make(map[string][]byte, len(ks.Files))
+		for name, content := range ks.Files {
+			copied.Files[name] = content
+		}
+	}
+	if ks.Run != nil {
+		run := *ks.Run
+		run.Steps = copySteps(ks.Run.Steps)
+		copied.Run = &run
+	}
+	return &copied
+}
+
+// copySteps returns deep copies of steps, null steps are kept.
+func copySteps(steps []*Step) []*Step {
+	if steps == nil {
+		return nil
+	}
+	keep := func(s string) string { return s }
+	r := make([]*Step, 0, len(steps))
+	for _, step := range steps {
+		if step == nil {
+			r = append(r, nil)
+			continue
+		}
+		r = append(r, mapSteps([]*Step{step}, keep, keep)...)
+	}
+	return r
 }
 
 // BuildEnvironmentVariables builds an environment variables map for the input.
-// If the value is required but not given, will return an error.
+// If any value is invalid, or required but not given, will return an error.
 // The values in KhoriumStepInput will be set as an environment variable in the format
 // KHORIUMSTEP_INPUT_${upper case of the input name}.
 func (ks *KhoriumStep) BuildEnvironmentVariables(vars map[string]string) (map[string]string, error) {
+	inputs, err := ks.ResolveInputs(vars)
+	if err != nil {
+		return nil, err
+	}
+
 	envs := map[string]string{}
-	for name, input := range ks.Inputs {
-		key := fmt.Sprintf("KHORIUMSTEP_INPUT_%s", strings.ToUpper(name))
-		value, ok := vars[name]
-
-		switch {
-		case input.Required && !ok:
-			return nil, errors.WithMessagef(ErrorInputIsRequired, "input: %s", name)
-		case ok:
-			envs[key] = value
-		default:
-			envs[key] = input.Default
-		}
+	for name, value := range inputs {
+		envs[fmt.Sprintf("KHORIUMSTEP_INPUT_%s", strings.ToUpper(name))] = value
 	}
 	return envs, nil
 }
 
+// KhoriumStepSummary is a KhoriumStep known by pistage,
+// Versions are the tags of its repository, the newest first.
+type KhoriumStepSummary struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Versions    []string `json:"versions"`
+}
+
 // KhoriumStepInput is the inputs of KhoriumStep.
+// Type is one of string, number, bool, enum and list, string if not given,
+// Options are the values allowed for enum, Pattern is the regexp the value,
+// or each item of a list, must match.
+// Deprecated is the message to warn with when the input is given.
 type KhoriumStepInput struct {
+	Description string   `yaml:"description" json:"description"`
+	Default     string   `yaml:"default" json:"default"`
+	Required    bool     `yaml:"required" json:"required"`
+	Type        string   `yaml:"type" json:"type"`
+	Options     []string `yaml:"options" json:"options"`
+	Pattern     string   `yaml:"pattern" json:"pattern"`
+	Deprecated  string   `yaml:"deprecated" json:"deprecated"`
+}
+
+// KhoriumStepOutput is the outputs of KhoriumStep.
+type KhoriumStepOutput struct {
 	Description string `yaml:"description" json:"description"`
-	Default     string `yaml:"default" json:"default"`
-	Required    bool   `yaml:"required" json:"required"`
 }
 
-// KhoriumStepRun is the command to run of KhoriumStep
+// KhoriumStepRun is the command to run of KhoriumStep.
+// Either Main or Steps is given, with Steps the KhoriumStep is a composite one,
+// which is expanded into its steps when executing, inputs are referenced as {{ inputs.name }}.
 type KhoriumStepRun struct {
-	Main string `yaml:"main" json:"main"`
-	Post string `yaml:"post" json:"post"`
+	Main  string  `yaml:"main" json:"main"`
+	Post  string  `yaml:"post" json:"post"`
+	Steps []*Step `yaml:"steps" json:"steps"`
 }
 
 func LoadKhoriumStep(content []byte) (*KhoriumStep, error) {
@@ -44,3 +44,168 @@ func TestKhoriumStepBuildEnvironmentVariables(t *testing.T) {
 	_, err = ks.BuildEnvironmentVariables(map[string]string{"input1": "i1", "input3": "i3"})
 	assert.Error(err)
 }
+
+func TestKhoriumStepResolveInputs(t *testing.T) {
+	assert := assert.New(t)
+
+	ks := &KhoriumStep{
+		Name: "test",
+		Inputs: map[string]*KhoriumStepInput{
+			"replicas": {Type: InputTypeNumber, Default: "1"},
+			"debug":    {Type: InputTypeBool},
+			"env":      {Type: InputTypeEnum, Options: []string{"test", "prod"}, Required: true},
+			"hosts":    {Type: InputTypeList, Pattern: `[a-z0-9.]+`},
+			"token":    {Deprecated: "use secrets instead"},
+		},
+		Run: &KhoriumStepRun{Main: "deploy"},
+	}
+	assert.NoError(ks.Validate())
+
+	inputs, err := ks.ResolveInputs(map[string]string{"debug": "1", "env": "prod", "hosts": "a.com, b.com\nc.com,"})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"replicas": "1", "debug": "true", "env": "prod", "hosts": "a.com\nb.com\nc.com", "token": ""}, inputs)
+
+	// all invalid inputs are reported at once.
+	_, err = ks.ResolveInputs(map[string]string{"replicas": "many", "debug": "maybe", "hosts": "a.com,B.com"})
+	assert.ErrorIs(err, ErrorInputIsRequired)
+	assert.ErrorIs(err, ErrorInputIsInvalid)
+	assert.Len(err.(InputErrors), 4)
+	assert.Contains(err.Error(), "input: replicas")
+	assert.Contains(err.Error(), `"B.com" doesn't match`)
+
+	assert.Equal([]string{"input token of test is deprecated: use secrets instead"}, ks.Deprecations(map[string]string{"token": "x", "env": "test"}))
+	assert.Empty(ks.Deprecations(map[string]string{"env": "test"}))
+
+	ks.Inputs = map[string]*KhoriumStepInput{
+		"kind":  {Type: "object"},
+		"env":   {Type: InputTypeEnum},
+		"name":  {Pattern: "("},
+		"count": {Type: InputTypeNumber, Default: "one"},
+	}
+	err = ks.Validate()
+	assert.ErrorIs(err, ErrorBadInputDefinition)
+	assert.Len(err.(InputErrors), 4)
+}
+
+func TestLoadCompositeKhoriumStep(t *testing.T) {
+	assert := assert.New(t)
+
+	ks, err := LoadKhoriumStep([]byte(`
+name: build
+inputs:
+  ref:
+    required: true
+run:
+  steps:
+    - name: checkout
+      uses: github.com/test/checkout@v1
+      with:
+        ref: "{{ inputs.ref }}"
+    - run:
+        - go build ./...
+`))
+	assert.NoError(err)
+	assert.True(ks.IsComposite())
+	assert.Len(ks.Run.Steps, 2)
+	assert.Equal("github.com/test/checkout@v1", ks.Run.Steps[0].Uses)
+
+	_, err = LoadKhoriumStep([]byte(`
+name: build
+run:
+  main: make
+  steps:
+    - run: [make]
+`))
+	assert.ErrorIs(err, ErrorMainWithSteps)
+
+	_, err = LoadKhoriumStep([]byte(`
+name: build
+run:
+  steps:
+    - name: nothing
+`))
+	assert.ErrorIs(err, ErrorStepHasNothingToRun)
+
+	_, err = LoadKhoriumStep([]byte(`
+name: build
+run:
+  steps: [~]
+`))
+	assert.ErrorIs(err, ErrorStepHasNothingToRun)
+
+	_, err = LoadKhoriumStep([]byte(`
+name: build
+run:
+  post: make clean
+`))
+	assert.ErrorIs(err, ErrorMustSpecifyMain)
+}
+
+func TestLoadJobResources(t *testing.T) {
+	assert := assert.New(t)
+
+	job, err := LoadJob([]byte(`
+name: build
+resources:
+  cpu: 1.5
+  memory: 512M
+  storage: 10Gi
+privileged: false
+nodelabels:
+  arch: amd64
+rlimits:
+  cpu: 60
+  memory: 1G
+  open_files: 1024
+`))
+	assert.NoError(err)
+	assert.Equal(&Rlimits{CPU: 60, Memory: 1 << 30, OpenFiles: 1024}, job.Rlimits)
+	assert.Equal(1.5, job.Resources.CPU)
+	assert.Equal(ByteSize(512<<20), job.Resources.Memory)
+	assert.Equal(ByteSize(10<<30), job.Resources.Storage)
+	assert.False(*job.Privileged)
+	assert.Equal("amd64", job.NodeLabels["arch"])
+
+	for size, bytes := range map[string]int64{"1024": 1024, "1k": 1024, "1.5G": 3 << 29, "2TB": 2 << 40} {
+		b, err := ParseByteSize(size)
+		assert.NoError(err)
+		assert.Equal(bytes, b)
+	}
+	for _, size := range []string{"", "1X", "-1M", "1M2"} {
+		_, err := ParseByteSize(size)
+		assert.ErrorIs(err, ErrorBadByteSize)
+	}
+}
+
+func TestKhoriumStepCopy(t *testing.T) {
+	assert := assert.New(t)
+
+	ks, err := LoadKhoriumStep([]byte(`
+name: release
+inputs:
+  target:
+    type: enum
+    options: [binary, image]
+outputs:
+  version: {}
+run:
+  steps:
+    - uses: ./build
+      with:
+        target: "{{ inputs.target }}"
+`))
+	assert.NoError(err)
+	ks.Files["khoriumstep.yml"] = []byte("name: release")
+
+	copied := ks.Copy()
+	assert.Equal(ks, copied)
+
+	copied.Inputs["target"].Options[0] = "changed"
+	copied.Outputs["version"].Description = "changed"
+	copied.Run.Steps[0].With["target"] = "changed"
+	delete(copied.Files, "khoriumstep.yml")
+	assert.Equal("binary", ks.Inputs["target"].Options[0])
+	assert.Equal("", ks.Outputs["version"].Description)
+	assert.Equal("{{ inputs.target }}", ks.Run.Steps[0].With["target"])
+	assert.Contains(ks.Files, "khoriumstep.yml")
+}
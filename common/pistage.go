@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -22,6 +23,21 @@ type Pistage struct {
 	Environment map[string]string `yaml:"env" json:"env"`
 	Executor    string            `yaml:"executor" json:"executor"`
 
+	// Priority of pistage, pistages and their jobs with higher priority are scheduled first.
+	Priority int `yaml:"priority" json:"priority,omitempty"`
+
+	// Inputs are the parameters given when applying, referenced by {{ vars.NAME }},
+	// Vars are the values given, they're stored with the Run instead of content,
+	// so one snapshot can be run with different values.
+	Inputs Inputs            `yaml:"inputs" json:"inputs,omitempty"`
+	Vars   map[string]string `yaml:"-" json:"-"`
+
+	// Bundle holds the files of KhoriumSteps relative to spec,
+	// only the digest is included in content, so changes of local steps
+	// are also changes of pistage.
+	Bundle       map[string][]byte `yaml:"-" json:"-"`
+	BundleDigest string            `yaml:"-" json:"bundle_digest,omitempty"`
+
 	Content     []byte `yaml:"-" json:"-"`
 	ContentHash string `yaml:"-" json:"-"`
 }
@@ -33,13 +49,23 @@ func (p *Pistage) init() {
 	}
 }
 
-// validate currently checks only if the dependency graph contains a cycle.
+// validate checks if all jobs depended on exist, if the dependency graph contains a cycle,
+// and if the definitions of inputs are valid.
+// See ValidateSpec for a thorough check.
 func (p *Pistage) validate() error {
 	tp := newTopo()
 	for _, job := range p.Jobs {
+		for _, dependency := range job.DependsOn {
+			if _, ok := p.Jobs[dependency]; !ok {
+				return errors.WithMessagef(ErrorJobNotFound, "job: %s depends on %s", job.Name, dependency)
+			}
+		}
 		tp.addDependencies(job.Name, job.DependsOn...)
 	}
-	return tp.checkCyclic()
+	if err := tp.checkCyclic(); err != nil {
+		return err
+	}
+	return p.Inputs.validate()
 }
 
 func (p *Pistage) GenerateHash() error {
@@ -62,6 +88,27 @@ func (p *Pistage) GenerateHash() error {
 	return nil
 }
 
+// SetBundle sets the bundle of spec, along with its digest.
+func (p *Pistage) SetBundle(bundle map[string][]byte) error {
+	if len(bundle) == 0 {
+		p.Bundle, p.BundleDigest = nil, ""
+		return nil
+	}
+
+	content, err := json.Marshal(bundle)
+	if err != nil {
+		return errors.Wrap(err, "marshal bundle")
+	}
+	digest, err := helpers.Sha1HexDigest(content)
+	if err != nil {
+		return errors.Wrap(err, "generate bundle digest")
+	}
+
+	p.Bundle = bundle
+	p.BundleDigest = digest
+	return nil
+}
+
 func UnmarshalPistage(marshalled []byte) (p *Pistage, err error) {
 	err = json.Unmarshal(marshalled, &p)
 	return
@@ -146,16 +193,107 @@ func (p *Pistage) GetJobs(names []string) []*Job {
 	return jobs
 }
 
-// FromSpec build a Pistage from a spec file.
-func FromSpec(content []byte) (*Pistage, error) {
-	p := &Pistage{}
-	err := yaml.Unmarshal(content, p)
-	if err != nil {
-		return nil, err
+// KhoriumStepNames returns the sorted names of all KhoriumSteps
+// used by steps and rollback steps of all jobs.
+func (p *Pistage) KhoriumStepNames() []string {
+	names := map[string]struct{}{}
+	for _, job := range p.Jobs {
+		steps := append(append([]*Step{}, job.Steps...), job.RollbackSteps...)
+		for _, step := range steps {
+			if step.Uses != "" {
+				names[step.Uses] = struct{}{}
+			}
+		}
+	}
+
+	r := make([]string, 0, len(names))
+	for name := range names {
+		r = append(r, name)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// Map returns a copy of p with f applied to all the strings which can be templates,
+// they're images, environments, commands and arguments of jobs, steps and services.
+// p itself is never changed.
+func (p *Pistage) Map(f func(string) string) *Pistage {
+	return p.mapStrings(f, f)
+}
+
+// MapValues is like Map, but commands and arguments of steps are kept as is,
+// they're rendered by pongo2 when the steps are executed.
+func (p *Pistage) MapValues(f func(string) string) *Pistage {
+	return p.mapStrings(f, func(s string) string { return s })
+}
+
+// mapStrings applies f to images and environments, and templates to commands and arguments of steps.
+func (p *Pistage) mapStrings(f, templates func(string) string) *Pistage {
+	copied := *p
+	copied.Environment = mapValues(p.Environment, f)
+	copied.Jobs = map[string]*Job{}
+	for name, job := range p.Jobs {
+		j := *job
+		j.Image = f(job.Image)
+		j.Environment = mapValues(job.Environment, f)
+		j.Steps = mapSteps(job.Steps, f, templates)
+		j.RollbackSteps = mapSteps(job.RollbackSteps, f, templates)
+		if job.Services != nil {
+			j.Services = map[string]*Service{}
+			for serviceName, service := range job.Services {
+				s := *service
+				s.Image = f(service.Image)
+				s.Command = mapSlice(service.Command, f)
+				s.Environment = mapValues(service.Environment, f)
+				j.Services[serviceName] = &s
+			}
+		}
+		copied.Jobs[name] = &j
+	}
+	return &copied
+}
+
+func mapSteps(steps []*Step, f, templates func(string) string) []*Step {
+	if steps == nil {
+		return nil
+	}
+	r := make([]*Step, 0, len(steps))
+	for _, step := range steps {
+		s := *step
+		s.With = mapValues(step.With, templates)
+		s.Run = mapSlice(step.Run, templates)
+		s.OnError = mapSlice(step.OnError, templates)
+		s.Environment = mapValues(step.Environment, f)
+		r = append(r, &s)
 	}
+	return r
+}
 
-	p.init()
-	return p, p.validate()
+func mapValues(m map[string]string, f func(string) string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		r[k] = f(v)
+	}
+	return r
+}
+
+func mapSlice(s []string, f func(string) string) []string {
+	if s == nil {
+		return nil
+	}
+	r := make([]string, 0, len(s))
+	for _, v := range s {
+		r = append(r, f(v))
+	}
+	return r
+}
+
+// FromSpec build a Pistage from a spec file, which can't have includes.
+func FromSpec(content []byte) (*Pistage, error) {
+	return FromSpecWithLoader(context.Background(), content, nil)
 }
 
 // MarshalPistage marshals pistage back into yaml format.
@@ -187,4 +325,7 @@ type PistageTask struct {
 	// Do remember to close the Output, or find some other methods to
 	// control the halt of the process.
 	Output io.WriteCloser
+
+	// Queued is when the task is added, before waiting for a free stager worker, in epoch millis.
+	Queued int64
 }
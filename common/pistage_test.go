@@ -3,6 +3,7 @@ package common
 import (
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,3 +32,93 @@ func TestGenerateHash(t *testing.T) {
 	a.NoError(p2.GenerateHash())
 	a.Equal(p1.ContentHash, p2.ContentHash)
 }
+
+func TestKhoriumStepNames(t *testing.T) {
+	a := assert.New(t)
+	p := &Pistage{
+		Jobs: map[string]*Job{
+			"job1": {
+				Steps:         []*Step{{Uses: "github.com/test/b"}, {Run: []string{"echo"}}},
+				RollbackSteps: []*Step{{Uses: "github.com/test/c@v1"}},
+			},
+			"job2": {
+				Steps: []*Step{{Uses: "github.com/test/b"}, {Uses: "github.com/test/a"}},
+			},
+		},
+	}
+	a.Equal([]string{"github.com/test/a", "github.com/test/b", "github.com/test/c@v1"}, p.KhoriumStepNames())
+}
+
+func TestSetBundle(t *testing.T) {
+	a := assert.New(t)
+	hash := func(bundle map[string][]byte) string {
+		p := &Pistage{WorkflowType: "t", WorkflowIdentifier: "i"}
+		a.NoError(p.SetBundle(bundle))
+		a.NoError(p.GenerateHash())
+		return p.ContentHash
+	}
+
+	a.Equal(hash(nil), hash(map[string][]byte{}))
+	a.NotEqual(hash(nil), hash(map[string][]byte{"steps/a/main.sh": []byte("v1")}))
+	a.NotEqual(hash(map[string][]byte{"steps/a/main.sh": []byte("v1")}), hash(map[string][]byte{"steps/a/main.sh": []byte("v2")}))
+}
+
+func TestPistageWithVars(t *testing.T) {
+	a := assert.New(t)
+
+	p, err := FromSpec([]byte(`
+inputs:
+  version:
+    required: true
+    pattern: v[0-9]+
+  region:
+    type: enum
+    options: [sg, us]
+    default: sg
+jobs:
+  deploy:
+    image: "alpine:{{ vars.version }}"
+    env:
+      REGION: "{{ vars.region }}"
+    steps:
+      - name: deploy
+        run:
+          - deploy {{ vars.version }} {{ vars.region | upper }}
+`))
+	a.NoError(err)
+	a.NoError(p.GenerateHash())
+	content := string(p.Content)
+
+	p.Vars = map[string]string{"version": "v2"}
+	r, err := p.WithVars()
+	a.NoError(err)
+	a.Equal(map[string]string{"version": "v2", "region": "sg"}, r.Vars)
+	r = r.RenderVars()
+	a.Equal("alpine:v2", r.Jobs["deploy"].Image)
+	a.Equal("sg", r.Jobs["deploy"].Environment["REGION"])
+	// commands are left to be rendered with vars
+	a.Equal("deploy {{ vars.version }} {{ vars.region | upper }}", r.Jobs["deploy"].Steps[0].Run[0])
+	a.Equal("alpine:{{ vars.version }}", p.Jobs["deploy"].Image)
+	a.Equal(content, string(p.Content))
+
+	// values are never parsed as templates
+	p.Vars = map[string]string{"version": "{{ secrets.X }}", "region": "{% if"}
+	r = p.RenderVars()
+	a.Equal("alpine:{{ secrets.X }}", r.Jobs["deploy"].Image)
+	a.Equal("{% if", r.Jobs["deploy"].Environment["REGION"])
+	a.Equal("deploy {{ vars.version }} {{ vars.region | upper }}", r.Jobs["deploy"].Steps[0].Run[0])
+
+	_, err = p.ResolveVars(map[string]string{"region": "eu", "verison": "v2"})
+	a.True(errors.Is(err, ErrorUnknownInput))
+	a.True(errors.Is(err, ErrorInputIsRequired))
+	a.True(errors.Is(err, ErrorInputIsInvalid))
+
+	_, err = FromSpec([]byte(`
+inputs:
+  region:
+    type: enum
+jobs:
+  deploy: {}
+`))
+	a.True(errors.Is(err, ErrorBadInputDefinition))
+}
@@ -0,0 +1,172 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrorUnknownSchema is returned when the schema asked for doesn't exist.
+var ErrorUnknownSchema = errors.New("Unknown schema")
+
+// Kinds of JSON Schema.
+const (
+	SchemaPistage     = "pistage"
+	SchemaKhoriumStep = "khoriumstep"
+)
+
+// JSONSchema returns the JSON Schema of kind, the spec of pistage or khoriumstep.yml.
+// It's generated from the yaml tags of the structs, so new fields are always in it.
+func JSONSchema(kind string) ([]byte, error) {
+	var (
+		t     reflect.Type
+		title string
+	)
+	switch kind {
+	case SchemaPistage:
+		t, title = reflect.TypeOf(specDocument{}), "Pistage spec"
+	case SchemaKhoriumStep:
+		t, title = reflect.TypeOf(KhoriumStep{}), "KhoriumStep spec, khoriumstep.yml"
+	default:
+		return nil, errors.WithMessagef(ErrorUnknownSchema, "kind: %s", kind)
+	}
+
+	g := &schemaGenerator{definitions: map[string]interface{}{}}
+	schema := g.object(t)
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = title
+	schema["definitions"] = g.definitions
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// jsonSchemaer is implemented by types having schemas other than their Go types,
+// usually types parsed by UnmarshalYAML.
+type jsonSchemaer interface {
+	jsonSchema(g *schemaGenerator) map[string]interface{}
+}
+
+var jsonSchemaerType = reflect.TypeOf((*jsonSchemaer)(nil)).Elem()
+
+type schemaGenerator struct {
+	definitions map[string]interface{}
+}
+
+// schema returns the schema of t, structs are referenced from definitions.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Implements(jsonSchemaerType) {
+		return reflect.Zero(t).Interface().(jsonSchemaer).jsonSchema(g)
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.ref(t)
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// ref returns the reference of struct t, which is added to definitions by the name of t.
+func (g *schemaGenerator) ref(t reflect.Type) map[string]interface{} {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, ok := g.definitions[name]; !ok {
+		// added before generated, so structs referencing themselves end.
+		g.definitions[name] = nil
+		g.definitions[name] = g.object(t)
+	}
+	return map[string]interface{}{"$ref": "#/definitions/" + name}
+}
+
+// object returns the schema of struct t, keys starting with x- are allowed, see ValidateSpec.
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for name, ft := range schemaFields(t) {
+		properties[name] = g.schema(ft)
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"patternProperties":    map[string]interface{}{"^x-": map[string]interface{}{}},
+		"additionalProperties": false,
+	}
+}
+
+func (ByteSize) jsonSchema(g *schemaGenerator) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    []string{"integer", "string"},
+		"pattern": `^[0-9.]+\s*([KMGTkmgt][Ii]?[Bb]?)?$`,
+	}
+}
+
+// specNames is one name or a list of names, like extends of job.
+type specNames []string
+
+// UnmarshalYAML parses one name as a list of itself.
+func (n *specNames) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*n = specNames{node.Value}
+		return nil
+	}
+	var names []string
+	if err := node.Decode(&names); err != nil {
+		return err
+	}
+	*n = names
+	return nil
+}
+
+func (specNames) jsonSchema(g *schemaGenerator) map[string]interface{} {
+	name := map[string]interface{}{"type": "string"}
+	return map[string]interface{}{
+		"oneOf": []interface{}{name, map[string]interface{}{"type": "array", "items": name}},
+	}
+}
+
+// specIncludes is include of spec, one include or a list of includes, a string is a local include.
+type specIncludes []Include
+
+// UnmarshalYAML parses includes like parseIncludes.
+func (i *specIncludes) UnmarshalYAML(node *yaml.Node) error {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	for _, item := range items {
+		include := Include{Local: item.Value}
+		if item.Kind != yaml.ScalarNode {
+			include.Local = ""
+			if err := item.Decode(&include); err != nil {
+				return err
+			}
+		}
+		*i = append(*i, include)
+	}
+	return nil
+}
+
+func (specIncludes) jsonSchema(g *schemaGenerator) map[string]interface{} {
+	include := map[string]interface{}{
+		"oneOf": []interface{}{map[string]interface{}{"type": "string"}, g.ref(reflect.TypeOf(Include{}))},
+	}
+	return map[string]interface{}{
+		"oneOf": []interface{}{include, map[string]interface{}{"type": "array", "items": include}},
+	}
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	content, err := JSONSchema(SchemaPistage)
+	assert.NoError(err)
+	schema := map[string]interface{}{}
+	assert.NoError(json.Unmarshal(content, &schema))
+
+	properties := schema["properties"].(map[string]interface{})
+	for _, name := range []string{"workflow_type", "jobs", "env", "executor", "inputs", "include", "templates"} {
+		assert.Contains(properties, name)
+	}
+	assert.Equal(false, schema["additionalProperties"])
+
+	// all the fields of job are in schema, along with the fields only in spec.
+	definitions := schema["definitions"].(map[string]interface{})
+	job := definitions["SpecJob"].(map[string]interface{})["properties"].(map[string]interface{})
+	for name := range schemaFields(reflect.TypeOf(Job{})) {
+		assert.Contains(job, name)
+	}
+	for _, name := range []string{"extends", "template", "with"} {
+		assert.Contains(job, name)
+	}
+	assert.Equal(map[string]interface{}{"$ref": "#/definitions/Step"}, job["steps"].(map[string]interface{})["items"])
+	assert.Contains(definitions, "Include")
+	assert.Contains(definitions, "Resources")
+
+	content, err = JSONSchema(SchemaKhoriumStep)
+	assert.NoError(err)
+	schema = map[string]interface{}{}
+	assert.NoError(json.Unmarshal(content, &schema))
+	properties = schema["properties"].(map[string]interface{})
+	for _, name := range []string{"name", "description", "inputs", "run", "outputs"} {
+		assert.Contains(properties, name)
+	}
+	assert.NotContains(properties, "files")
+	assert.NotContains(properties, "commit")
+
+	_, err = JSONSchema("unknown")
+	assert.ErrorIs(err, ErrorUnknownSchema)
+}
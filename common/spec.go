@@ -0,0 +1,546 @@
+package common
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projecteru2/pistage/helpers/variable"
+)
+
+var (
+	// ErrorBadInclude is returned when an include has none or more than one source, or escapes its base.
+	ErrorBadInclude = errors.New("Bad include")
+
+	// ErrorIncludeCycle is returned when specs include each other.
+	ErrorIncludeCycle = errors.New("Include cycle")
+
+	// ErrorIncludeTooDeep is returned when includes are nested deeper than MaxIncludeDepth.
+	ErrorIncludeTooDeep = errors.New("Include too deep")
+
+	// ErrorNoIncludeLoader is returned when spec has includes but they can't be loaded.
+	ErrorNoIncludeLoader = errors.New("No loader for includes")
+
+	// ErrorExtendsCycle is returned when jobs extend each other.
+	ErrorExtendsCycle = errors.New("Extends cycle")
+
+	// ErrorExtendsNotFound is returned when the job extended doesn't exist.
+	ErrorExtendsNotFound = errors.New("Extended job not found")
+
+	// ErrorTemplateNotFound is returned when the template of job doesn't exist.
+	ErrorTemplateNotFound = errors.New("Job template not found")
+)
+
+// MaxIncludeDepth is the max depth of nested includes.
+var MaxIncludeDepth = 8
+
+// Include is a spec file included by spec, exactly one of the sources is set:
+//   - Local is a path relative to the including spec, read from the bundle of spec.
+//   - Git is like github.com/org/pipelines//go/service.yml@v1, the file go/service.yml of the repository at v1.
+//   - Template is the name of a template stored on pistage server.
+//
+// Local includes of an included spec are relative to it, in the same source.
+type Include struct {
+	Local    string `yaml:"local"`
+	Git      string `yaml:"git"`
+	Template string `yaml:"template"`
+}
+
+func (i Include) String() string {
+	switch {
+	case i.Git != "":
+		return "git:" + i.Git
+	case i.Template != "":
+		return "template:" + i.Template
+	default:
+		return "local:" + i.Local
+	}
+}
+
+// relative returns the include of local, which is relative to i.
+func (i Include) relative(local string) (Include, error) {
+	join := func(base string) (string, error) {
+		p := path.Join(path.Dir(base), local)
+		if path.IsAbs(local) || p == ".." || strings.HasPrefix(p, "../") {
+			return "", errors.WithMessagef(ErrorBadInclude, "%s escapes %s", local, i)
+		}
+		return p, nil
+	}
+
+	var err error
+	switch {
+	case i.Git != "":
+		repository, version := i.Git, ""
+		if n := strings.LastIndex(repository, "@"); n >= 0 {
+			repository, version = repository[:n], repository[n:]
+		}
+		parts := strings.SplitN(repository, "//", 2)
+		if len(parts) != 2 {
+			return Include{}, errors.WithMessagef(ErrorBadInclude, "git: %s", i.Git)
+		}
+		file, err := join(parts[1])
+		return Include{Git: parts[0] + "//" + file + version}, err
+	case i.Template != "":
+		i.Template, err = join(i.Template)
+		return Include{Template: i.Template}, err
+	default:
+		i.Local, err = join(i.Local)
+		return Include{Local: i.Local}, err
+	}
+}
+
+// IncludeLoader loads the content of included specs.
+type IncludeLoader interface {
+	LoadInclude(ctx context.Context, include Include) ([]byte, error)
+}
+
+// jobTemplate is a job parameterized by inputs, referenced by {{ inputs.NAME }} within job.
+type jobTemplate struct {
+	inputs Inputs
+	job    map[string]interface{}
+}
+
+// FromSpecWithLoader builds a Pistage from a spec, with includes loaded by loader.
+// The spec is flattened before it's built:
+//   - Included specs are merged in order, then the spec itself is merged on top of them.
+//   - Jobs with extends are merged on top of the jobs they extend in order,
+//     jobs named with a leading dot are only for extending, they're removed after.
+//   - Jobs with template are merged on top of the template rendered with inputs given by with,
+//     templates are defined in templates, each with inputs and job.
+//
+// Maps are merged key by key, other values including lists are replaced.
+// YAML anchors and merge keys work within each file as usual.
+// So the Pistage, and its content saved as snapshot, is always fully resolved.
+func FromSpecWithLoader(ctx context.Context, content []byte, loader IncludeLoader) (*Pistage, error) {
+	doc, err := newSpecLoader(loader).load(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pistage{}
+	if err := convert(doc, p); err != nil {
+		return nil, err
+	}
+	p.init()
+	return p, p.validate()
+}
+
+// specLoader loads specs along with the specs they include,
+// and remembers where the nodes are from, so errors can be located.
+type specLoader struct {
+	loader IncludeLoader
+
+	// files are the names of specs the nodes are from, see SpecError.
+	files map[*yaml.Node]string
+	// roots are the documents loaded, the spec itself first.
+	roots []*yaml.Node
+}
+
+func newSpecLoader(loader IncludeLoader) *specLoader {
+	return &specLoader{loader: loader, files: map[*yaml.Node]string{}}
+}
+
+// load loads spec, and returns the flattened document.
+func (s *specLoader) load(ctx context.Context, content []byte) (map[string]interface{}, error) {
+	doc, err := s.loadDocument(ctx, content, Include{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doc, s.flattenJobs(doc)
+}
+
+// locate returns err located at node, err is returned as is if node is nil.
+func (s *specLoader) locate(node *yaml.Node, err error) error {
+	if node == nil {
+		return err
+	}
+	return &SpecError{File: s.files[node], Line: node.Line, Column: node.Column, Message: err.Error(), err: err}
+}
+
+// index remembers all nodes under node are from file.
+func (s *specLoader) index(file string, node *yaml.Node) {
+	s.files[node] = file
+	for _, n := range node.Content {
+		s.index(file, n)
+	}
+}
+
+// loadDocument parses content of include, and merges it on top of the specs it includes,
+// stack holds the includes being loaded, to find cycles.
+func (s *specLoader) loadDocument(ctx context.Context, content []byte, include Include, stack []string) (map[string]interface{}, error) {
+	file := specFileName(include)
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, syntaxError(file, err)
+	}
+	s.index(file, &node)
+	s.roots = append(s.roots, &node)
+
+	doc, _ := decodeNode(&node).(map[string]interface{})
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+
+	includes, nodes, err := parseIncludes(doc["include"])
+	if err != nil {
+		return nil, s.locate(firstNode(doc["include"]), err)
+	}
+	delete(doc, "include")
+	if len(includes) == 0 {
+		return doc, nil
+	}
+	if s.loader == nil {
+		return nil, s.locate(nodes[0], ErrorNoIncludeLoader)
+	}
+
+	merged := map[string]interface{}{}
+	for i, inc := range includes {
+		if len(stack) >= MaxIncludeDepth {
+			return nil, s.locate(nodes[i], errors.WithMessagef(ErrorIncludeTooDeep, "include: %s", strings.Join(stack, " -> ")))
+		}
+		if inc.Local != "" {
+			if inc, err = include.relative(inc.Local); err != nil {
+				return nil, s.locate(nodes[i], err)
+			}
+		}
+		for _, f := range stack {
+			if f == inc.String() {
+				return nil, s.locate(nodes[i], errors.WithMessagef(ErrorIncludeCycle, "include: %s -> %s", strings.Join(stack, " -> "), inc))
+			}
+		}
+
+		included, err := s.loader.LoadInclude(ctx, inc)
+		if err != nil {
+			return nil, s.locate(nodes[i], errors.WithMessagef(err, "include: %s", inc))
+		}
+		child, err := s.loadDocument(ctx, included, inc, append(stack, inc.String()))
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeMaps(merged, child)
+	}
+	return mergeMaps(merged, doc), nil
+}
+
+// specFileName returns the name of spec of include, see SpecError.
+func specFileName(include Include) string {
+	if include == (Include{}) {
+		return ""
+	}
+	return include.String()
+}
+
+// parseIncludes parses include of spec, a string is a local include,
+// nodes are where the includes are.
+func parseIncludes(value interface{}) ([]Include, []*yaml.Node, error) {
+	if value == nil {
+		return nil, nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+
+	var (
+		includes []Include
+		nodes    []*yaml.Node
+	)
+	for _, item := range items {
+		var include Include
+		if local, ok := scalar(item); ok {
+			include.Local = local
+		} else if err := convert(item, &include); err != nil {
+			return nil, nil, errors.WithMessagef(ErrorBadInclude, "%v", err)
+		}
+
+		sources := 0
+		for _, source := range []string{include.Local, include.Git, include.Template} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return nil, nil, errors.WithMessagef(ErrorBadInclude, "%+v", include)
+		}
+		includes = append(includes, include)
+		nodes = append(nodes, firstNode(item))
+	}
+	return includes, nodes, nil
+}
+
+// flattenJobs resolves extends and templates of all jobs in doc,
+// hidden jobs and templates are removed after.
+func (s *specLoader) flattenJobs(doc map[string]interface{}) error {
+	templates := map[string]*jobTemplate{}
+	definitions, _ := doc["templates"].(map[string]interface{})
+	for name, definition := range definitions {
+		d, _ := definition.(map[string]interface{})
+		template := &jobTemplate{}
+		if err := convert(d["inputs"], &template.inputs); err != nil {
+			return s.locate(firstNode(d["inputs"]), errors.WithMessagef(err, "template: %s", name))
+		}
+		template.job, _ = d["job"].(map[string]interface{})
+		templates[name] = template
+	}
+	delete(doc, "templates")
+
+	jobs, _ := doc["jobs"].(map[string]interface{})
+	resolved := map[string]map[string]interface{}{}
+	// ref is where the job is extended, nil for jobs resolved by names.
+	var resolve func(name string, ref *yaml.Node, stack []string) (map[string]interface{}, error)
+	resolve = func(name string, ref *yaml.Node, stack []string) (map[string]interface{}, error) {
+		if job, ok := resolved[name]; ok {
+			return job, nil
+		}
+		for _, f := range stack {
+			if f == name {
+				return nil, s.locate(ref, errors.WithMessagef(ErrorExtendsCycle, "job: %s -> %s", strings.Join(stack, " -> "), name))
+			}
+		}
+		raw, ok := jobs[name]
+		if !ok {
+			return nil, s.locate(ref, errors.WithMessagef(ErrorExtendsNotFound, "job: %s", strings.Join(append(stack, name), " -> ")))
+		}
+		job, _ := raw.(map[string]interface{})
+
+		base := map[string]interface{}{}
+		for _, extended := range nodeList(job["extends"]) {
+			parent, err := resolve(extended.Value, extended, append(stack, name))
+			if err != nil {
+				return nil, err
+			}
+			base = mergeMaps(base, parent)
+		}
+		if template, ok := scalar(job["template"]); ok {
+			rendered, err := renderJobTemplate(templates, template, job["with"])
+			if err != nil {
+				return nil, s.locate(firstNode(job["template"]), errors.WithMessagef(err, "job: %s", name))
+			}
+			base = mergeMaps(base, rendered)
+		}
+
+		own := map[string]interface{}{}
+		for k, v := range job {
+			if k != "extends" && k != "template" && k != "with" {
+				own[k] = v
+			}
+		}
+		resolved[name] = mergeMaps(base, own)
+		return resolved[name], nil
+	}
+
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		job, err := resolve(name, nil, nil)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(name, ".") {
+			delete(jobs, name)
+			continue
+		}
+		jobs[name] = job
+	}
+	return nil
+}
+
+// renderJobTemplate renders the template name with the values of inputs given by with.
+func renderJobTemplate(templates map[string]*jobTemplate, name string, with interface{}) (map[string]interface{}, error) {
+	template, ok := templates[name]
+	if !ok {
+		return nil, errors.WithMessagef(ErrorTemplateNotFound, "template: %s", name)
+	}
+	if err := template.inputs.validate(); err != nil {
+		return nil, errors.WithMessagef(err, "template: %s", name)
+	}
+
+	vars := map[string]string{}
+	if values, ok := with.(map[string]interface{}); ok {
+		for k, v := range values {
+			vars[k], _ = scalar(v)
+		}
+	}
+	inputs, err := template.inputs.Resolve(vars)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "template: %s", name)
+	}
+
+	rendered, _ := mapStrings(template.job, func(s string) string { return variable.RenderInputs(s, inputs) }).(map[string]interface{})
+	if rendered == nil {
+		rendered = map[string]interface{}{}
+	}
+	return rendered, nil
+}
+
+// mergeMaps returns lower with higher merged on top, maps are merged key by key,
+// other values are replaced. Neither lower nor higher is changed.
+func mergeMaps(lower, higher map[string]interface{}) map[string]interface{} {
+	r := make(map[string]interface{}, len(lower)+len(higher))
+	for k, v := range lower {
+		r[k] = v
+	}
+	for k, v := range higher {
+		lv, lok := r[k].(map[string]interface{})
+		hv, hok := v.(map[string]interface{})
+		if lok && hok {
+			r[k] = mergeMaps(lv, hv)
+			continue
+		}
+		r[k] = v
+	}
+	return r
+}
+
+// mapStrings returns a copy of value with f applied to all the strings within.
+func mapStrings(value interface{}, f func(string) string) interface{} {
+	switch v := value.(type) {
+	case *yaml.Node:
+		if v.Tag != "!!str" {
+			return v
+		}
+		copied := *v
+		copied.Value = f(v.Value)
+		return &copied
+	case map[string]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			r[k] = mapStrings(item, f)
+		}
+		return r
+	case []interface{}:
+		r := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			r = append(r, mapStrings(item, f))
+		}
+		return r
+	default:
+		return v
+	}
+}
+
+// nodeList returns value as a list of scalar nodes, a single scalar is a list of itself.
+func nodeList(value interface{}) []*yaml.Node {
+	if node, ok := value.(*yaml.Node); ok {
+		return []*yaml.Node{node}
+	}
+	items, _ := value.([]interface{})
+	r := make([]*yaml.Node, 0, len(items))
+	for _, item := range items {
+		if node, ok := item.(*yaml.Node); ok {
+			r = append(r, node)
+		}
+	}
+	return r
+}
+
+// firstNode returns the first scalar node within value decoded by decodeNode,
+// keys of maps are sorted, nil is returned if there's none.
+func firstNode(value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case *yaml.Node:
+		return v
+	case []interface{}:
+		for _, item := range v {
+			if node := firstNode(item); node != nil {
+				return node
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if node := firstNode(v[k]); node != nil {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// decodeNode decodes node into maps and lists, with aliases and merge keys resolved.
+// Scalars are kept as nodes, so they're decoded later by the types of fields,
+// e.g. 1.10 is still "1.10" for a string field, but not 1.1.
+func decodeNode(node *yaml.Node) interface{} {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return decodeNode(node.Content[0])
+	case yaml.AliasNode:
+		return decodeNode(node.Alias)
+	case yaml.SequenceNode:
+		r := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			r = append(r, decodeNode(item))
+		}
+		return r
+	case yaml.MappingNode:
+		// keys given explicitly override merged ones,
+		// for a list of merged maps, the former ones override the latter ones.
+		r := map[string]interface{}{}
+		merged := map[string]interface{}{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag != "!!merge" {
+				r[key.Value] = decodeNode(value)
+				continue
+			}
+			values := []interface{}{decodeNode(value)}
+			if list, ok := values[0].([]interface{}); ok {
+				values = list
+			}
+			for _, v := range values {
+				m, _ := v.(map[string]interface{})
+				for k, mv := range m {
+					if _, ok := merged[k]; !ok {
+						merged[k] = mv
+					}
+				}
+			}
+		}
+		for k, v := range merged {
+			if _, ok := r[k]; !ok {
+				r[k] = v
+			}
+		}
+		return r
+	default:
+		if node.Tag == "!!null" {
+			return nil
+		}
+		return node
+	}
+}
+
+// scalar returns the value of a scalar decoded by decodeNode.
+func scalar(value interface{}) (string, bool) {
+	node, ok := value.(*yaml.Node)
+	if !ok || node.Kind != yaml.ScalarNode {
+		return "", false
+	}
+	return node.Value, true
+}
+
+// convert converts the decoded YAML value in into out.
+func convert(in, out interface{}) error {
+	if in == nil {
+		return nil
+	}
+	content, err := yaml.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(content, out)
+}
@@ -0,0 +1,172 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapIncludeLoader map[string]string
+
+func (m mapIncludeLoader) LoadInclude(ctx context.Context, include Include) ([]byte, error) {
+	content, ok := m[include.String()]
+	if !ok {
+		return nil, errors.Errorf("not found: %s", include)
+	}
+	return []byte(content), nil
+}
+
+func TestFromSpecWithLoader(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	loader := mapIncludeLoader{
+		"local:ci/base.yml": `
+include:
+  - common.yml
+env:
+  LEVEL: base
+jobs:
+  .go:
+    image: golang:1.10
+    env:
+      GOFLAGS: -mod=mod
+      CGO_ENABLED: "0"
+    steps:
+      - name: test
+        run: ["go test ./..."]
+`,
+		"local:ci/common.yml": `
+executor: docker
+env:
+  LEVEL: common
+  SHARED: "yes"
+`,
+		"git:example.com/org/pipelines//go/deploy.yml@v1": `
+include:
+  - templates.yml
+`,
+		"git:example.com/org/pipelines//go/templates.yml@v1": `
+templates:
+  deploy:
+    inputs:
+      app:
+        required: true
+      replicas:
+        default: "1"
+    job:
+      image: deployer
+      depends_on: [build]
+      steps:
+        - name: deploy
+          run: ["deploy {{ inputs.app }} --replicas {{ inputs.replicas }}"]
+`,
+	}
+
+	p, err := FromSpecWithLoader(ctx, []byte(`
+workflow_type: test
+workflow_identifier: service
+include:
+  - ci/base.yml
+  - git: example.com/org/pipelines//go/deploy.yml@v1
+env:
+  LEVEL: service
+x-defaults: &defaults
+  timeout: 60
+jobs:
+  build:
+    <<: *defaults
+    extends: .go
+    env:
+      CGO_ENABLED: "1"
+  deploy:
+    template: deploy
+    with:
+      app: service
+      replicas: 010
+`), loader)
+	assert.NoError(err)
+	assert.Equal("docker", p.Executor)
+	assert.Equal(map[string]string{"LEVEL": "service", "SHARED": "yes"}, p.Environment)
+
+	// hidden jobs are removed after extended.
+	assert.Len(p.Jobs, 2)
+	build := p.Jobs["build"]
+	assert.Equal("build", build.Name)
+	assert.Equal("golang:1.10", build.Image)
+	assert.Equal(60, build.Timeout)
+	assert.Equal(map[string]string{"GOFLAGS": "-mod=mod", "CGO_ENABLED": "1"}, build.Environment)
+	assert.Equal([]string{"go test ./..."}, build.Steps[0].Run)
+
+	deploy := p.Jobs["deploy"]
+	assert.Equal("deployer", deploy.Image)
+	assert.Equal([]string{"build"}, deploy.DependsOn)
+	assert.Equal([]string{"deploy service --replicas 010"}, deploy.Steps[0].Run)
+
+	// the content is fully resolved.
+	assert.NoError(p.GenerateHash())
+	assert.NotContains(string(p.Content), "include")
+	assert.Contains(string(p.Content), "deploy service --replicas 010")
+}
+
+func TestFromSpecWithLoaderErrors(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	_, err := FromSpec([]byte("include: [base.yml]"))
+	assert.ErrorIs(err, ErrorNoIncludeLoader)
+
+	loader := mapIncludeLoader{
+		"local:a.yml":      "include: [b/b.yml]",
+		"local:b/b.yml":    "include: [../a.yml]",
+		"local:escape.yml": "include: [../../etc/passwd]",
+	}
+	_, err = FromSpecWithLoader(ctx, []byte("include: [a.yml]"), loader)
+	assert.ErrorIs(err, ErrorIncludeCycle)
+
+	_, err = FromSpecWithLoader(ctx, []byte("include: [escape.yml]"), loader)
+	assert.ErrorIs(err, ErrorBadInclude)
+
+	_, err = FromSpecWithLoader(ctx, []byte("include: [{local: a.yml, template: a}]"), loader)
+	assert.ErrorIs(err, ErrorBadInclude)
+
+	_, err = FromSpec([]byte(`
+jobs:
+  a:
+    extends: b
+  b:
+    extends: [a]
+`))
+	assert.ErrorIs(err, ErrorExtendsCycle)
+
+	_, err = FromSpec([]byte(`
+jobs:
+  a:
+    extends: .missing
+`))
+	assert.ErrorIs(err, ErrorExtendsNotFound)
+
+	_, err = FromSpec([]byte(`
+jobs:
+  a:
+    template: missing
+`))
+	assert.ErrorIs(err, ErrorTemplateNotFound)
+
+	var inputErrors InputErrors
+	_, err = FromSpec([]byte(`
+templates:
+  t:
+    inputs:
+      app:
+        required: true
+    job:
+      image: "{{ inputs.app }}"
+jobs:
+  a:
+    template: t
+`))
+	assert.True(errors.As(err, &inputErrors))
+}
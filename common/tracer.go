@@ -3,6 +3,7 @@ package common
 import (
 	"bytes"
 	"io"
+	"sort"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -14,6 +15,7 @@ type LogTracer struct {
 	writer  io.Writer
 	mutex   sync.Mutex
 	tracers []io.Writer
+	masker  *maskWriter
 }
 
 // NewLogTracer creates a LogTracer.
@@ -49,9 +51,31 @@ func (l *LogTracer) Write(p []byte) (int, error) {
 	return l.writer.Write(p)
 }
 
+// Mask replaces values with *** in all the output written later,
+// to buffer and all tracers, so secrets never show up in logs.
+func (l *LogTracer) Mask(values ...string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.masker == nil {
+		l.masker = &maskWriter{writer: l.writer}
+		l.writer = l.masker
+	}
+	l.masker.add(values...)
+}
+
 // Close implements io.Closer.
 // But Close won't close DonCloseWriter.
 func (l *LogTracer) Close() error {
+	l.mutex.Lock()
+	if l.masker != nil {
+		if err := l.masker.flush(); err != nil {
+			l.mutex.Unlock()
+			return err
+		}
+	}
+	l.mutex.Unlock()
+
 	for _, tracer := range l.tracers {
 		if _, ok := tracer.(DonCloseWriter); ok {
 			continue
@@ -83,6 +107,68 @@ func (l *logrusTracer) Write(p []byte) (int, error) {
 	return len(p), nil
 }
 
+// maskWriter replaces values with *** before writing to writer.
+// A value may be split into several writes, so the tail which may be
+// the beginning of a value is held until the next write or flush.
+type maskWriter struct {
+	writer  io.Writer
+	values  [][]byte
+	pending []byte
+}
+
+func (m *maskWriter) add(values ...string) {
+	for _, value := range values {
+		if value != "" {
+			m.values = append(m.values, []byte(value))
+		}
+	}
+	// longer values first, so a value containing another is masked as a whole
+	sort.Slice(m.values, func(i, j int) bool { return len(m.values[i]) > len(m.values[j]) })
+}
+
+// Write implements io.Writer.
+func (m *maskWriter) Write(p []byte) (int, error) {
+	data := append(m.pending, p...)
+	for _, value := range m.values {
+		data = bytes.ReplaceAll(data, value, maskedValue)
+	}
+
+	hold := m.partial(data)
+	m.pending = append([]byte{}, data[len(data)-hold:]...)
+	if len(data) == hold {
+		return len(p), nil
+	}
+	if _, err := m.writer.Write(data[:len(data)-hold]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// partial returns the length of the longest tail of data which is the beginning of a value.
+func (m *maskWriter) partial(data []byte) int {
+	longest := 0
+	for _, value := range m.values {
+		for n := len(value) - 1; n > longest; n-- {
+			if bytes.HasSuffix(data, value[:n]) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
+
+func (m *maskWriter) flush() error {
+	if len(m.pending) == 0 {
+		return nil
+	}
+	_, err := m.writer.Write(m.pending)
+	m.pending = nil
+	return err
+}
+
+var maskedValue = []byte("***")
+
 // DonCloseWriter wraps an io.Writer, to avoid being closed by LogTracer.
 type DonCloseWriter struct {
 	io.Writer
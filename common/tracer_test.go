@@ -0,0 +1,30 @@
+package common
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogTracerMask(t *testing.T) {
+	assert := assert.New(t)
+
+	output := &bytes.Buffer{}
+	l := NewLogTracer("run", output)
+	l.Mask("s3cr3t", "", "s3cr3t-token")
+
+	l.Write([]byte("password is s3cr3t, token is s3cr3t-token\n"))
+	// values split into writes are masked too
+	l.Write([]byte("again: s3c"))
+	l.Write([]byte("r3t and s3"))
+	assert.NoError(l.Close())
+
+	expected := "password is ***, token is ***\nagain: *** and s3"
+	assert.Equal(expected, output.String())
+
+	buffered, err := ioutil.ReadAll(l)
+	assert.NoError(err)
+	assert.Equal(expected, string(buffered))
+}
@@ -0,0 +1,470 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flosch/pongo2/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projecteru2/pistage/helpers/variable"
+)
+
+var (
+	// ErrorUnknownField is returned when spec has a field pistage doesn't know.
+	ErrorUnknownField = errors.New("Unknown field")
+
+	// ErrorDuplicateKey is returned when a key is given more than once in the same map.
+	ErrorDuplicateKey = errors.New("Duplicate key")
+
+	// ErrorBadValue is returned when a value doesn't match the type of field.
+	ErrorBadValue = errors.New("Bad value")
+
+	// ErrorJobHasNoSteps is returned when a job has no steps.
+	ErrorJobHasNoSteps = errors.New("Job has no steps")
+
+	// ErrorRunWithUses is returned when a step has both run and uses.
+	ErrorRunWithUses = errors.New("Can't specify both run and uses")
+
+	// ErrorDuplicateStepName is returned when steps of a job have the same name.
+	ErrorDuplicateStepName = errors.New("Duplicate step name")
+
+	// ErrorUnknownExecutor is returned when the executor of spec isn't available.
+	ErrorUnknownExecutor = errors.New("Unknown executor")
+
+	// ErrorBadTemplate is returned when a template in spec can't be parsed.
+	ErrorBadTemplate = errors.New("Bad template")
+)
+
+var yamlLineRe = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// SpecError is a problem of spec, located by Line and Column in File.
+// File is empty for the spec itself, or the include for specs included, like local:ci/base.yml.
+// Line is 0 if the problem isn't at any specific place of spec.
+type SpecError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+
+	err error
+}
+
+func (e *SpecError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location = strings.TrimPrefix(fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column), ":")
+	}
+	if location == "" {
+		return e.Message
+	}
+	return location + ": " + e.Message
+}
+
+// Unwrap returns the error of problem, so errors.Is works with SpecError.
+func (e *SpecError) Unwrap() error {
+	return e.err
+}
+
+// syntaxError returns the yaml error of file as a SpecError.
+func syntaxError(file string, err error) error {
+	e := &SpecError{File: file, Message: err.Error(), err: err}
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+		e.Message = m[2]
+	}
+	return e
+}
+
+// SpecErrors holds all the problems of spec, so they can be reported at once.
+type SpecErrors []*SpecError
+
+func (e SpecErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is tells if any of the errors is target, so errors.Is works with SpecErrors.
+func (e SpecErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// specDocument is the schema of spec files, with the fields only known before flattened.
+type specDocument struct {
+	Pistage   `yaml:",inline"`
+	Include   specIncludes             `yaml:"include"`
+	Templates map[string]*specTemplate `yaml:"templates"`
+	Jobs      map[string]*specJob      `yaml:"jobs"`
+}
+
+type specTemplate struct {
+	Inputs Inputs `yaml:"inputs"`
+	Job    *Job   `yaml:"job"`
+}
+
+type specJob struct {
+	Job      `yaml:",inline"`
+	Extends  specNames         `yaml:"extends"`
+	Template string            `yaml:"template"`
+	With     map[string]string `yaml:"with"`
+}
+
+var unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// ValidateSpec checks spec with includes loaded by loader, and returns all the problems found,
+// each located in the spec or the include it's from.
+// Besides what FromSpecWithLoader checks, it finds unknown fields, values of wrong types, duplicate keys,
+// dependencies on jobs not defined, jobs without steps, steps with both or neither of run and uses,
+// steps with the same name, templates can't be parsed, and vars not declared as inputs.
+// executors are the names of executors available, executor of spec isn't checked if it's empty.
+// Keys starting with x- are ignored, they can be used to hold anchors.
+func ValidateSpec(ctx context.Context, content []byte, loader IncludeLoader, executors []string) SpecErrors {
+	s := newSpecLoader(loader)
+	doc, err := s.load(ctx, content)
+	v := &specValidator{files: s.files, keys: map[string]*yaml.Node{}}
+
+	for _, root := range s.roots {
+		v.walk(root, reflect.TypeOf(specDocument{}), "")
+	}
+	if err != nil {
+		v.add(err)
+		return v.result()
+	}
+
+	// values of wrong types are already found, the rest are still decoded.
+	p := &Pistage{}
+	if err := convert(doc, p); err != nil {
+		var typeError *yaml.TypeError
+		if len(v.errs) == 0 || !errors.As(err, &typeError) {
+			v.add(err)
+			return v.result()
+		}
+	}
+	p.init()
+
+	v.checkExecutor(p, doc, executors)
+	v.checkJobs(p, doc)
+	v.checkTemplates(p, doc)
+	return v.result()
+}
+
+type specValidator struct {
+	// files are the names of specs the nodes are from.
+	files map[*yaml.Node]string
+	// keys are the key nodes of maps by their paths, like jobs.build,
+	// the spec itself is walked first, so keys in it win.
+	keys map[string]*yaml.Node
+	errs SpecErrors
+}
+
+// add adds err, located if it's a SpecError.
+func (v *specValidator) add(err error) {
+	var e *SpecError
+	if !errors.As(err, &e) {
+		e = &SpecError{Message: err.Error(), err: err}
+	}
+	v.errs = append(v.errs, e)
+}
+
+// at adds err located at node, or at the key of path if node is nil.
+func (v *specValidator) at(node *yaml.Node, path string, err error) {
+	if node == nil {
+		node = v.keys[path]
+	}
+	e := &SpecError{Message: err.Error(), err: err}
+	if node != nil {
+		e.File, e.Line, e.Column = v.files[node], node.Line, node.Column
+	}
+	v.errs = append(v.errs, e)
+}
+
+// result returns the errors sorted by where they are, the spec itself first.
+func (v *specValidator) result() SpecErrors {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		a, b := v.errs[i], v.errs[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.errs
+}
+
+// walk checks node against type t, path is where node is, like jobs.build.steps[0].
+// Aliases are checked where the anchors are, and merge keys are not checked.
+func (v *specValidator) walk(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind == yaml.DocumentNode {
+		for _, n := range node.Content {
+			v.walk(n, t, path)
+		}
+		return
+	}
+	if node.Kind == yaml.AliasNode || node.Tag == "!!null" {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		if err := node.Decode(reflect.New(t).Interface()); err != nil {
+			v.at(node, "", errors.WithMessagef(ErrorBadValue, "%s: %v", path, err))
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := schemaFields(t)
+		v.walkMapping(node, path, func(key string) (reflect.Type, bool) {
+			ft, ok := fields[key]
+			return ft, ok
+		})
+	case reflect.Map:
+		v.walkMapping(node, path, func(string) (reflect.Type, bool) { return t.Elem(), true })
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.at(node, "", errors.WithMessagef(ErrorBadValue, "%s: should be a list", path))
+			return
+		}
+		for i, item := range node.Content {
+			v.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			v.at(node, "", errors.WithMessagef(ErrorBadValue, "%s: should be a %s", path, t.Kind()))
+			return
+		}
+		if err := node.Decode(reflect.New(t).Interface()); err != nil {
+			v.at(node, "", errors.WithMessagef(ErrorBadValue, "%s: %q should be %s", path, node.Value, t.Kind()))
+		}
+	}
+}
+
+// walkMapping checks keys of node with field, which returns the type of key, or false if it's unknown.
+func (v *specValidator) walkMapping(node *yaml.Node, path string, field func(key string) (reflect.Type, bool)) {
+	if node.Kind != yaml.MappingNode {
+		v.at(node, "", errors.WithMessagef(ErrorBadValue, "%s: should be a map", path))
+		return
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			continue
+		}
+		p := strings.TrimPrefix(path+"."+key.Value, ".")
+		if seen[key.Value] {
+			v.at(key, "", errors.WithMessagef(ErrorDuplicateKey, "%s", p))
+			continue
+		}
+		seen[key.Value] = true
+		if _, ok := v.keys[p]; !ok {
+			v.keys[p] = key
+		}
+
+		if strings.HasPrefix(key.Value, "x-") {
+			continue
+		}
+		t, ok := field(key.Value)
+		if !ok {
+			v.at(key, "", errors.WithMessagef(ErrorUnknownField, "%s", p))
+			continue
+		}
+		v.walk(value, t, p)
+	}
+}
+
+// schemaFields returns the types of fields of struct t by their yaml names,
+// fields of inline structs are included, unless t has the same names.
+func schemaFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.Contains(f.Tag.Get("yaml"), ",inline") {
+			for name, ft := range schemaFields(f.Type) {
+				fields[name] = ft
+			}
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("yaml")
+		if f.PkgPath != "" || tag == "-" || strings.Contains(tag, ",inline") {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func (v *specValidator) checkExecutor(p *Pistage, doc map[string]interface{}, executors []string) {
+	if len(executors) == 0 {
+		return
+	}
+	for _, executor := range executors {
+		if p.Executor == executor {
+			return
+		}
+	}
+	v.at(firstNode(doc["executor"]), "executor", errors.WithMessagef(ErrorUnknownExecutor, "executor: %q, available: %s", p.Executor, strings.Join(executors, ", ")))
+}
+
+// checkJobs checks dependencies and steps of jobs.
+func (v *specValidator) checkJobs(p *Pistage, doc map[string]interface{}) {
+	if err := p.Inputs.validate(); err != nil {
+		v.at(nil, "inputs", err)
+	}
+
+	jobs, _ := doc["jobs"].(map[string]interface{})
+	names := make([]string, 0, len(p.Jobs))
+	for name := range p.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tp := newTopo()
+	for _, name := range names {
+		job := p.Jobs[name]
+		raw, _ := jobs[name].(map[string]interface{})
+		path := "jobs." + name
+
+		dependencies, _ := raw["depends_on"].([]interface{})
+		for i, dependency := range job.DependsOn {
+			if _, ok := p.Jobs[dependency]; ok {
+				continue
+			}
+			var node *yaml.Node
+			if i < len(dependencies) {
+				node = firstNode(dependencies[i])
+			}
+			v.at(node, path, errors.WithMessagef(ErrorJobNotFound, "%s.depends_on: %s", path, dependency))
+		}
+		tp.addDependencies(name, job.DependsOn...)
+
+		if len(job.Steps) == 0 {
+			v.at(nil, path, errors.WithMessagef(ErrorJobHasNoSteps, "%s", path))
+		}
+		v.checkSteps(path+".steps", job.Steps, raw["steps"])
+		v.checkSteps(path+".rollback_steps", job.RollbackSteps, raw["rollback_steps"])
+	}
+
+	if _, err := tp.graph(); err != nil {
+		cyclic := make([]string, 0, len(tp.vertices))
+		for name := range tp.vertices {
+			if name != emptyVertexName {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sort.Strings(cyclic)
+		v.at(nil, "jobs."+cyclic[0], errors.WithMessagef(err, "jobs: %s", strings.Join(cyclic, ", ")))
+	}
+}
+
+// checkSteps checks steps at path, raw is the steps decoded, where nodes are.
+func (v *specValidator) checkSteps(path string, steps []*Step, raw interface{}) {
+	items, _ := raw.([]interface{})
+	seen := map[string]bool{}
+	for i, step := range steps {
+		var item map[string]interface{}
+		if i < len(items) {
+			item, _ = items[i].(map[string]interface{})
+		}
+		node := firstNode(item["name"])
+		if node == nil {
+			node = firstNode(item)
+		}
+		p := fmt.Sprintf("%s[%d]", path, i)
+
+		if step == nil {
+			v.at(node, path, errors.WithMessagef(ErrorStepHasNothingToRun, "%s", p))
+			continue
+		}
+		if step.Name != "" && seen[step.Name] {
+			v.at(node, path, errors.WithMessagef(ErrorDuplicateStepName, "%s: %s", p, step.Name))
+		}
+		seen[step.Name] = true
+
+		switch {
+		case len(step.Run) == 0 && step.Uses == "":
+			v.at(node, path, errors.WithMessagef(ErrorStepHasNothingToRun, "%s", p))
+		case len(step.Run) != 0 && step.Uses != "":
+			v.at(firstNode(item["uses"]), path, errors.WithMessagef(ErrorRunWithUses, "%s", p))
+		}
+	}
+}
+
+// checkTemplates checks the templates of env and jobs, they must be parsed by pongo2 if they're rendered as commands
+// and arguments, and vars referenced must be declared in inputs.
+func (v *specValidator) checkTemplates(p *Pistage, doc map[string]interface{}) {
+	check := func(node *yaml.Node, path string, render bool) {
+		if !strings.Contains(node.Value, "{{") && !strings.Contains(node.Value, "{%") {
+			return
+		}
+		for _, name := range variable.VarNames(node.Value) {
+			if _, ok := p.Inputs[name]; !ok {
+				v.at(node, path, errors.WithMessagef(ErrorUnknownInput, "%s: vars.%s", path, name))
+			}
+		}
+		if !render {
+			return
+		}
+		if _, err := pongo2.FromString(variable.ReplaceVariables(node.Value)); err != nil {
+			v.at(node, path, errors.WithMessagef(ErrorBadTemplate, "%s: %v", path, err))
+		}
+	}
+
+	var walk func(value interface{}, path string, render bool)
+	walk = func(value interface{}, path string, render bool) {
+		switch val := value.(type) {
+		case *yaml.Node:
+			if val.Tag == "!!str" {
+				check(val, path, render)
+			}
+		case []interface{}:
+			for i, item := range val {
+				walk(item, fmt.Sprintf("%s[%d]", path, i), render)
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(val))
+			for k := range val {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				// commands and arguments of steps are rendered by pongo2.
+				rendered := render || strings.Contains(path, "steps[") && (k == "run" || k == "on_error" || k == "with")
+				walk(val[k], path+"."+k, rendered)
+			}
+		}
+	}
+	walk(doc["env"], "env", false)
+	walk(doc["jobs"], "jobs", false)
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSpec(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	loader := mapIncludeLoader{
+		"local:base.yml": `
+jobs:
+  .base:
+    imgae: alpine
+`,
+	}
+	errs := ValidateSpec(ctx, []byte(`
+include: [base.yml]
+executor: dokcer
+inputs:
+  version:
+    required: true
+jobs:
+  build:
+    extends: .base
+    timeout: soon
+    depends_on: [test, lint]
+    steps:
+      - name: build
+        run: ["make {{ vars.version }}"]
+      - name: build
+        uses: ./steps/push
+        run: ["push"]
+      - name: render
+        with:
+          tag: "{{ vars.tag"
+  test:
+    steps: []
+  test:
+    env:
+      A: "{{ vars.unknown }}"
+`), loader, []string{"docker", "shell"})
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal([]string{
+		`3:11: executor: "dokcer", available: docker, shell: Unknown executor`,
+		`10:14: jobs.build.timeout: "soon" should be int: Bad value`,
+		`11:24: jobs.build.depends_on: lint: Job not found`,
+		`15:15: jobs.build.steps[1]: build: Duplicate step name`,
+		`16:15: jobs.build.steps[1]: Can't specify both run and uses`,
+		`18:15: jobs.build.steps[2]: Step has nothing to run`,
+		`20:16: jobs.build.steps[2].with.tag: [Error (where: parser) in <string> | Line 1 Col 9 near 'tag'] '}}' expected: Bad template`,
+		`21:3: jobs.test: Job has no steps`,
+		`23:3: jobs.test: Duplicate key`,
+		`25:10: jobs.test.env.A: vars.unknown: Unknown input`,
+		`local:base.yml:4:5: jobs..base.imgae: Unknown field`,
+	}, messages)
+	assert.ErrorIs(errs, ErrorUnknownField)
+
+	errs = ValidateSpec(ctx, []byte("jobs:\n  a:\n    extends: .missing\n"), nil, nil)
+	assert.Len(errs, 1)
+	assert.Equal("3:14: job: a -> .missing: Extended job not found", errs[0].Error())
+
+	errs = ValidateSpec(ctx, []byte("jobs:\n  a:\n    steps: [~]\n"), nil, nil)
+	assert.Len(errs, 1)
+	assert.Equal("3:5: jobs.a.steps[0]: Step has nothing to run", errs[0].Error())
+
+	errs = ValidateSpec(ctx, []byte("jobs: [a"), nil, nil)
+	assert.Len(errs, 1)
+	assert.Equal(1, errs[0].Line)
+
+	errs = ValidateSpec(ctx, []byte(`
+executor: shell
+x-steps: &steps
+  - name: test
+    run: ["make test"]
+jobs:
+  a:
+    steps: *steps
+  b:
+    depends_on: [a]
+    steps: *steps
+`), nil, []string{"shell"})
+	assert.Empty(errs)
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/helpers/variable"
+)
+
+// ErrorUnknownInput is returned when a value is given for an input the workflow doesn't declare.
+var ErrorUnknownInput = errors.New("Unknown input")
+
+// ResolveVars resolves the values of inputs of p from vars, see Inputs.Resolve,
+// values for inputs not declared are rejected, since they're mostly typos.
+func (p *Pistage) ResolveVars(vars map[string]string) (map[string]string, error) {
+	var errs InputErrors
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := p.Inputs[name]; !ok {
+			errs = append(errs, errors.WithMessagef(ErrorUnknownInput, "input: %s", name))
+		}
+	}
+
+	resolved, err := p.Inputs.Resolve(vars)
+	if err != nil {
+		errs = append(errs, err.(InputErrors)...)
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return resolved, nil
+}
+
+// WithVars returns a copy of p with Vars resolved.
+// Vars are passed when rendering commands and arguments, for {{ vars.NAME }} and expressions like {{ vars.NAME | upper }},
+// values are never parsed as templates, so they can't reference secrets or break the rendering.
+func (p *Pistage) WithVars() (*Pistage, error) {
+	vars, err := p.ResolveVars(p.Vars)
+	if err != nil {
+		return nil, err
+	}
+	copied := *p
+	copied.Vars = vars
+	return &copied, nil
+}
+
+// RenderVars returns a copy of p with {{ vars.NAME }} in images and environments replaced with the values of Vars,
+// they're never rendered by pongo2. Secrets must be resolved before, otherwise values of vars
+// like {{ secrets.NAME }} would be resolved as well.
+func (p *Pistage) RenderVars() *Pistage {
+	return p.MapValues(func(s string) string { return variable.RenderVars(s, p.Vars) })
+}
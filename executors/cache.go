@@ -0,0 +1,165 @@
+package executors
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/helpers/command"
+	"github.com/projecteru2/pistage/store"
+)
+
+// ErrorCacheArchiveNotFound is returned when the tarball of a cache is missing in the workload.
+var ErrorCacheArchiveNotFound = errors.New("Cache archive not found")
+
+// cacheArchiveName returns the name of the tarball for the index-th cache,
+// the tarball is put in the working dir temporarily.
+func cacheArchiveName(index int) string {
+	return fmt.Sprintf("__pistage_cache_%d.tgz", index)
+}
+
+// CacheTransport is how JobCaches reaches the workload of a job,
+// names of files are relative to the working dir.
+type CacheTransport struct {
+	// Run executes cmd within the working dir, writes the output to output.
+	Run func(ctx context.Context, cmd string, output io.Writer) error
+	// Upload writes content to the file name.
+	Upload func(ctx context.Context, name string, content io.Reader) error
+	// Download writes the content of the file name to w.
+	Download func(ctx context.Context, name string, w io.Writer) error
+}
+
+// JobCaches restores the caches of a job before it's executed,
+// and saves the missed ones after. Tarballs are streamed between
+// store and the workload, they are never held in memory as a whole.
+// The zero value is ready to use.
+type JobCaches struct {
+	keys []string
+	hits []bool
+}
+
+// hashFiles calculates the digest of files within the working dir.
+func hashFiles(ctx context.Context, t *CacheTransport) func(...string) (string, error) {
+	return func(patterns ...string) (string, error) {
+		output := &bytes.Buffer{}
+		if err := t.Run(ctx, command.HashFilesCommand(patterns), output); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(output.String()), nil
+	}
+}
+
+// Restore renders the keys of all caches of job and restores them,
+// env is the environment keys are rendered with, progress is written to output.
+// A missing cache is not an error, it will be saved by Save.
+func (c *JobCaches) Restore(ctx context.Context, job *common.Job, env map[string]string, s store.Store, output io.Writer, t *CacheTransport) error {
+	c.keys = make([]string, len(job.Cache))
+	c.hits = make([]bool, len(job.Cache))
+
+	for index, cache := range job.Cache {
+		key, err := command.RenderCacheKey(cache.Key, env, hashFiles(ctx, t))
+		if err != nil {
+			return err
+		}
+		c.keys[index] = key
+
+		var restoreKeys []string
+		for _, restoreKey := range cache.RestoreKeys {
+			k, err := command.RenderCacheKey(restoreKey, env, hashFiles(ctx, t))
+			if err != nil {
+				return err
+			}
+			restoreKeys = append(restoreKeys, k)
+		}
+
+		matched, err := c.restoreOne(ctx, index, key, restoreKeys, s, t)
+		if errors.Is(err, store.ErrorCacheNotFound) {
+			fmt.Fprintf(output, "Cache not found for key: %s\n", key)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		c.hits[index] = matched == key
+		fmt.Fprintf(output, "Cache restored from key: %s\n", matched)
+	}
+	return nil
+}
+
+func (c *JobCaches) restoreOne(ctx context.Context, index int, key string, restoreKeys []string, s store.Store, t *CacheTransport) (string, error) {
+	matched, content, err := s.RestoreCache(ctx, key, restoreKeys)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	archive := cacheArchiveName(index)
+	if err := t.Upload(ctx, archive, content); err != nil {
+		return "", err
+	}
+	return matched, t.Run(ctx, command.CacheExtractCommand(archive), io.Discard)
+}
+
+// Save archives the paths of missed caches of job and saves them.
+// Failure of saving cache won't fail the job, errors are just logged.
+func (c *JobCaches) Save(ctx context.Context, job *common.Job, s store.Store, output io.Writer, t *CacheTransport) {
+	for index, cache := range job.Cache {
+		if index >= len(c.hits) || c.hits[index] {
+			continue
+		}
+		if err := c.saveOne(ctx, index, c.keys[index], cache.Paths, s, t); err != nil {
+			logrus.WithField("key", c.keys[index]).WithError(err).Errorf("[JobCaches] error when saving cache")
+			continue
+		}
+		fmt.Fprintf(output, "Cache saved with key: %s\n", c.keys[index])
+	}
+}
+
+func (c *JobCaches) saveOne(ctx context.Context, index int, key string, paths []string, s store.Store, t *CacheTransport) error {
+	archive := cacheArchiveName(index)
+	if err := t.Run(ctx, command.CacheArchiveCommand(archive, paths), io.Discard); err != nil {
+		return err
+	}
+	defer func() {
+		if err := t.Run(ctx, command.CacheRemoveCommand(archive), io.Discard); err != nil {
+			logrus.WithField("archive", archive).WithError(err).Warn("[JobCaches] fail to remove archive")
+		}
+	}()
+
+	// a failed download fails the pipe, so the partial tarball is never saved.
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(t.Download(ctx, archive, w))
+	}()
+	err := s.SaveCache(ctx, key, r)
+	r.CloseWithError(err)
+	return err
+}
+
+// CopyTarFile copies the content of the first regular file in the tar stream r to w,
+// it's for workloads sending files as tarballs.
+func CopyTarFile(r io.Reader, w io.Writer) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return ErrorCacheArchiveNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		_, err = io.Copy(w, tr)
+		return err
+	}
+}
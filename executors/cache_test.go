@@ -0,0 +1,113 @@
+package executors
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/store"
+)
+
+// cacheStore keeps caches in a CacheManager, other methods of store.Store are never called.
+type cacheStore struct {
+	store.Store
+	manager *store.CacheManager
+}
+
+func (s *cacheStore) RestoreCache(ctx context.Context, key string, restoreKeys []string) (string, io.ReadCloser, error) {
+	return s.manager.Restore(ctx, key, restoreKeys)
+}
+
+func (s *cacheStore) SaveCache(ctx context.Context, key string, content io.Reader) error {
+	return s.manager.Save(ctx, key, content)
+}
+
+func localCacheTransport(dir string) *CacheTransport {
+	return &CacheTransport{
+		Run: func(ctx context.Context, cmd string, output io.Writer) error {
+			c := exec.CommandContext(ctx, "/bin/sh", "-c", cmd)
+			c.Dir = dir
+			c.Stdout = output
+			c.Stderr = output
+			return c.Run()
+		},
+		Upload: func(ctx context.Context, name string, content io.Reader) error {
+			b, err := ioutil.ReadAll(content)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(filepath.Join(dir, name), b, 0600)
+		},
+		Download: func(ctx context.Context, name string, w io.Writer) error {
+			f, err := os.Open(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		},
+	}
+}
+
+func TestJobCaches(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	s := &cacheStore{manager: store.NewCacheManager(common.CacheConfig{Dir: t.TempDir()})}
+	dir := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(dir, "deps"), 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "deps", "a.txt"), []byte("a"), 0644))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("sum"), 0644))
+
+	job := &common.Job{Cache: []*common.Cache{{
+		Key:         "go-{{ hashFiles('go.sum') }}",
+		RestoreKeys: []string{"go-"},
+		Paths:       []string{"deps"},
+	}}}
+	transport := localCacheTransport(dir)
+
+	// missed, saved after execution, the tarball is removed.
+	output := &bytes.Buffer{}
+	caches := &JobCaches{}
+	assert.NoError(caches.Restore(ctx, job, nil, s, output, transport))
+	assert.Contains(output.String(), "Cache not found for key: go-")
+	caches.Save(ctx, job, s, output, transport)
+	assert.Contains(output.String(), "Cache saved with key: go-")
+	assert.NoFileExists(filepath.Join(dir, cacheArchiveName(0)))
+
+	// hit, restored and never saved again.
+	assert.NoError(os.RemoveAll(filepath.Join(dir, "deps")))
+	output.Reset()
+	caches = &JobCaches{}
+	assert.NoError(caches.Restore(ctx, job, nil, s, output, transport))
+	assert.Contains(output.String(), "Cache restored from key: go-")
+	content, err := ioutil.ReadFile(filepath.Join(dir, "deps", "a.txt"))
+	assert.NoError(err)
+	assert.Equal("a", string(content))
+	caches.Save(ctx, job, s, output, transport)
+	assert.NotContains(output.String(), "Cache saved")
+
+	// failed to archive, nothing is saved.
+	job.Cache[0].Key = "npm-{{ hashFiles('go.sum') }}"
+	failing := localCacheTransport(dir)
+	failing.Download = func(ctx context.Context, name string, w io.Writer) error {
+		return os.ErrNotExist
+	}
+	output.Reset()
+	caches = &JobCaches{}
+	assert.NoError(caches.Restore(ctx, job, nil, s, output, failing))
+	assert.Contains(output.String(), "Cache restored from key: go-")
+	caches.Save(ctx, job, s, output, failing)
+	assert.NotContains(output.String(), "Cache saved")
+	_, _, err = s.RestoreCache(ctx, "npm-", []string{"npm-"})
+	assert.ErrorIs(err, store.ErrorCacheNotFound)
+}
@@ -0,0 +1,125 @@
+package executors
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/helpers/command"
+	"github.com/projecteru2/pistage/helpers/variable"
+	"github.com/projecteru2/pistage/store"
+)
+
+var (
+	// ErrorKhoriumStepCycle is returned when a composite KhoriumStep uses itself, directly or not.
+	ErrorKhoriumStepCycle = errors.New("KhoriumStep uses itself")
+
+	// ErrorKhoriumStepTooDeep is returned when composite KhoriumSteps are nested deeper than MaxCompositeDepth.
+	ErrorKhoriumStepTooDeep = errors.New("KhoriumStep nested too deep")
+)
+
+// MaxCompositeDepth is the max depth of composite KhoriumSteps nested in each other.
+var MaxCompositeDepth = 8
+
+// ExpandSteps replaces composite KhoriumSteps in steps with the steps they're made of, recursively,
+// other steps are kept as is, so executors can execute the result step by step.
+// Steps expanded are named as composite/step, {{ inputs.name }} in their run, on_error, uses, with and env
+// are replaced with the inputs of composite, inputs are also given as KHORIUMSTEP_INPUT_* environment variables.
+// vars are the values of workflow inputs, used when rendering the inputs of composites.
+func ExpandSteps(ctx context.Context, store store.Store, steps []*common.Step, vars map[string]string) ([]*common.Step, error) {
+	return expandSteps(ctx, store, steps, vars, nil)
+}
+
+// expandSteps expands steps used by composites in stack.
+func expandSteps(ctx context.Context, store store.Store, steps []*common.Step, vars map[string]string, stack []string) ([]*common.Step, error) {
+	var expanded []*common.Step
+	for _, step := range steps {
+		if step.Uses == "" {
+			expanded = append(expanded, step)
+			continue
+		}
+
+		ks, err := store.GetRegisteredKhoriumStep(ctx, step.Uses)
+		if err != nil {
+			return nil, err
+		}
+		if !ks.IsComposite() {
+			expanded = append(expanded, step)
+			continue
+		}
+
+		path := append(stack[:len(stack):len(stack)], step.Uses)
+		for _, name := range stack {
+			if name == step.Uses {
+				return nil, errors.WithMessagef(ErrorKhoriumStepCycle, "path: %s", strings.Join(path, " -> "))
+			}
+		}
+		if len(path) > MaxCompositeDepth {
+			return nil, errors.WithMessagef(ErrorKhoriumStepTooDeep, "path: %s", strings.Join(path, " -> "))
+		}
+
+		inner, err := compositeSteps(step, ks, vars)
+		if err != nil {
+			return nil, err
+		}
+		inner, err = expandSteps(ctx, store, inner, vars, path)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, inner...)
+	}
+	return expanded, nil
+}
+
+// compositeSteps renders the steps of composite KhoriumStep ks used by step.
+func compositeSteps(step *common.Step, ks *common.KhoriumStep, vars map[string]string) ([]*common.Step, error) {
+	arguments, err := variable.RenderArguments(step.With, step.Environment, vars)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := ks.ResolveInputs(arguments)
+	if err != nil {
+		return nil, err
+	}
+	ksEnv, err := ks.BuildEnvironmentVariables(inputs)
+	if err != nil {
+		return nil, err
+	}
+	environment := command.MergeVariables(step.Environment, ksEnv)
+
+	var steps []*common.Step
+	for i, s := range ks.Run.Steps {
+		name := s.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		steps = append(steps, &common.Step{
+			Name:        step.Name + "/" + name,
+			Uses:        variable.RenderInputs(s.Uses, inputs),
+			With:        renderInputsMap(s.With, inputs),
+			Run:         renderInputsList(s.Run, inputs),
+			OnError:     renderInputsList(s.OnError, inputs),
+			Environment: command.MergeVariables(environment, renderInputsMap(s.Environment, inputs)),
+		})
+	}
+	return steps, nil
+}
+
+func renderInputsList(ts []string, inputs map[string]string) []string {
+	var r []string
+	for _, t := range ts {
+		r = append(r, variable.RenderInputs(t, inputs))
+	}
+	return r
+}
+
+func renderInputsMap(ts map[string]string, inputs map[string]string) map[string]string {
+	r := map[string]string{}
+	for k, t := range ts {
+		r[k] = variable.RenderInputs(t, inputs)
+	}
+	return r
+}
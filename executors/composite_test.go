@@ -0,0 +1,122 @@
+package executors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/store"
+)
+
+// mapStore provides KhoriumSteps by name.
+type mapStore struct {
+	store.Store
+	steps map[string]*common.KhoriumStep
+}
+
+func (s *mapStore) GetRegisteredKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	ks, ok := s.steps[name]
+	if !ok {
+		return nil, store.ErrorKhoriumRefNotFound
+	}
+	return ks, nil
+}
+
+func composite(name string, steps ...*common.Step) *common.KhoriumStep {
+	return &common.KhoriumStep{Name: name, Run: &common.KhoriumStepRun{Steps: steps}}
+}
+
+func TestExpandSteps(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	build := composite("build",
+		&common.Step{Name: "checkout", Uses: "checkout", With: map[string]string{"ref": "{{ inputs.ref }}"}},
+		&common.Step{Run: []string{"go build {{ inputs.pkg }} -o {{ out }}"}, With: map[string]string{"out": "bin"}},
+	)
+	build.Inputs = map[string]*common.KhoriumStepInput{
+		"ref": {Required: true},
+		"pkg": {Default: "./..."},
+	}
+	s := &mapStore{steps: map[string]*common.KhoriumStep{
+		"checkout": {Name: "checkout", Run: &common.KhoriumStepRun{Main: "git checkout"}},
+		"build":    build,
+		"ci":       composite("ci", &common.Step{Name: "build", Uses: "build", With: map[string]string{"ref": "{{ inputs.ref }}"}}),
+	}}
+	s.steps["ci"].Inputs = map[string]*common.KhoriumStepInput{"ref": {Default: "master"}}
+
+	plain := &common.Step{Name: "test", Run: []string{"go test"}}
+	steps, err := ExpandSteps(ctx, s, []*common.Step{
+		{Name: "ci", Uses: "ci", With: map[string]string{"ref": "{{ env.REF }}"}, Environment: map[string]string{"REF": "v1"}},
+		plain,
+	}, nil)
+	assert.NoError(err)
+	assert.Len(steps, 3)
+
+	assert.Equal("ci/build/checkout", steps[0].Name)
+	assert.Equal("checkout", steps[0].Uses)
+	assert.Equal(map[string]string{"ref": "v1"}, steps[0].With)
+
+	assert.Equal("ci/build/1", steps[1].Name)
+	assert.Equal([]string{"go build ./... -o {{ out }}"}, steps[1].Run)
+	assert.Equal("v1", steps[1].Environment["KHORIUMSTEP_INPUT_REF"])
+	assert.Equal("./...", steps[1].Environment["KHORIUMSTEP_INPUT_PKG"])
+
+	assert.Same(plain, steps[2])
+
+	// vars are given to inputs of composite.
+	steps, err = ExpandSteps(ctx, s, []*common.Step{
+		{Name: "ci", Uses: "ci", With: map[string]string{"ref": "{{ vars.ref }}-{{ vars.ref | upper }}"}},
+	}, map[string]string{"ref": "rc"})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"ref": "rc-RC"}, steps[0].With)
+	assert.Equal("rc-RC", steps[1].Environment["KHORIUMSTEP_INPUT_REF"])
+
+	// required input of inner composite is missing.
+	_, err = ExpandSteps(ctx, s, []*common.Step{{Name: "build", Uses: "build"}}, nil)
+	assert.ErrorIs(err, common.ErrorInputIsRequired)
+}
+
+func TestExpandStepsCycle(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	s := &mapStore{steps: map[string]*common.KhoriumStep{
+		"a": composite("a", &common.Step{Uses: "b"}),
+		"b": composite("b", &common.Step{Uses: "a"}),
+	}}
+	_, err := ExpandSteps(ctx, s, []*common.Step{{Name: "a", Uses: "a"}}, nil)
+	assert.ErrorIs(err, ErrorKhoriumStepCycle)
+	assert.Contains(err.Error(), "a -> b -> a")
+
+	// the same step used twice isn't a cycle.
+	s.steps["b"] = composite("b", &common.Step{Run: []string{"true"}})
+	s.steps["a"] = composite("a", &common.Step{Uses: "b"}, &common.Step{Uses: "b"})
+	steps, err := ExpandSteps(ctx, s, []*common.Step{{Name: "a", Uses: "a"}}, nil)
+	assert.NoError(err)
+	assert.Len(steps, 2)
+}
+
+func TestExpandStepsTooDeep(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	s := &mapStore{steps: map[string]*common.KhoriumStep{}}
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}
+	for i, name := range names {
+		if i == len(names)-1 {
+			s.steps[name] = composite(name, &common.Step{Run: []string{"true"}})
+			break
+		}
+		s.steps[name] = composite(name, &common.Step{Uses: names[i+1]})
+	}
+
+	_, err := ExpandSteps(ctx, s, []*common.Step{{Name: "a", Uses: "a"}}, nil)
+	assert.ErrorIs(err, ErrorKhoriumStepTooDeep)
+
+	steps, err := ExpandSteps(ctx, s, []*common.Step{{Name: "b", Uses: "b"}}, nil)
+	assert.NoError(err)
+	assert.Len(steps, 1)
+}
@@ -0,0 +1,48 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/projecteru2/pistage/executors"
+)
+
+// restoreCache restores the caches of job into the container.
+func (d *DockerJobExecutor) restoreCache(ctx context.Context) error {
+	return d.caches.Restore(ctx, d.job, d.jobEnvironment, d.store, d.output, d.cacheTransport())
+}
+
+// saveCache saves the missed caches of job.
+func (d *DockerJobExecutor) saveCache(ctx context.Context) {
+	d.caches.Save(ctx, d.job, d.store, d.output, d.cacheTransport())
+}
+
+// cacheTransport runs commands in the working dir of the container,
+// tarballs are transferred through the archive API.
+func (d *DockerJobExecutor) cacheTransport() *executors.CacheTransport {
+	return &executors.CacheTransport{
+		Run: func(ctx context.Context, cmd string, output io.Writer) error {
+			return d.executeContainer(ctx, []string{"/bin/sh", "-c", cmd}, nil, "", output)
+		},
+		Upload: func(ctx context.Context, name string, content io.Reader) error {
+			// the size must be known to put a file into the tar stream.
+			buffer, err := ioutil.ReadAll(content)
+			if err != nil {
+				return err
+			}
+			fc := NewDockerFileCollector(d.client, d.workingDir)
+			fc.SetFiles(map[string][]byte{name: buffer})
+			return fc.CopyTo(ctx, d.containerID, nil)
+		},
+		Download: func(ctx context.Context, name string, w io.Writer) error {
+			archive, err := d.client.openArchive(ctx, d.containerID, filepath.Join(d.workingDir, name))
+			if err != nil {
+				return err
+			}
+			defer archive.Close()
+			return executors.CopyTarFile(archive, w)
+		},
+	}
+}
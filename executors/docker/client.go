@@ -0,0 +1,272 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrorDockerAPI is returned when Docker Engine API responds with an error status.
+	ErrorDockerAPI = errors.New("Docker API error")
+
+	// ErrorDockerNotFound is returned when Docker Engine API responds with 404.
+	ErrorDockerNotFound = errors.New("Docker object not found")
+)
+
+// client is a minimal Docker Engine API client,
+// only APIs used by DockerJobExecutor are implemented.
+// Podman exposes a compatible API, so it works with Podman as well.
+type client struct {
+	http    *http.Client
+	baseURL string
+}
+
+// newClient creates a client with host, host can be like:
+//   - unix:///var/run/docker.sock
+//   - tcp://127.0.0.1:2375
+//   - http://127.0.0.1:2375
+func newClient(host, version string) (*client, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{}
+	baseURL := ""
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		}
+		baseURL = "http://docker"
+	case "tcp":
+		baseURL = "http://" + u.Host
+	case "http", "https":
+		baseURL = strings.TrimSuffix(host, "/")
+	default:
+		return nil, errors.WithMessagef(ErrorDockerAPI, "unsupported host: %s", host)
+	}
+	if version != "" {
+		baseURL = fmt.Sprintf("%s/%s", baseURL, version)
+	}
+
+	return &client{
+		http:    &http.Client{Transport: transport},
+		baseURL: baseURL,
+	}, nil
+}
+
+type containerConfig struct {
+	Image      string            `json:"Image"`
+	Cmd        []string          `json:"Cmd"`
+	Env        []string          `json:"Env"`
+	WorkingDir string            `json:"WorkingDir"`
+	User       string            `json:"User,omitempty"`
+	Labels     map[string]string `json:"Labels,omitempty"`
+	HostConfig *hostConfig       `json:"HostConfig"`
+}
+
+type hostConfig struct {
+	NetworkMode string `json:"NetworkMode,omitempty"`
+	Privileged  bool   `json:"Privileged"`
+}
+
+type execConfig struct {
+	Cmd          []string `json:"Cmd"`
+	Env          []string `json:"Env,omitempty"`
+	WorkingDir   string   `json:"WorkingDir,omitempty"`
+	AttachStdout bool     `json:"AttachStdout"`
+	AttachStderr bool     `json:"AttachStderr"`
+}
+
+type idResponse struct {
+	ID string `json:"Id"`
+}
+
+type execInspectResponse struct {
+	Running  bool `json:"Running"`
+	ExitCode int  `json:"ExitCode"`
+}
+
+// do sends the request, body is encoded as json unless it's an io.Reader.
+// Response with status code 404 is returned as ErrorDockerNotFound,
+// other status codes >= 400 are returned as ErrorDockerAPI.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	var (
+		reader      io.Reader
+		contentType string
+	)
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+		contentType = "application/x-tar"
+	default:
+		content, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(content)
+		contentType = "application/json"
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		message, _ := ioutil.ReadAll(resp.Body)
+		e := ErrorDockerAPI
+		if resp.StatusCode == http.StatusNotFound {
+			e = ErrorDockerNotFound
+		}
+		return nil, errors.WithMessagef(e, "%s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// doJSON sends the request and decodes the response into result if result is not nil.
+func (c *client) doJSON(ctx context.Context, method, path string, query url.Values, body, result interface{}) error {
+	resp, err := c.do(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if result == nil {
+		_, err := io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// imageExists checks if image exists locally.
+func (c *client) imageExists(ctx context.Context, image string) (bool, error) {
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/images/%s/json", image), nil, nil, nil)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, ErrorDockerNotFound) {
+		return false, nil
+	}
+	return false, err
+}
+
+// pullImage pulls image, the progress messages are discarded.
+func (c *client) pullImage(ctx context.Context, image string) error {
+	return c.doJSON(ctx, http.MethodPost, "/images/create", url.Values{"fromImage": {image}}, nil, nil)
+}
+
+// createContainer creates a container, the name is generated by Docker.
+func (c *client) createContainer(ctx context.Context, config *containerConfig) (string, error) {
+	resp := &idResponse{}
+	if err := c.doJSON(ctx, http.MethodPost, "/containers/create", nil, config, resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (c *client) startContainer(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/containers/%s/start", id), nil, nil, nil)
+}
+
+func (c *client) removeContainer(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/containers/%s", id), url.Values{"force": {"true"}, "v": {"true"}}, nil, nil)
+}
+
+// exec executes cmd in container, writes both stdout and stderr to output,
+// returns the exit code of cmd.
+func (c *client) exec(ctx context.Context, id string, config *execConfig, output io.Writer) (int, error) {
+	config.AttachStdout = true
+	config.AttachStderr = true
+
+	created := &idResponse{}
+	if err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/containers/%s/exec", id), nil, config, created); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/exec/%s/start", created.ID), nil, map[string]bool{"Detach": false, "Tty": false})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := demultiplex(resp.Body, output); err != nil {
+		return 0, err
+	}
+
+	inspect := &execInspectResponse{}
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/exec/%s/json", created.ID), nil, nil, inspect); err != nil {
+		return 0, err
+	}
+	return inspect.ExitCode, nil
+}
+
+// putArchive extracts the tarball content into path of the container.
+func (c *client) putArchive(ctx context.Context, id, path string, content io.Reader) error {
+	return c.doJSON(ctx, http.MethodPut, fmt.Sprintf("/containers/%s/archive", id), url.Values{"path": {path}}, content, nil)
+}
+
+// getArchive returns path of the container as a tarball.
+func (c *client) getArchive(ctx context.Context, id, path string) ([]byte, error) {
+	archive, err := c.openArchive(ctx, id, path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	return ioutil.ReadAll(archive)
+}
+
+// openArchive streams path of the container as a tarball, it must be closed by the caller.
+func (c *client) openArchive(ctx context.Context, id, path string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/containers/%s/archive", id), url.Values{"path": {path}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// demultiplex reads the multiplexed stream of a non-tty exec,
+// each frame has an 8 bytes header, the first byte is the stream type,
+// the last 4 bytes are the big endian frame size.
+// Frames from both stdout and stderr are written to output.
+func demultiplex(r io.Reader, output io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(output, r, size); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/helpers/command"
+	"github.com/projecteru2/pistage/helpers/variable"
+	"github.com/projecteru2/pistage/store"
+)
+
+const (
+	// working dir for KhoriumStep.
+	khoriumStepWorkingDir = "/_khoriumstep/"
+
+	labelJobName            = "pistage.job"
+	labelWorkflowIdentifier = "pistage.workflow_identifier"
+)
+
+// DockerJobExecutor executes the job in a local container,
+// it works just like EruJobExecutor, but talks to Docker Engine API directly.
+type DockerJobExecutor struct {
+	client *client
+	store  store.Store
+	config *common.Config
+
+	job     *common.Job
+	pistage *common.Pistage
+
+	output         io.Writer
+	containerID    string
+	jobEnvironment map[string]string
+	workingDir     string
+
+	// caches are the caches of job, restored in Prepare.
+	caches executors.JobCaches
+
+	// posts are run in Cleanup, see executors.PostHooks.
+	posts executors.PostHooks
+
+	// outputs holds the outputs of KhoriumSteps executed.
+	outputs executors.StepOutputs
+}
+
+// NewDockerJobExecutor creates a Docker executor for this job.
+// Since job needs to know its context, pistage is assigned too.
+func NewDockerJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, client *client, store store.Store, config *common.Config) (*DockerJobExecutor, error) {
+	return &DockerJobExecutor{
+		client:         client,
+		store:          store,
+		config:         config,
+		job:            job,
+		pistage:        pistage,
+		output:         output,
+		jobEnvironment: pistage.Environment,
+		workingDir:     config.Docker.DefaultWorkingDir,
+	}, nil
+}
+
+// Prepare does all the preparations before actually running a job
+func (d *DockerJobExecutor) Prepare(ctx context.Context) error {
+	preparations := []func(context.Context) error{
+		d.prepareJobRuntime,
+		d.prepareFileContext,
+		d.restoreCache,
+	}
+	for _, f := range preparations {
+		if err := f(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareJobRuntime creates and starts an empty container.
+// The empty container is actually a sleep process which lasts timeout seconds.
+// Image is pulled if it doesn't exist locally.
+func (d *DockerJobExecutor) prepareJobRuntime(ctx context.Context) error {
+	jobImage := d.job.Image
+	if jobImage == "" {
+		jobImage = d.config.Docker.DefaultJobImage
+	}
+
+	exists, err := d.client.imageExists(ctx, jobImage)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := d.client.pullImage(ctx, jobImage); err != nil {
+			return err
+		}
+	}
+
+	id, err := d.client.createContainer(ctx, &containerConfig{
+		Image:      jobImage,
+		Cmd:        command.EmptyWorkloadCommand(d.job.Timeout),
+		Env:        command.ToEnvironmentList(command.MergeVariables(command.PreparePistageEnvs(d.jobEnvironment), d.defaultEnvironmentVariables())),
+		WorkingDir: d.workingDir,
+		User:       d.config.Docker.DefaultUser,
+		Labels: map[string]string{
+			labelJobName:            d.job.Name,
+			labelWorkflowIdentifier: d.pistage.WorkflowIdentifier,
+		},
+		HostConfig: &hostConfig{
+			NetworkMode: d.config.Docker.DefaultNetwork,
+			Privileged:  d.config.Docker.DefaultPrivileged,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	d.containerID = id
+
+	return d.client.startContainer(ctx, id)
+}
+
+// defaultEnvironmentVariables sets some useful information into environment variables.
+// This will be set to the whole running context within the container.
+func (d *DockerJobExecutor) defaultEnvironmentVariables() map[string]string {
+	return map[string]string{
+		"PISTAGE_WORKING_DIR":         d.workingDir,
+		"PISTAGE_JOB_NAME":            d.job.Name,
+		"PISTAGE_DEPENDS_ON":          strings.Join(d.job.DependsOn, ","),
+		"PISTAGE_WORKFLOW_IDENTIFIER": d.pistage.WorkflowIdentifier,
+		"PISTAGE_WORKFLOW_TYPE":       d.pistage.WorkflowType,
+	}
+}
+
+func (d *DockerJobExecutor) prepareFileContext(ctx context.Context) error {
+	dependentJobs := d.pistage.GetJobs(d.job.DependsOn)
+	for _, job := range dependentJobs {
+		fc := job.GetFileCollector()
+		if fc == nil {
+			continue
+		}
+		if err := fc.CopyTo(ctx, d.containerID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute will execute all steps within this job one by one,
+// caches missed are saved after all steps succeeded.
+func (d *DockerJobExecutor) Execute(ctx context.Context) error {
+	if err := d.executeSteps(ctx, d.job.Steps); err != nil {
+		return err
+	}
+	d.saveCache(ctx)
+	return nil
+}
+
+func (d *DockerJobExecutor) executeSteps(ctx context.Context, steps []*common.Step) error {
+	// composite KhoriumSteps are expanded first,
+	// so only plain steps and plain KhoriumSteps are left.
+	steps, err := executors.ExpandSteps(ctx, d.store, steps, d.pistage.Vars)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		var err error
+		switch step.Uses {
+		case "":
+			err = d.executeStep(ctx, step)
+		default:
+			err = d.executeKhoriumStep(ctx, step)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeStep executes a step.
+// It prepares the arguments and environments to the command.
+// Then execute the command, retrieve the output, the execution will stop if any error occurs.
+// It then retries to execute the OnError commands, also with the arguments and environments.
+func (d *DockerJobExecutor) executeStep(ctx context.Context, step *common.Step) error {
+	var (
+		err  error
+		vars = d.pistage.Vars
+	)
+
+	environment := command.MergeVariables(command.MergeVariables(d.jobEnvironment, d.outputs.Environment()), step.Environment)
+
+	defer func() {
+		if !errors.Is(err, common.ErrExecutionError) {
+			return
+		}
+		if err := d.executeCommands(ctx, step.OnError, step.With, environment, vars); err != nil {
+			logrus.WithField("step", step.Name).WithError(err).Errorf("[DockerJobExecutor] error when executing on_error")
+		}
+	}()
+
+	err = d.executeCommands(ctx, step.Run, step.With, environment, vars)
+	return err
+}
+
+// executeKhoriumStep executes a KhoriumStep defined by step.Uses.
+func (d *DockerJobExecutor) executeKhoriumStep(ctx context.Context, step *common.Step) error {
+	ks, err := d.store.GetRegisteredKhoriumStep(ctx, step.Uses)
+	if err != nil {
+		return err
+	}
+
+	arguments, err := variable.RenderArguments(step.With, command.MergeVariables(d.outputs.Environment(), step.Environment), d.pistage.Vars)
+	if err != nil {
+		return err
+	}
+
+	ksEnv, err := ks.BuildEnvironmentVariables(arguments)
+	if err != nil {
+		return err
+	}
+	envs := command.MergeVariables(command.MergeVariables(d.outputs.Environment(), step.Environment), ksEnv)
+
+	if err := d.copyKhoriumStepFiles(ctx, khoriumStepWorkingDir, ks.Files); err != nil {
+		return err
+	}
+
+	outputFile, err := executors.OutputFile(khoriumStepWorkingDir, step)
+	if err != nil {
+		return err
+	}
+	if len(ks.Outputs) != 0 {
+		envs[executors.KhoriumStepOutputEnv] = outputFile
+	}
+
+	d.posts.Queue(step.Name, ks, envs, khoriumStepWorkingDir)
+
+	if err := d.executeContainer(ctx, []string{"/bin/sh", "-c", ks.Run.Main}, envs, khoriumStepWorkingDir, d.output); err != nil {
+		return err
+	}
+	return d.outputs.Collect(ks, step.Name, outputFile, func(cmd string, output io.Writer) error {
+		return d.executeContainer(ctx, []string{"/bin/sh", "-c", cmd}, envs, khoriumStepWorkingDir, output)
+	})
+}
+
+// executeCommands executes cmd with given arguments, environments and variables.
+// use args, envs, and reserved vars to build the cmd.
+func (d *DockerJobExecutor) executeCommands(ctx context.Context, cmds []string, args, env, vars map[string]string) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	var commands []string
+	for _, cmd := range cmds {
+		c, err := command.RenderCommand(cmd, args, env, vars)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, c)
+	}
+
+	shell, err := command.RenderShell(commands)
+	if err != nil {
+		return err
+	}
+
+	return d.executeContainer(ctx, []string{"/bin/sh", "-c", shell}, env, "", d.output)
+}
+
+// executeContainer executes cmds within the container, writes the output to output.
+// If workdir is empty, the working dir of the container is used.
+func (d *DockerJobExecutor) executeContainer(ctx context.Context, cmds []string, env map[string]string, workdir string, output io.Writer) error {
+	exitcode, err := d.client.exec(ctx, d.containerID, &execConfig{
+		Cmd:        cmds,
+		Env:        command.ToEnvironmentList(env),
+		WorkingDir: workdir,
+	}, output)
+	if err != nil {
+		return err
+	}
+	if exitcode != 0 {
+		return errors.WithMessagef(common.ErrExecutionError, "exitcode: %d", exitcode)
+	}
+	return nil
+}
+
+// copyKhoriumStepFiles copies files of a KhoriumStep into dir of the container.
+func (d *DockerJobExecutor) copyKhoriumStepFiles(ctx context.Context, dir string, files map[string][]byte) error {
+	fc := NewDockerFileCollector(d.client, dir)
+	fc.SetFiles(files)
+	return fc.CopyTo(ctx, d.containerID, nil)
+}
+
+// executePost executes the post command of a KhoriumStep.
+func (d *DockerJobExecutor) executePost(ctx context.Context, hook *executors.PostHook) error {
+	return d.executeContainer(ctx, []string{"/bin/sh", "-c", hook.Command}, hook.Environment, hook.WorkingDir, d.output)
+}
+
+// beforeCleanup collects files if any
+func (d *DockerJobExecutor) beforeCleanup(ctx context.Context) error {
+	if len(d.job.Files) == 0 || d.containerID == "" {
+		return nil
+	}
+
+	fc := NewDockerFileCollector(d.client, d.workingDir)
+	if err := fc.Collect(ctx, d.containerID, d.job.Files); err != nil {
+		return err
+	}
+
+	d.job.SetFileCollector(fc)
+	return nil
+}
+
+// cleanup removes the container forcibly.
+func (d *DockerJobExecutor) cleanup(ctx context.Context) error {
+	if d.containerID == "" {
+		return nil
+	}
+	return d.client.removeContainer(ctx, d.containerID)
+}
+
+// Cleanup does all the cleanup work
+func (d *DockerJobExecutor) Cleanup(ctx context.Context) error {
+	// post commands are executed even if the job fails,
+	// every step of cleanup is executed, the first error is returned.
+	err := d.posts.Run(ctx, d.copyKhoriumStepFiles, d.executePost)
+
+	cleanups := []func(context.Context) error{
+		d.beforeCleanup,
+		d.cleanup,
+	}
+	for _, f := range cleanups {
+		if cerr := f(ctx); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Rollback is a function can execute rollback_steps commands which are defined in yaml file
+func (d *DockerJobExecutor) Rollback(ctx context.Context) error {
+	return d.executeSteps(ctx, d.job.RollbackSteps)
+}
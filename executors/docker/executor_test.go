@@ -0,0 +1,244 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// fakeDocker is a fake Docker Engine API server,
+// commands executed are recorded, and their output are the commands themselves.
+type fakeDocker struct {
+	sync.Mutex
+	images     map[string]bool
+	pulled     []string
+	containers map[string]*containerConfig
+	started    map[string]bool
+	execs      map[string]*execConfig
+	commands   [][]string
+	files      map[string][]byte
+	exitCode   func(cmd []string) int
+}
+
+func newFakeDocker() *fakeDocker {
+	return &fakeDocker{
+		images:     map[string]bool{},
+		containers: map[string]*containerConfig{},
+		started:    map[string]bool{},
+		execs:      map[string]*execConfig{},
+		files:      map[string][]byte{},
+		exitCode:   func([]string) int { return 0 },
+	}
+}
+
+func (f *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1.41")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && parts[0] == "images":
+		image := strings.TrimSuffix(strings.TrimPrefix(path, "/images/"), "/json")
+		if !f.images[image] {
+			http.Error(w, "no such image", http.StatusNotFound)
+		}
+	case r.Method == http.MethodPost && path == "/images/create":
+		image := r.URL.Query().Get("fromImage")
+		f.pulled = append(f.pulled, image)
+		f.images[image] = true
+	case r.Method == http.MethodPost && path == "/containers/create":
+		config := &containerConfig{}
+		json.NewDecoder(r.Body).Decode(config)
+		id := fmt.Sprintf("container%d", len(f.containers))
+		f.containers[id] = config
+		json.NewEncoder(w).Encode(idResponse{ID: id})
+	case r.Method == http.MethodPost && parts[0] == "containers" && parts[2] == "start":
+		f.started[parts[1]] = true
+	case r.Method == http.MethodDelete && parts[0] == "containers":
+		delete(f.containers, parts[1])
+	case r.Method == http.MethodPost && parts[0] == "containers" && parts[2] == "exec":
+		config := &execConfig{}
+		json.NewDecoder(r.Body).Decode(config)
+		id := fmt.Sprintf("exec%d", len(f.execs))
+		f.execs[id] = config
+		json.NewEncoder(w).Encode(idResponse{ID: id})
+	case r.Method == http.MethodPost && parts[0] == "exec" && parts[2] == "start":
+		cmd := f.execs[parts[1]].Cmd
+		f.commands = append(f.commands, cmd)
+		output := []byte(strings.Join(cmd, " "))
+		header := make([]byte, 8)
+		header[0] = 1
+		binary.BigEndian.PutUint32(header[4:], uint32(len(output)))
+		w.Write(header)
+		w.Write(output)
+	case r.Method == http.MethodGet && parts[0] == "exec":
+		json.NewEncoder(w).Encode(execInspectResponse{ExitCode: f.exitCode(f.execs[parts[1]].Cmd)})
+	case r.Method == http.MethodPut && parts[0] == "containers" && parts[2] == "archive":
+		tr := tar.NewReader(r.Body)
+		for {
+			header, err := tr.Next()
+			if err != nil {
+				break
+			}
+			content, _ := ioutil.ReadAll(tr)
+			f.files["/"+header.Name] = content
+		}
+	case r.Method == http.MethodGet && parts[0] == "containers" && parts[2] == "archive":
+		name := r.URL.Query().Get("path")
+		content, ok := f.files[name]
+		if !ok {
+			http.Error(w, "no such file", http.StatusNotFound)
+			return
+		}
+		tw := tar.NewWriter(w)
+		tw.WriteHeader(&tar.Header{Name: name[strings.LastIndex(name, "/")+1:], Mode: 0644, Size: int64(len(content))})
+		tw.Write(content)
+		tw.Close()
+	default:
+		http.Error(w, "not implemented", http.StatusNotImplemented)
+	}
+}
+
+func newTestingExecutor(t *testing.T, job *common.Job, output io.Writer) (*DockerJobExecutor, *fakeDocker, func()) {
+	fake := newFakeDocker()
+	server := httptest.NewServer(fake)
+
+	config := &common.Config{
+		Docker: common.DockerConfig{
+			Host:              server.URL,
+			APIVersion:        "v1.41",
+			DefaultJobImage:   "alpine:latest",
+			DefaultWorkingDir: "/pistage",
+			DefaultNetwork:    "bridge",
+		},
+	}
+	provider, err := NewDockerJobExecutorProvider(config, nil)
+	assert.NoError(t, err)
+
+	pistage := &common.Pistage{
+		WorkflowIdentifier: "identifier",
+		Jobs:               map[string]*common.Job{job.Name: job},
+		Environment:        map[string]string{"GOOS": "linux"},
+	}
+	executor, err := provider.GetJobExecutor(job, pistage, output)
+	assert.NoError(t, err)
+	return executor.(*DockerJobExecutor), fake, server.Close
+}
+
+func TestDockerJobExecutor(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	job := &common.Job{
+		Name:    "job1",
+		Timeout: 120,
+		Steps: []*common.Step{
+			{Name: "build", Run: []string{"make {{ target }}"}, With: map[string]string{"target": "binary"}, OnError: []string{"echo failed"}},
+		},
+		Files: []string{"binary"},
+	}
+	output := &bytes.Buffer{}
+	executor, fake, stop := newTestingExecutor(t, job, output)
+	defer stop()
+
+	assert.NoError(executor.Prepare(ctx))
+	assert.Equal([]string{"alpine:latest"}, fake.pulled)
+	assert.True(fake.started[executor.containerID])
+
+	container := fake.containers[executor.containerID]
+	assert.Equal("alpine:latest", container.Image)
+	assert.Equal("/pistage", container.WorkingDir)
+	assert.Equal([]string{"/bin/sh", "-c", "sleep 120"}, container.Cmd)
+	assert.Equal("bridge", container.HostConfig.NetworkMode)
+	assert.Contains(container.Env, "PISTAGE_ENV_VAR_GOOS=linux")
+
+	assert.NoError(executor.Execute(ctx))
+	assert.Equal("/bin/sh -c make binary\n", output.String())
+
+	// a failed step triggers on_error.
+	fake.exitCode = func(cmd []string) int {
+		if strings.Contains(cmd[2], "make") {
+			return 2
+		}
+		return 0
+	}
+	assert.ErrorIs(executor.Execute(ctx), common.ErrExecutionError)
+	assert.Contains(fake.commands[len(fake.commands)-1][2], "echo failed")
+
+	fake.files["/pistage/binary"] = []byte("ELF")
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Empty(fake.containers)
+	assert.Equal([]string{"binary"}, job.GetFileCollector().Files())
+}
+
+func TestDockerJobExecutorCleanup(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	job := &common.Job{Name: "job1", Timeout: 120, Files: []string{"missing"}}
+	executor, fake, stop := newTestingExecutor(t, job, ioutil.Discard)
+	defer stop()
+
+	// the container is removed even if files fail to be collected.
+	assert.NoError(executor.Prepare(ctx))
+	assert.Error(executor.Cleanup(ctx))
+	assert.Empty(fake.containers)
+}
+
+func TestDockerFileCollector(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	fake := newFakeDocker()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c, err := newClient(server.URL, "")
+	assert.NoError(err)
+
+	fc := NewDockerFileCollector(c, "/pistage")
+	fc.SetFiles(map[string][]byte{"a/file1": []byte("content1"), "file2": []byte("content2"), "../outside": []byte("x")})
+	assert.NoError(fc.CopyTo(ctx, "container0", nil))
+	assert.Equal(map[string][]byte{"/pistage/a/file1": []byte("content1"), "/pistage/file2": []byte("content2")}, fake.files)
+
+	collector := NewDockerFileCollector(c, "/pistage")
+	assert.NoError(collector.Collect(ctx, "container0", []string{"a/file1"}))
+	content, ok := collector.getFile("a/file1")
+	assert.True(ok)
+	assert.Equal([]byte("content1"), content)
+
+	assert.ErrorIs(collector.Collect(ctx, "container0", []string{"missing"}), ErrorDockerNotFound)
+}
+
+func TestDemultiplex(t *testing.T) {
+	assert := assert.New(t)
+
+	stream := &bytes.Buffer{}
+	for i, frame := range []string{"out", "err"} {
+		header := make([]byte, 8)
+		header[0] = byte(i + 1)
+		binary.BigEndian.PutUint32(header[4:], uint32(len(frame)))
+		stream.Write(header)
+		stream.WriteString(frame)
+	}
+
+	output := &bytes.Buffer{}
+	assert.NoError(demultiplex(stream, output))
+	assert.Equal("outerr", output.String())
+}
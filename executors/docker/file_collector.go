@@ -0,0 +1,160 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DockerFileCollector collects or sends files from or to the container
+// through the archive endpoints of Docker Engine API.
+// Note: the paths of files are relative to root.
+type DockerFileCollector struct {
+	mutex  sync.Mutex
+	client *client
+
+	// files kept in collector.
+	// The names are **RELATIVE** to root.
+	files map[string][]byte
+
+	// root is the root dir of this file collector.
+	// This should be **ABSOLUTE**.
+	root string
+}
+
+// NewDockerFileCollector creates a DockerFileCollector,
+// note that root must be an absolute path.
+func NewDockerFileCollector(client *client, root string) *DockerFileCollector {
+	return &DockerFileCollector{
+		client: client,
+		files:  map[string][]byte{},
+		root:   root,
+	}
+}
+
+func (d *DockerFileCollector) SetFiles(files map[string][]byte) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.files = files
+}
+
+// Collect collects files from the container.
+// For a DockerFileCollector, identifier represents the container id.
+func (d *DockerFileCollector) Collect(ctx context.Context, identifier string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, file := range files {
+		path := filepath.Join(d.root, file)
+		// We don't allow files out of d.root to be collected.
+		if !strings.HasPrefix(path, d.root) {
+			continue
+		}
+
+		content, err := d.client.getArchive(ctx, identifier, path)
+		if err != nil {
+			return err
+		}
+
+		// the archive of a file contains only the file itself.
+		tr := tar.NewReader(bytes.NewReader(content))
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if header.Typeflag != tar.TypeReg {
+				continue
+			}
+			buffer := &bytes.Buffer{}
+			if _, err := io.Copy(buffer, tr); err != nil {
+				return err
+			}
+			d.files[file] = buffer.Bytes()
+			break
+		}
+	}
+	return nil
+}
+
+// CopyTo copies files to the container.
+// For a DockerFileCollector, identifier represents the container id.
+// Files are sent as one tarball extracted at /, with root as the prefix of names,
+// so all the essential dirs are created by Docker.
+func (d *DockerFileCollector) CopyTo(ctx context.Context, identifier string, files []string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(files) == 0 {
+		for name := range d.files {
+			files = append(files, name)
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	tw := tar.NewWriter(buffer)
+	count := 0
+	for _, filename := range files {
+		content, ok := d.files[filename]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(d.root, filename)
+		// We don't allow to copy files out of root.
+		if !strings.HasPrefix(path, d.root) {
+			continue
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name: strings.TrimPrefix(path, "/"),
+			Mode: 0644,
+			Size: int64(len(content)),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+	return d.client.putArchive(ctx, identifier, "/", buffer)
+}
+
+// Files returns all file names including path this collector holds.
+func (d *DockerFileCollector) Files() []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var files []string
+	for file := range d.files {
+		files = append(files, file)
+	}
+	return files
+}
+
+// getFile returns the content of the file this collector holds.
+func (d *DockerFileCollector) getFile(name string) ([]byte, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	content, ok := d.files[name]
+	return content, ok
+}
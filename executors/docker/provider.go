@@ -0,0 +1,36 @@
+package docker
+
+import (
+	"io"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/store"
+)
+
+type DockerJobExecutorProvider struct {
+	config *common.Config
+	client *client
+	store  store.Store
+}
+
+func NewDockerJobExecutorProvider(config *common.Config, store store.Store) (*DockerJobExecutorProvider, error) {
+	c, err := newClient(config.Docker.Host, config.Docker.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DockerJobExecutorProvider{
+		config: config,
+		client: c,
+		store:  store,
+	}, nil
+}
+
+func (dp *DockerJobExecutorProvider) GetName() string {
+	return "docker"
+}
+
+func (dp *DockerJobExecutorProvider) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
+	return NewDockerJobExecutor(job, pistage, output, dp.client, dp.store, dp.config)
+}
@@ -0,0 +1,107 @@
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// DryRunJobExecutor renders the job into a plan and writes it to output,
+// nothing is executed.
+type DryRunJobExecutor struct {
+	planner *Planner
+
+	job     *common.Job
+	pistage *common.Pistage
+	output  io.Writer
+
+	plan *JobPlan
+}
+
+// NewDryRunJobExecutor creates a DryRun executor for this job.
+// Since job needs to know its context, pistage is assigned too.
+func NewDryRunJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, planner *Planner) (*DryRunJobExecutor, error) {
+	return &DryRunJobExecutor{
+		planner: planner,
+		job:     job,
+		pistage: pistage,
+		output:  output,
+	}, nil
+}
+
+// Prepare renders the job,
+// fails if any command can't be rendered or any KhoriumStep can't be resolved.
+func (d *DryRunJobExecutor) Prepare(ctx context.Context) error {
+	plan, err := d.planner.PlanJob(ctx, d.job, d.pistage)
+	if err != nil {
+		return err
+	}
+	d.plan = plan
+	return nil
+}
+
+// Execute writes the steps would be executed.
+func (d *DryRunJobExecutor) Execute(ctx context.Context) error {
+	return writeSteps(d.output, d.plan.Steps)
+}
+
+// Cleanup does nothing, since nothing is created.
+func (d *DryRunJobExecutor) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+// Rollback writes the rollback steps would be executed.
+func (d *DryRunJobExecutor) Rollback(ctx context.Context) error {
+	return writeSteps(d.output, d.plan.RollbackSteps)
+}
+
+// writeSteps writes steps in a readable way, e.g.
+//   step build
+//     $ go build ./...
+//   step lint uses github.com/test/lint@v1
+//     with path=./...
+//     $ ./lint.sh
+func writeSteps(output io.Writer, steps []*StepPlan) error {
+	for _, step := range steps {
+		header := fmt.Sprintf("step %s", step.Name)
+		if step.Uses != "" {
+			header = fmt.Sprintf("%s uses %s", header, step.Uses)
+		}
+		if _, err := fmt.Fprintln(output, header); err != nil {
+			return err
+		}
+		for _, name := range sortedKeys(step.Inputs) {
+			if _, err := fmt.Fprintf(output, "  with %s=%s\n", name, step.Inputs[name]); err != nil {
+				return err
+			}
+		}
+		for _, cmd := range step.Commands {
+			if _, err := fmt.Fprintf(output, "  $ %s\n", cmd); err != nil {
+				return err
+			}
+		}
+		for _, cmd := range step.OnError {
+			if _, err := fmt.Fprintf(output, "  on error $ %s\n", cmd); err != nil {
+				return err
+			}
+		}
+		if step.Post != "" {
+			if _, err := fmt.Fprintf(output, "  post $ %s\n", step.Post); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/store"
+)
+
+// fakeStore only provides KhoriumSteps.
+type fakeStore struct {
+	store.Store
+}
+
+func (s *fakeStore) GetRegisteredKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	return &common.KhoriumStep{
+		Name: name,
+		Inputs: map[string]*common.KhoriumStepInput{
+			"path":  {Required: true},
+			"level": {Default: "warn"},
+		},
+		Run: &common.KhoriumStepRun{Main: "./lint.sh", Post: "./clean.sh"},
+	}, nil
+}
+
+var spec = `
+workflow_type: test
+workflow_identifier: dryrun
+executor: dryrun
+env:
+  GOOS: linux
+jobs:
+  build:
+    steps:
+      - name: build
+        run:
+          - go build -o {{ output }} ./...
+        with:
+          output: bin/{{ env.GOOS }}
+        on_error:
+          - echo {{ env.GOOS }} failed
+  lint:
+    steps:
+      - name: lint
+        uses: github.com/test/lint@v1
+        with:
+          path: "{{ env.DIR }}"
+        env:
+          DIR: ./cmd
+  deploy:
+    depends_on:
+      - build
+      - lint
+    steps:
+      - name: deploy
+        run:
+          - ./deploy.sh
+    rollback_steps:
+      - name: revert
+        run:
+          - ./revert.sh {{ env.VERSION }}
+        env:
+          VERSION: v1
+`
+
+func TestPlanPistage(t *testing.T) {
+	assert := assert.New(t)
+
+	pistage, err := common.FromSpec([]byte(spec))
+	assert.NoError(err)
+
+	plan, err := NewPlanner(&fakeStore{}).PlanPistage(context.Background(), pistage)
+	assert.NoError(err)
+	assert.Equal([][]string{{"build", "lint"}, {"deploy"}}, plan.Stages)
+
+	build := plan.Jobs["build"].Steps[0]
+	assert.Equal([]string{"go build -o bin/linux ./..."}, build.Commands)
+	assert.Equal([]string{"echo linux failed"}, build.OnError)
+	assert.Equal(map[string]string{"GOOS": "linux"}, build.Environment)
+
+	lint := plan.Jobs["lint"].Steps[0]
+	assert.Equal("github.com/test/lint@v1", lint.Uses)
+	assert.Equal([]string{"./lint.sh"}, lint.Commands)
+	assert.Equal("./clean.sh", lint.Post)
+	assert.Equal(map[string]string{"path": "./cmd"}, lint.Inputs)
+	assert.Equal("./cmd", lint.Environment["KHORIUMSTEP_INPUT_PATH"])
+	assert.Equal("warn", lint.Environment["KHORIUMSTEP_INPUT_LEVEL"])
+
+	assert.Equal([]string{"build", "lint"}, plan.Jobs["deploy"].DependsOn)
+	assert.Equal([]string{"./revert.sh v1"}, plan.Jobs["deploy"].RollbackSteps[0].Commands)
+}
+
+func TestDryRunJobExecutor(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	pistage, err := common.FromSpec([]byte(spec))
+	assert.NoError(err)
+
+	provider, err := NewDryRunJobExecutorProvider(&fakeStore{})
+	assert.NoError(err)
+	assert.Equal("dryrun", provider.GetName())
+
+	output := &bytes.Buffer{}
+	executor, err := provider.GetJobExecutor(pistage.Jobs["build"], pistage, output)
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("step build\n  $ go build -o bin/linux ./...\n  on error $ echo linux failed\n", output.String())
+
+	output.Reset()
+	executor, err = provider.GetJobExecutor(pistage.Jobs["lint"], pistage, output)
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	assert.Equal("step lint uses github.com/test/lint@v1\n  with path=./cmd\n  $ ./lint.sh\n  post $ ./clean.sh\n", output.String())
+
+	output.Reset()
+	executor, err = provider.GetJobExecutor(pistage.Jobs["deploy"], pistage, output)
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Rollback(ctx))
+	assert.Equal("step revert\n  $ ./revert.sh v1\n", output.String())
+
+	// required input is missing.
+	pistage.Jobs["lint"].Steps[0].With = nil
+	executor, err = provider.GetJobExecutor(pistage.Jobs["lint"], pistage, output)
+	assert.NoError(err)
+	assert.ErrorIs(executor.Prepare(ctx), common.ErrorInputIsRequired)
+}
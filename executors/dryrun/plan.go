@@ -0,0 +1,190 @@
+package dryrun
+
+import (
+	"context"
+	"sort"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/helpers/command"
+	"github.com/projecteru2/pistage/helpers/variable"
+	"github.com/projecteru2/pistage/store"
+)
+
+// Plan describes what would be executed for a pistage.
+// Stages are the names of jobs can be executed parallelly,
+// in the order given by JobDependencies, names within a stage are sorted.
+type Plan struct {
+	WorkflowType       string
+	WorkflowIdentifier string
+	Stages             [][]string
+	Jobs               map[string]*JobPlan
+}
+
+// JobPlan describes what would be executed for a job.
+type JobPlan struct {
+	Name          string
+	DependsOn     []string
+	Steps         []*StepPlan
+	RollbackSteps []*StepPlan
+}
+
+// StepPlan describes what would be executed for a step.
+// For a normal step, Commands and OnError are rendered with the final environment.
+// For a KhoriumStep, Commands is the main command of the KhoriumStep,
+// Post is the post command executed during cleanup,
+// and Inputs are the resolved inputs, as the environment variables passed to it.
+type StepPlan struct {
+	Name        string
+	Uses        string
+	Commands    []string
+	OnError     []string
+	Post        string
+	Environment map[string]string
+	Inputs      map[string]string
+}
+
+// Planner renders pistages into plans,
+// the store is only used to resolve KhoriumSteps.
+type Planner struct {
+	store store.Store
+}
+
+func NewPlanner(store store.Store) *Planner {
+	return &Planner{store: store}
+}
+
+// PlanPistage renders all the jobs in pistage,
+// with the stages they would be executed.
+func (p *Planner) PlanPistage(ctx context.Context, pistage *common.Pistage) (*Plan, error) {
+	deps, err := pistage.JobDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		WorkflowType:       pistage.WorkflowType,
+		WorkflowIdentifier: pistage.WorkflowIdentifier,
+		Jobs:               map[string]*JobPlan{},
+	}
+	for _, jobs := range deps {
+		var stage []string
+		for _, job := range jobs {
+			jobPlan, err := p.PlanJob(ctx, job, pistage)
+			if err != nil {
+				return nil, err
+			}
+			plan.Jobs[job.Name] = jobPlan
+			stage = append(stage, job.Name)
+		}
+		sort.Strings(stage)
+		plan.Stages = append(plan.Stages, stage)
+	}
+	return plan, nil
+}
+
+// PlanJob renders all the steps and rollback steps in job.
+func (p *Planner) PlanJob(ctx context.Context, job *common.Job, pistage *common.Pistage) (*JobPlan, error) {
+	steps, err := p.planSteps(ctx, job.Steps, pistage)
+	if err != nil {
+		return nil, err
+	}
+	rollbackSteps, err := p.planSteps(ctx, job.RollbackSteps, pistage)
+	if err != nil {
+		return nil, err
+	}
+	return &JobPlan{
+		Name:          job.Name,
+		DependsOn:     job.DependsOn,
+		Steps:         steps,
+		RollbackSteps: rollbackSteps,
+	}, nil
+}
+
+// planSteps plans steps the same way executors execute them,
+// composite KhoriumSteps are expanded into the steps they're made of.
+func (p *Planner) planSteps(ctx context.Context, steps []*common.Step, pistage *common.Pistage) ([]*StepPlan, error) {
+	steps, err := executors.ExpandSteps(ctx, p.store, steps, pistage.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []*StepPlan
+	for _, step := range steps {
+		var (
+			plan *StepPlan
+			err  error
+		)
+		switch step.Uses {
+		case "":
+			plan, err = p.planStep(step, pistage)
+		default:
+			plan, err = p.planKhoriumStep(ctx, step, pistage)
+		}
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// planStep renders the commands the same way executors do,
+// with step environment merged into pistage environment.
+func (p *Planner) planStep(step *common.Step, pistage *common.Pistage) (*StepPlan, error) {
+	environment := command.MergeVariables(pistage.Environment, step.Environment)
+
+	commands, err := renderCommands(step.Run, step.With, environment, pistage.Vars)
+	if err != nil {
+		return nil, err
+	}
+	onError, err := renderCommands(step.OnError, step.With, environment, pistage.Vars)
+	if err != nil {
+		return nil, err
+	}
+	return &StepPlan{
+		Name:        step.Name,
+		Commands:    commands,
+		OnError:     onError,
+		Environment: environment,
+	}, nil
+}
+
+// planKhoriumStep resolves the KhoriumStep and its inputs,
+// the environment is what executors pass to the main command.
+func (p *Planner) planKhoriumStep(ctx context.Context, step *common.Step, pistage *common.Pistage) (*StepPlan, error) {
+	ks, err := p.store.GetRegisteredKhoriumStep(ctx, step.Uses)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := variable.RenderArguments(step.With, step.Environment, pistage.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	ksEnv, err := ks.BuildEnvironmentVariables(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return &StepPlan{
+		Name:        step.Name,
+		Uses:        step.Uses,
+		Commands:    []string{ks.Run.Main},
+		Post:        ks.Run.Post,
+		Environment: command.MergeVariables(step.Environment, ksEnv),
+		Inputs:      inputs,
+	}, nil
+}
+
+func renderCommands(cmds []string, args, env, vars map[string]string) ([]string, error) {
+	var commands []string
+	for _, cmd := range cmds {
+		c, err := command.RenderCommand(cmd, args, env, vars)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, c)
+	}
+	return commands, nil
+}
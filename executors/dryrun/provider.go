@@ -0,0 +1,27 @@
+package dryrun
+
+import (
+	"io"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/store"
+)
+
+type DryRunJobExecutorProvider struct {
+	planner *Planner
+}
+
+func NewDryRunJobExecutorProvider(store store.Store) (*DryRunJobExecutorProvider, error) {
+	return &DryRunJobExecutorProvider{
+		planner: NewPlanner(store),
+	}, nil
+}
+
+func (d *DryRunJobExecutorProvider) GetName() string {
+	return "dryrun"
+}
+
+func (d *DryRunJobExecutorProvider) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
+	return NewDryRunJobExecutor(job, pistage, output, d.planner)
+}
@@ -0,0 +1,85 @@
+package eru
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	corepb "github.com/projecteru2/core/rpc/gen"
+
+	"github.com/projecteru2/pistage/executors"
+)
+
+// restoreCache restores the caches of job into the workload.
+func (e *EruJobExecutor) restoreCache(ctx context.Context) error {
+	return e.caches.Restore(ctx, e.job, e.jobEnvironment, e.store, e.output, e.cacheTransport())
+}
+
+// saveCache saves the missed caches of job.
+func (e *EruJobExecutor) saveCache(ctx context.Context) {
+	e.caches.Save(ctx, e.job, e.store, e.output, e.cacheTransport())
+}
+
+// cacheTransport runs commands in the working dir of the workload,
+// tarballs are transferred through the copy and send APIs of eru.
+func (e *EruJobExecutor) cacheTransport() *executors.CacheTransport {
+	return &executors.CacheTransport{
+		Run: func(ctx context.Context, cmd string, output io.Writer) error {
+			return e.executeWorkload(ctx, []string{"/bin/sh", "-c", cmd}, nil, "", output)
+		},
+		Upload: func(ctx context.Context, name string, content io.Reader) error {
+			// eru sends a file as a whole.
+			buffer, err := ioutil.ReadAll(content)
+			if err != nil {
+				return err
+			}
+			fc := NewEruFileCollector(e.eru, e.workingDir, e.job)
+			fc.SetFiles(map[string][]byte{name: buffer})
+			return fc.CopyTo(ctx, e.workloadID, nil)
+		},
+		Download: e.downloadFile,
+	}
+}
+
+// downloadFile streams the file name in the working dir of the workload to w.
+func (e *EruJobExecutor) downloadFile(ctx context.Context, name string, w io.Writer) error {
+	path := filepath.Join(e.workingDir, name)
+	resp, err := e.eru.Copy(ctx, &corepb.CopyOptions{
+		Targets: map[string]*corepb.CopyPaths{
+			e.workloadID: {Paths: []string{path}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// messages carry chunks of the tarball of path.
+	r, pw := io.Pipe()
+	defer r.Close()
+	go func() {
+		for {
+			message, err := resp.Recv()
+			if err == io.EOF {
+				pw.Close()
+				return
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if message.Error != "" {
+				pw.CloseWithError(fmt.Errorf(message.Error))
+				return
+			}
+			if message.Path != path {
+				continue
+			}
+			if _, err := pw.Write(message.Data); err != nil {
+				return
+			}
+		}
+	}()
+	return executors.CopyTarFile(r, w)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
 	"github.com/projecteru2/pistage/helpers/command"
 	"github.com/projecteru2/pistage/helpers/variable"
 	"github.com/projecteru2/pistage/store"
@@ -38,8 +39,18 @@ type EruJobExecutor struct {
 
 	output         io.Writer
 	workloadID     string
+	services       []*serviceWorkload
 	jobEnvironment map[string]string
 	workingDir     string
+
+	// caches are the caches of job, restored in Prepare.
+	caches executors.JobCaches
+
+	// posts are run in Cleanup, see executors.PostHooks.
+	posts executors.PostHooks
+
+	// outputs holds the outputs of KhoriumSteps executed.
+	outputs executors.StepOutputs
 }
 
 // NewEruJobExecutor creates an ERU executor for this job.
@@ -60,8 +71,10 @@ func NewEruJobExecutor(job *common.Job, pistage *common.Pistage, output io.Write
 // Prepare does all the preparations before actually running a job
 func (e *EruJobExecutor) Prepare(ctx context.Context) error {
 	preparations := []func(context.Context) error{
+		e.prepareServices,
 		e.prepareJobRuntime,
 		e.prepareFileContext,
+		e.restoreCache,
 	}
 	for _, f := range preparations {
 		if err := f(ctx); err != nil {
@@ -74,22 +87,31 @@ func (e *EruJobExecutor) Prepare(ctx context.Context) error {
 // prepareJobRuntime currently creates an empty lambda workload.
 // The empty lambda workload is actually a sleep process which lasts timeout seconds.
 func (e *EruJobExecutor) prepareJobRuntime(ctx context.Context) error {
-	lambda, err := e.eru.RunAndWait(ctx)
+	workloadID, err := e.runLambda(ctx, e.buildEruLambdaOptions())
 	if err != nil {
 		return err
 	}
 
-	if err := lambda.Send(e.buildEruLambdaOptions()); err != nil {
-		return err
+	e.workloadID = workloadID
+	return nil
+}
+
+// runLambda creates a lambda workload with opts, returns the workload id.
+func (e *EruJobExecutor) runLambda(ctx context.Context, opts *corepb.RunAndWaitOptions) (string, error) {
+	lambda, err := e.eru.RunAndWait(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := lambda.Send(opts); err != nil {
+		return "", err
 	}
 
 	message, err := lambda.Recv()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	e.workloadID = message.WorkloadId
-
 	// eat all the remaing messages
 	go func() {
 		for {
@@ -101,7 +123,7 @@ func (e *EruJobExecutor) prepareJobRuntime(ctx context.Context) error {
 			}
 		}
 	}()
-	return nil
+	return message.WorkloadId, nil
 }
 
 // defaultEnvironmentVariables sets some useful information into environment variables.
@@ -119,34 +141,86 @@ func (e *EruJobExecutor) defaultEnvironmentVariables() map[string]string {
 // buildEruLambdaOptions builds the options for ERU lambda workload.
 // Currently only container supports, it's just because I never tried virtual machines
 // or systemd engine...
+// Resources and placement of the job override the defaults in config.
 func (e *EruJobExecutor) buildEruLambdaOptions() *corepb.RunAndWaitOptions {
 	jobImage := e.job.Image
 	if jobImage == "" {
 		jobImage = e.config.Eru.DefaultJobImage
 	}
 
+	podname := e.job.Pod
+	if podname == "" {
+		podname = e.config.Eru.DefaultPodname
+	}
+
+	network := e.job.Network
+	if network == "" {
+		network = e.config.Eru.DefaultNetwork
+	}
+
+	user := e.job.User
+	if user == "" {
+		user = e.config.Eru.DefaultUser
+	}
+
+	privileged := e.config.Eru.DefaultPrivileged
+	if e.job.Privileged != nil {
+		privileged = *e.job.Privileged
+	}
+
+	var nodeFilter *corepb.NodeFilter
+	if len(e.job.Nodes) > 0 || len(e.job.NodeLabels) > 0 {
+		nodeFilter = &corepb.NodeFilter{
+			Includes: e.job.Nodes,
+			Labels:   e.job.NodeLabels,
+		}
+	}
+
 	return &corepb.RunAndWaitOptions{
 		DeployOptions: &corepb.DeployOptions{
 			Name: e.job.Name,
 			Entrypoint: &corepb.EntrypointOptions{
 				Name:       e.job.Name,
 				Commands:   command.EmptyWorkloadCommand(e.job.Timeout),
-				Privileged: e.config.Eru.DefaultPrivileged,
+				Privileged: privileged,
 				Dir:        e.workingDir,
 			},
-			Podname:        e.config.Eru.DefaultPodname,
+			Podname:        podname,
+			NodeFilter:     nodeFilter,
 			Image:          jobImage,
 			Count:          1,
-			Env:            command.ToEnvironmentList(command.MergeVariables(command.PreparePistageEnvs(e.jobEnvironment), e.defaultEnvironmentVariables())),
-			Networks:       map[string]string{e.config.Eru.DefaultNetwork: ""},
+			Env:            command.ToEnvironmentList(command.MergeVariables(command.MergeVariables(command.PreparePistageEnvs(e.jobEnvironment), e.defaultEnvironmentVariables()), e.serviceEnvironmentVariables())),
+			Dns:            e.job.DNS,
+			ExtraHosts:     e.serviceExtraHosts(),
+			Networks:       map[string]string{network: ""},
 			DeployStrategy: corepb.DeployOptions_AUTO,
-			ResourceOpts:   &corepb.ResourceOptions{},
-			User:           e.config.Eru.DefaultUser,
+			ResourceOpts:   e.buildResourceOptions(),
+			User:           user,
 		},
 		Async: false,
 	}
 }
 
+// buildResourceOptions builds the resource options of the job,
+// requests and limits are the same, so the job gets exactly what it asks.
+func (e *EruJobExecutor) buildResourceOptions() *corepb.ResourceOptions {
+	opts := &corepb.ResourceOptions{
+		VolumesLimit:   e.job.Volumes,
+		VolumesRequest: e.job.Volumes,
+	}
+	if e.job.Resources == nil {
+		return opts
+	}
+
+	opts.CpuQuotaLimit = e.job.Resources.CPU
+	opts.CpuQuotaRequest = e.job.Resources.CPU
+	opts.MemoryLimit = int64(e.job.Resources.Memory)
+	opts.MemoryRequest = int64(e.job.Resources.Memory)
+	opts.StorageLimit = int64(e.job.Resources.Storage)
+	opts.StorageRequest = int64(e.job.Resources.Storage)
+	return opts
+}
+
 func (e *EruJobExecutor) prepareFileContext(ctx context.Context) error {
 	dependentJobs := e.pistage.GetJobs(e.job.DependsOn)
 	for _, job := range dependentJobs {
@@ -161,13 +235,25 @@ func (e *EruJobExecutor) prepareFileContext(ctx context.Context) error {
 	return nil
 }
 
-// Execute will execute all steps within this job one by one
+// Execute will execute all steps within this job one by one,
+// caches missed are saved after all steps succeeded.
 func (e *EruJobExecutor) Execute(ctx context.Context) error {
-	return e.executeSteps(ctx, e.job.Steps)
+	if err := e.executeSteps(ctx, e.job.Steps); err != nil {
+		return err
+	}
+	e.saveCache(ctx)
+	return nil
 }
 
 // executeDifferentJob dispatch executor
 func (e *EruJobExecutor) executeSteps(ctx context.Context, steps []*common.Step) error {
+	// composite KhoriumSteps are expanded first,
+	// so only plain steps and plain KhoriumSteps are left.
+	steps, err := executors.ExpandSteps(ctx, e.store, steps, e.pistage.Vars)
+	if err != nil {
+		return err
+	}
+
 	for _, step := range steps {
 		var err error
 		switch step.Uses {
@@ -195,10 +281,10 @@ func (e *EruJobExecutor) executeSteps(ctx context.Context, steps []*common.Step)
 func (e *EruJobExecutor) executeStep(ctx context.Context, step *common.Step) error {
 	var (
 		err  error
-		vars map[string]string
+		vars = e.pistage.Vars
 	)
 
-	environment := command.MergeVariables(e.jobEnvironment, step.Environment)
+	environment := command.MergeVariables(command.MergeVariables(e.jobEnvironment, e.outputs.Environment()), step.Environment)
 
 	defer func() {
 		if !errors.Is(err, common.ErrExecutionError) {
@@ -220,7 +306,7 @@ func (e *EruJobExecutor) executeKhoriumStep(ctx context.Context, step *common.St
 		return err
 	}
 
-	arguments, err := variable.RenderArguments(step.With, step.Environment, map[string]string{})
+	arguments, err := variable.RenderArguments(step.With, command.MergeVariables(e.outputs.Environment(), step.Environment), e.pistage.Vars)
 	if err != nil {
 		return err
 	}
@@ -229,53 +315,28 @@ func (e *EruJobExecutor) executeKhoriumStep(ctx context.Context, step *common.St
 	if err != nil {
 		return err
 	}
-	envs := command.MergeVariables(step.Environment, ksEnv)
+	envs := command.MergeVariables(command.MergeVariables(e.outputs.Environment(), step.Environment), ksEnv)
 
-	fc := NewEruFileCollector(e.eru, khoriumStepWorkingDir, e.job)
-	fc.SetFiles(ks.Files)
-	if err := fc.CopyTo(ctx, e.workloadID, nil); err != nil {
+	if err := e.copyKhoriumStepFiles(ctx, khoriumStepWorkingDir, ks.Files); err != nil {
 		return err
 	}
 
-	exec, err := e.eru.ExecuteWorkload(ctx)
+	outputFile, err := executors.OutputFile(khoriumStepWorkingDir, step)
 	if err != nil {
 		return err
 	}
-
-	if err := exec.Send(&corepb.ExecuteWorkloadOptions{
-		WorkloadId: e.workloadID,
-		Commands:   []string{"/bin/sh", "-c", ks.Run.Main},
-		Envs:       command.ToEnvironmentList(envs),
-		Workdir:    khoriumStepWorkingDir,
-	}); err != nil {
-		return err
+	if len(ks.Outputs) != 0 {
+		envs[executors.KhoriumStepOutputEnv] = outputFile
 	}
 
-	for {
-		message, err := exec.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
+	e.posts.Queue(step.Name, ks, envs, khoriumStepWorkingDir)
 
-		data := string(message.Data)
-		if strings.HasPrefix(data, exitMessagePrefix) {
-			exitcode, err := strconv.Atoi(strings.TrimPrefix(data, exitMessagePrefix))
-			if err != nil {
-				return err
-			}
-			if exitcode != 0 {
-				return errors.WithMessagef(common.ErrExecutionError, "exitcode: %d", exitcode)
-			}
-		} else {
-			if _, err := io.WriteString(e.output, data); err != nil {
-				return err
-			}
-		}
+	if err := e.executeWorkload(ctx, []string{"/bin/sh", "-c", ks.Run.Main}, envs, khoriumStepWorkingDir, e.output); err != nil {
+		return err
 	}
-	return exec.CloseSend()
+	return e.outputs.Collect(ks, step.Name, outputFile, func(cmd string, output io.Writer) error {
+		return e.executeWorkload(ctx, []string{"/bin/sh", "-c", cmd}, envs, khoriumStepWorkingDir, output)
+	})
 }
 
 // executeCommands executes cmd with given arguments, environments and variables.
@@ -300,6 +361,13 @@ func (e *EruJobExecutor) executeCommands(ctx context.Context, cmds []string, arg
 		return err
 	}
 
+	return e.executeWorkload(ctx, []string{"/bin/sh", "-c", shell}, env, "", e.output)
+}
+
+// executeWorkload executes cmds within the workload, writes the output to output.
+// If workdir is empty, the working dir of the workload is used.
+// This method should be sync.
+func (e *EruJobExecutor) executeWorkload(ctx context.Context, cmds []string, env map[string]string, workdir string, output io.Writer) error {
 	exec, err := e.eru.ExecuteWorkload(ctx)
 	if err != nil {
 		return err
@@ -307,8 +375,9 @@ func (e *EruJobExecutor) executeCommands(ctx context.Context, cmds []string, arg
 
 	if err := exec.Send(&corepb.ExecuteWorkloadOptions{
 		WorkloadId: e.workloadID,
-		Commands:   []string{"/bin/sh", "-c", shell},
+		Commands:   cmds,
 		Envs:       command.ToEnvironmentList(env),
+		Workdir:    workdir,
 	}); err != nil {
 		return err
 	}
@@ -332,7 +401,7 @@ func (e *EruJobExecutor) executeCommands(ctx context.Context, cmds []string, arg
 				return errors.WithMessagef(common.ErrExecutionError, "exitcode: %d", exitcode)
 			}
 		} else {
-			if _, err := io.WriteString(e.output, data); err != nil {
+			if _, err := io.WriteString(output, data); err != nil {
 				return err
 			}
 		}
@@ -340,8 +409,24 @@ func (e *EruJobExecutor) executeCommands(ctx context.Context, cmds []string, arg
 	return exec.CloseSend()
 }
 
+// copyKhoriumStepFiles copies files of a KhoriumStep into dir of the workload.
+func (e *EruJobExecutor) copyKhoriumStepFiles(ctx context.Context, dir string, files map[string][]byte) error {
+	fc := NewEruFileCollector(e.eru, dir, e.job)
+	fc.SetFiles(files)
+	return fc.CopyTo(ctx, e.workloadID, nil)
+}
+
+// executePost executes the post command of a KhoriumStep.
+func (e *EruJobExecutor) executePost(ctx context.Context, hook *executors.PostHook) error {
+	return e.executeWorkload(ctx, []string{"/bin/sh", "-c", hook.Command}, hook.Environment, hook.WorkingDir, e.output)
+}
+
 // beforeCleanup collects files if any
 func (e *EruJobExecutor) beforeCleanup(ctx context.Context) error {
+	if e.workloadID == "" {
+		return nil
+	}
+
 	fc := NewEruFileCollector(e.eru, e.workingDir, e.job)
 	if err := fc.Collect(ctx, e.workloadID, e.job.Files); err != nil {
 		return err
@@ -351,12 +436,23 @@ func (e *EruJobExecutor) beforeCleanup(ctx context.Context) error {
 	return nil
 }
 
-// cleanup currently just stops the workload.
+// cleanup currently just stops the workload and services.
 // On ERU side, the stopped lambda workload will be removed automatically,
 // so just leave the cleanup work to ERU.
 func (e *EruJobExecutor) cleanup(ctx context.Context) error {
+	var ids []string
+	if e.workloadID != "" {
+		ids = append(ids, e.workloadID)
+	}
+	for _, service := range e.services {
+		ids = append(ids, service.workloadID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
 	opts := &corepb.ControlWorkloadOptions{
-		Ids:   []string{e.workloadID},
+		Ids:   ids,
 		Type:  corecluster.WorkloadStop,
 		Force: true,
 	}
@@ -380,18 +476,18 @@ func (e *EruJobExecutor) cleanup(ctx context.Context) error {
 	return nil
 }
 
-// Cleanup does all the cleanup work
+// Cleanup does all the cleanup work,
+// post commands of KhoriumSteps are executed in reverse order first,
+// workloads are always stopped even if any of them or collecting files fails.
 func (e *EruJobExecutor) Cleanup(ctx context.Context) error {
-	cleanups := []func(context.Context) error{
-		e.beforeCleanup,
-		e.cleanup,
+	err := e.posts.Run(ctx, e.copyKhoriumStepFiles, e.executePost)
+	if cerr := e.beforeCleanup(ctx); cerr != nil && err == nil {
+		err = cerr
 	}
-	for _, f := range cleanups {
-		if err := f(ctx); err != nil {
-			return err
-		}
+	if cerr := e.cleanup(ctx); cerr != nil && err == nil {
+		err = cerr
 	}
-	return nil
+	return err
 }
 
 // Rollback is a function can execute rollback_steps commands which are defined in yaml file
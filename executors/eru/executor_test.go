@@ -0,0 +1,204 @@
+package eru
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	corepb "github.com/projecteru2/core/rpc/gen"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// fakeEru records the workloads deployed and stopped,
+// status of workloads is decided by status, with the times of polling.
+type fakeEru struct {
+	corepb.CoreRPCClient
+
+	sync.Mutex
+	deployed map[string]*corepb.DeployOptions
+	stopped  []string
+	polls    int
+	status   func(options *corepb.DeployOptions, polls int) *corepb.WorkloadStatus
+}
+
+func (f *fakeEru) RunAndWait(ctx context.Context, opts ...grpc.CallOption) (corepb.CoreRPC_RunAndWaitClient, error) {
+	return &fakeLambda{eru: f}, nil
+}
+
+func (f *fakeEru) GetWorkloadsStatus(ctx context.Context, in *corepb.WorkloadIDs, opts ...grpc.CallOption) (*corepb.WorkloadsStatus, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.polls++
+	resp := &corepb.WorkloadsStatus{}
+	for _, id := range in.Ids {
+		status := f.status(f.deployed[id], f.polls)
+		status.Id = id
+		resp.Status = append(resp.Status, status)
+	}
+	return resp, nil
+}
+
+func (f *fakeEru) ControlWorkload(ctx context.Context, in *corepb.ControlWorkloadOptions, opts ...grpc.CallOption) (corepb.CoreRPC_ControlWorkloadClient, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.stopped = append(f.stopped, in.Ids...)
+	return &fakeControl{}, nil
+}
+
+type fakeLambda struct {
+	grpc.ClientStream
+	eru *fakeEru
+	id  string
+}
+
+func (l *fakeLambda) Send(opts *corepb.RunAndWaitOptions) error {
+	l.eru.Lock()
+	defer l.eru.Unlock()
+
+	l.id = fmt.Sprintf("workload%d", len(l.eru.deployed))
+	l.eru.deployed[l.id] = opts.DeployOptions
+	return nil
+}
+
+func (l *fakeLambda) Recv() (*corepb.AttachWorkloadMessage, error) {
+	if l.id == "" {
+		return nil, io.EOF
+	}
+	id := l.id
+	l.id = ""
+	return &corepb.AttachWorkloadMessage{WorkloadId: id}, nil
+}
+
+type fakeControl struct {
+	grpc.ClientStream
+}
+
+func (c *fakeControl) Recv() (*corepb.ControlWorkloadMessage, error) {
+	return nil, io.EOF
+}
+
+func TestBuildEruLambdaOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	config := &common.Config{Eru: common.EruConfig{
+		DefaultPrivileged: true,
+		DefaultWorkingDir: "/pistage",
+		DefaultPodname:    "ci",
+		DefaultJobImage:   "alpine",
+		DefaultUser:       "root",
+		DefaultNetwork:    "host",
+	}}
+	job := &common.Job{Name: "lint"}
+	pistage := &common.Pistage{Jobs: map[string]*common.Job{"lint": job}}
+
+	e, err := NewEruJobExecutor(job, pistage, io.Discard, nil, nil, config)
+	assert.NoError(err)
+
+	opts := e.buildEruLambdaOptions().DeployOptions
+	assert.Equal("ci", opts.Podname)
+	assert.Equal("alpine", opts.Image)
+	assert.Equal("root", opts.User)
+	assert.True(opts.Entrypoint.Privileged)
+	assert.Equal(map[string]string{"host": ""}, opts.Networks)
+	assert.Nil(opts.NodeFilter)
+	assert.Zero(opts.ResourceOpts.CpuQuotaLimit)
+
+	privileged := false
+	job.Pod = "build"
+	job.Network = "calico"
+	job.User = "builder"
+	job.Privileged = &privileged
+	job.Nodes = []string{"node1"}
+	job.NodeLabels = map[string]string{"arch": "amd64"}
+	job.DNS = []string{"8.8.8.8"}
+	job.Volumes = []string{"/data:/data:rw"}
+	job.Resources = &common.Resources{CPU: 2, Memory: 4 << 30, Storage: 20 << 30}
+
+	opts = e.buildEruLambdaOptions().DeployOptions
+	assert.Equal("build", opts.Podname)
+	assert.Equal("builder", opts.User)
+	assert.False(opts.Entrypoint.Privileged)
+	assert.Equal(map[string]string{"calico": ""}, opts.Networks)
+	assert.Equal([]string{"node1"}, opts.NodeFilter.Includes)
+	assert.Equal(map[string]string{"arch": "amd64"}, opts.NodeFilter.Labels)
+	assert.Equal([]string{"8.8.8.8"}, opts.Dns)
+	assert.Equal(float64(2), opts.ResourceOpts.CpuQuotaRequest)
+	assert.Equal(int64(4<<30), opts.ResourceOpts.MemoryLimit)
+	assert.Equal(int64(20<<30), opts.ResourceOpts.StorageRequest)
+	assert.Equal([]string{"/data:/data:rw"}, opts.ResourceOpts.VolumesLimit)
+}
+
+func TestEruJobExecutorServices(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	servicePollInterval = 10 * time.Millisecond
+
+	eru := &fakeEru{
+		deployed: map[string]*corepb.DeployOptions{},
+		// mysql becomes healthy at the 3rd polling, redis has no ports, running is enough.
+		status: func(options *corepb.DeployOptions, polls int) *corepb.WorkloadStatus {
+			switch options.Image {
+			case "mysql":
+				return &corepb.WorkloadStatus{Running: true, Healthy: polls >= 3, Networks: map[string]string{"calico": "10.0.0.2"}}
+			default:
+				return &corepb.WorkloadStatus{Running: true, Networks: map[string]string{"calico": "10.0.0.3"}}
+			}
+		},
+	}
+	config := &common.Config{Eru: common.EruConfig{DefaultNetwork: "host", ServiceReadyTimeoutSecs: 5}}
+	job := &common.Job{
+		Name:    "integration_test",
+		Network: "calico",
+		Services: map[string]*common.Service{
+			"mysql": {Image: "mysql", Environment: map[string]string{"MYSQL_ROOT_PASSWORD": "root"}, Ports: []int{3306}},
+			"redis": {Image: "redis"},
+		},
+	}
+	pistage := &common.Pistage{Jobs: map[string]*common.Job{"integration_test": job}}
+	output := &bytes.Buffer{}
+
+	e, err := NewEruJobExecutor(job, pistage, output, eru, nil, config)
+	assert.NoError(err)
+	assert.NoError(e.Prepare(ctx))
+	assert.Contains(output.String(), "Service mysql is ready at 10.0.0.2")
+	assert.GreaterOrEqual(eru.polls, 3)
+
+	mysql := eru.deployed["workload0"]
+	assert.Equal("integration-test-mysql", mysql.Entrypoint.Name)
+	assert.Equal([]string{"3306"}, mysql.Entrypoint.Healthcheck.TcpPorts)
+	assert.Equal(map[string]string{"calico": ""}, mysql.Networks)
+	assert.Equal([]string{"MYSQL_ROOT_PASSWORD=root"}, mysql.Env)
+	assert.Nil(eru.deployed["workload1"].Entrypoint.Healthcheck)
+
+	jobOptions := eru.deployed["workload2"]
+	assert.Contains(jobOptions.Env, "PISTAGE_SERVICE_MYSQL_ADDRESS=10.0.0.2:3306")
+	assert.Contains(jobOptions.Env, "PISTAGE_SERVICE_REDIS_HOST=10.0.0.3")
+	assert.Equal([]string{"mysql:10.0.0.2", "redis:10.0.0.3"}, jobOptions.ExtraHosts)
+
+	assert.NoError(e.cleanup(ctx))
+	assert.ElementsMatch([]string{"workload0", "workload1", "workload2"}, eru.stopped)
+
+	// services never ready, but they are still stopped.
+	eru = &fakeEru{
+		deployed: map[string]*corepb.DeployOptions{},
+		status: func(*corepb.DeployOptions, int) *corepb.WorkloadStatus {
+			return &corepb.WorkloadStatus{Running: true}
+		},
+	}
+	config.Eru.ServiceReadyTimeoutSecs = 1
+	e, err = NewEruJobExecutor(job, pistage, output, eru, nil, config)
+	assert.NoError(err)
+	assert.ErrorIs(e.Prepare(ctx), ErrorServiceNotReady)
+	assert.NoError(e.cleanup(ctx))
+	assert.ElementsMatch([]string{"workload0", "workload1"}, eru.stopped)
+}
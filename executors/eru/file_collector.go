@@ -288,3 +288,12 @@ func (e *EruFileCollector) Files() []string {
 	}
 	return files
 }
+
+// getFile returns the content of the file this collector holds.
+func (e *EruFileCollector) getFile(name string) ([]byte, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	content, ok := e.files[name]
+	return content, ok
+}
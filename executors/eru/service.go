@@ -0,0 +1,224 @@
+package eru
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corepb "github.com/projecteru2/core/rpc/gen"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/helpers/command"
+)
+
+// ErrorServiceNotReady is returned when services are not ready in time.
+var ErrorServiceNotReady = errors.New("Service not ready")
+
+// servicePollInterval is the interval to check status of services.
+var servicePollInterval = time.Second
+
+// serviceWorkload is a service started for the job.
+type serviceWorkload struct {
+	name       string
+	workloadID string
+	host       string
+	ports      []int
+}
+
+// prepareServices starts all the services of the job on the same network,
+// and waits until they are ready.
+// Services started are recorded before waiting, so they are stopped in cleanup anyway.
+func (e *EruJobExecutor) prepareServices(ctx context.Context) error {
+	if len(e.job.Services) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range e.job.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		service := e.job.Services[name]
+		workloadID, err := e.runLambda(ctx, e.buildServiceOptions(name, service))
+		if err != nil {
+			return err
+		}
+		e.services = append(e.services, &serviceWorkload{
+			name:       name,
+			workloadID: workloadID,
+			ports:      service.Ports,
+		})
+	}
+
+	if err := e.waitForServices(ctx); err != nil {
+		return err
+	}
+	for _, service := range e.services {
+		fmt.Fprintf(e.output, "Service %s is ready at %s\n", service.name, service.host)
+	}
+	return nil
+}
+
+// buildServiceOptions builds the options for the service lambda workload.
+// Services are deployed to the same pod and network as the job,
+// TCP ports are used as health check, so ERU tells us if it's ready.
+func (e *EruJobExecutor) buildServiceOptions(name string, service *common.Service) *corepb.RunAndWaitOptions {
+	workloadName := strings.ReplaceAll(fmt.Sprintf("%s-%s", e.job.Name, name), "_", "-")
+
+	var healthcheck *corepb.HealthCheckOptions
+	if len(service.Ports) > 0 {
+		healthcheck = &corepb.HealthCheckOptions{}
+		for _, port := range service.Ports {
+			healthcheck.TcpPorts = append(healthcheck.TcpPorts, strconv.Itoa(port))
+		}
+	}
+
+	jobOptions := e.buildEruLambdaOptions().DeployOptions
+	return &corepb.RunAndWaitOptions{
+		DeployOptions: &corepb.DeployOptions{
+			Name: workloadName,
+			Entrypoint: &corepb.EntrypointOptions{
+				Name:        workloadName,
+				Commands:    service.Command,
+				Healthcheck: healthcheck,
+			},
+			Podname:        jobOptions.Podname,
+			NodeFilter:     jobOptions.NodeFilter,
+			Image:          service.Image,
+			Count:          1,
+			Env:            command.ToEnvironmentList(service.Environment),
+			Networks:       jobOptions.Networks,
+			DeployStrategy: corepb.DeployOptions_AUTO,
+			ResourceOpts:   &corepb.ResourceOptions{},
+		},
+		Async: false,
+	}
+}
+
+// waitForServices polls the status of services until all of them are ready,
+// a service with ports is ready when it's healthy, otherwise when it's running.
+// The address of service on the network of job is recorded.
+func (e *EruJobExecutor) waitForServices(ctx context.Context) error {
+	timeout := time.Duration(e.config.Eru.ServiceReadyTimeoutSecs) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	network := e.buildEruLambdaOptions().DeployOptions.Networks
+	ticker := time.NewTicker(servicePollInterval)
+	defer ticker.Stop()
+
+	for {
+		ready, err := e.checkServices(ctx, network)
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			var pending []string
+			for _, service := range e.services {
+				if service.host == "" {
+					pending = append(pending, service.name)
+				}
+			}
+			return errors.WithMessagef(ErrorServiceNotReady, "services: %s", strings.Join(pending, ","))
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkServices checks the status of services not ready yet,
+// returns true if all services are ready.
+func (e *EruJobExecutor) checkServices(ctx context.Context, networks map[string]string) (bool, error) {
+	var ids []string
+	for _, service := range e.services {
+		if service.host == "" {
+			ids = append(ids, service.workloadID)
+		}
+	}
+	if len(ids) == 0 {
+		return true, nil
+	}
+
+	resp, err := e.eru.GetWorkloadsStatus(ctx, &corepb.WorkloadIDs{Ids: ids})
+	if err != nil {
+		return false, err
+	}
+
+	statuses := map[string]*corepb.WorkloadStatus{}
+	for _, status := range resp.Status {
+		statuses[status.Id] = status
+	}
+
+	ready := true
+	for _, service := range e.services {
+		if service.host != "" {
+			continue
+		}
+		status, ok := statuses[service.workloadID]
+		if !ok || !status.Running || (len(service.ports) > 0 && !status.Healthy) {
+			ready = false
+			continue
+		}
+		service.host = serviceHost(status.Networks, networks)
+		if service.host == "" {
+			ready = false
+		}
+	}
+	return ready, nil
+}
+
+// serviceHost returns the IP of service on one of the networks,
+// if not found, the IP on any network is returned.
+func serviceHost(addresses map[string]string, networks map[string]string) string {
+	for network := range networks {
+		if ip, ok := addresses[network]; ok && ip != "" {
+			return ip
+		}
+	}
+	for _, ip := range addresses {
+		if ip != "" {
+			return ip
+		}
+	}
+	return ""
+}
+
+// serviceEnvironmentVariables tells the job where the services are,
+// e.g. for service mysql with ports [3306]:
+//   PISTAGE_SERVICE_MYSQL_HOST=10.0.0.2
+//   PISTAGE_SERVICE_MYSQL_PORT=3306
+//   PISTAGE_SERVICE_MYSQL_ADDRESS=10.0.0.2:3306
+// PORT and ADDRESS use the first port.
+func (e *EruJobExecutor) serviceEnvironmentVariables() map[string]string {
+	envs := map[string]string{}
+	for _, service := range e.services {
+		prefix := "PISTAGE_SERVICE_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(service.name))
+		envs[prefix+"_HOST"] = service.host
+		if len(service.ports) > 0 {
+			port := strconv.Itoa(service.ports[0])
+			envs[prefix+"_PORT"] = port
+			envs[prefix+"_ADDRESS"] = service.host + ":" + port
+		}
+	}
+	return envs
+}
+
+// serviceExtraHosts makes services resolvable by their names within the job.
+func (e *EruJobExecutor) serviceExtraHosts() []string {
+	var hosts []string
+	for _, service := range e.services {
+		hosts = append(hosts, fmt.Sprintf("%s:%s", service.name, service.host))
+	}
+	return hosts
+}
@@ -3,6 +3,8 @@ package executors
 import (
 	"context"
 	"io"
+	"sort"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -65,3 +67,85 @@ func RegisterExecutorProvider(ep ExecutorProvider) {
 func GetExecutorProvider(name string) ExecutorProvider {
 	return executorProviders[name]
 }
+
+// ExecutorNames returns the sorted names of executor providers registered.
+func ExecutorNames() []string {
+	names := make([]string, 0, len(executorProviders))
+	for name := range executorProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PostHook is the post command of a KhoriumStep,
+// executed with the same environment and working dir as the main command.
+type PostHook struct {
+	Step        string
+	Command     string
+	Environment map[string]string
+	Files       map[string][]byte
+	WorkingDir  string
+}
+
+// PostHooks holds the post commands of KhoriumSteps executed in a job,
+// they're queued when KhoriumSteps run, and run in reverse order during Cleanup,
+// even if the job fails.
+// KhoriumSteps of a job may share the same working dir, files of a KhoriumStep
+// may be removed or overwritten when its post runs, so they're kept with the hook
+// and copied to the working dir again before post.
+// The zero value is ready to use.
+type PostHooks struct {
+	mutex sync.Mutex
+	hooks []*PostHook
+}
+
+// Queue queues the post command of ks used by step, env and workingDir are
+// what its main command is executed with. Returns false if ks has no post.
+func (p *PostHooks) Queue(step string, ks *common.KhoriumStep, env map[string]string, workingDir string) bool {
+	if ks.Run.Post == "" {
+		return false
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.hooks = append(p.hooks, &PostHook{
+		Step:        step,
+		Command:     ks.Run.Post,
+		Environment: env,
+		Files:       ks.Files,
+		WorkingDir:  workingDir,
+	})
+	return true
+}
+
+// Run takes all the queued hooks and runs them in reverse order,
+// copyFiles copies the files of hook to its working dir, then run executes its command.
+// copyFiles can be nil if the working dir is never shared.
+// All hooks are run even if some of them fail, the first error is returned.
+func (p *PostHooks) Run(ctx context.Context, copyFiles func(ctx context.Context, workingDir string, files map[string][]byte) error, run func(context.Context, *PostHook) error) error {
+	p.mutex.Lock()
+	hooks := p.hooks
+	p.hooks = nil
+	p.mutex.Unlock()
+
+	var err error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		var herr error
+		if copyFiles != nil {
+			herr = copyFiles(ctx, hook.WorkingDir, hook.Files)
+		}
+		if herr == nil {
+			herr = run(ctx, hook)
+		}
+		if herr != nil {
+			logrus.WithField("step", hook.Step).WithError(herr).Error("[PostHooks] error when executing post")
+			if err == nil {
+				err = herr
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package executors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func TestPostHooks(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	khoriumStep := func(post string) *common.KhoriumStep {
+		return &common.KhoriumStep{
+			Run:   &common.KhoriumStepRun{Main: "main", Post: post},
+			Files: map[string][]byte{"post.sh": []byte(post)},
+		}
+	}
+
+	posts := &PostHooks{}
+	assert.True(posts.Queue("first", khoriumStep("clean first"), map[string]string{"A": "1"}, "/ks"))
+	assert.False(posts.Queue("nothing", khoriumStep(""), nil, "/ks"))
+	assert.True(posts.Queue("second", khoriumStep("clean second"), nil, "/ks"))
+
+	// files are copied before each post, all posts run even if some fail.
+	var ran []string
+	err := posts.Run(ctx, func(ctx context.Context, workingDir string, files map[string][]byte) error {
+		ran = append(ran, "copy "+workingDir+" "+string(files["post.sh"]))
+		return nil
+	}, func(ctx context.Context, hook *PostHook) error {
+		ran = append(ran, hook.Command)
+		if hook.Step == "second" {
+			return common.ErrExecutionError
+		}
+		assert.Equal(map[string]string{"A": "1"}, hook.Environment)
+		return nil
+	})
+	assert.ErrorIs(err, common.ErrExecutionError)
+	assert.Equal([]string{"copy /ks clean second", "clean second", "copy /ks clean first", "clean first"}, ran)
+
+	// posts run only once.
+	assert.NoError(posts.Run(ctx, nil, func(ctx context.Context, hook *PostHook) error {
+		assert.Fail("run again")
+		return nil
+	}))
+}
@@ -0,0 +1,49 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/executors"
+)
+
+// restoreCache restores the caches of job into the pod.
+func (k *KubernetesJobExecutor) restoreCache(ctx context.Context) error {
+	return k.caches.Restore(ctx, k.job, k.jobEnvironment, k.store, k.output, k.cacheTransport())
+}
+
+// saveCache saves the missed caches of job.
+func (k *KubernetesJobExecutor) saveCache(ctx context.Context) {
+	k.caches.Save(ctx, k.job, k.store, k.output, k.cacheTransport())
+}
+
+// cacheTransport runs commands in the working dir of the pod,
+// tarballs are streamed through stdin and stdout of cat over exec.
+func (k *KubernetesJobExecutor) cacheTransport() *executors.CacheTransport {
+	return &executors.CacheTransport{
+		Run: func(ctx context.Context, cmd string, output io.Writer) error {
+			return k.executeShell(ctx, cmd, nil, k.workingDir, output)
+		},
+		Upload: func(ctx context.Context, name string, content io.Reader) error {
+			stderr := &bytes.Buffer{}
+			cmd := []string{"/bin/sh", "-c", fmt.Sprintf("cat > '%s'", filepath.Join(k.workingDir, name))}
+			if err := k.exec(ctx, k.namespace, k.podName, cmd, content, io.Discard, stderr); err != nil {
+				return errors.WithMessagef(err, "stderr: %s", stderr.String())
+			}
+			return nil
+		},
+		Download: func(ctx context.Context, name string, w io.Writer) error {
+			stderr := &bytes.Buffer{}
+			cmd := []string{"cat", filepath.Join(k.workingDir, name)}
+			if err := k.exec(ctx, k.namespace, k.podName, cmd, nil, w, stderr); err != nil {
+				return errors.WithMessagef(err, "stderr: %s", stderr.String())
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,453 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/helpers/command"
+	"github.com/projecteru2/pistage/helpers/variable"
+	"github.com/projecteru2/pistage/store"
+)
+
+const (
+	// working dir for KhoriumStep.
+	khoriumStepWorkingDir = "/_khoriumstep/"
+
+	labelManagedBy = "app.kubernetes.io/managed-by"
+	labelJobName   = "pistage.projecteru2.io/job"
+)
+
+var (
+	// ErrorPodNotRunning is returned when the job pod terminates before running.
+	ErrorPodNotRunning = errors.New("Pod not running")
+
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+type KubernetesJobExecutor struct {
+	client kubeclient.Interface
+	exec   podExecFunc
+	store  store.Store
+	config *common.Config
+
+	job     *common.Job
+	pistage *common.Pistage
+
+	output         io.Writer
+	namespace      string
+	podName        string
+	jobEnvironment map[string]string
+	workingDir     string
+
+	// caches are the caches of job, restored in Prepare.
+	caches executors.JobCaches
+
+	// posts are run in Cleanup, see executors.PostHooks.
+	posts executors.PostHooks
+
+	// outputs holds the outputs of KhoriumSteps executed.
+	outputs executors.StepOutputs
+}
+
+// NewKubernetesJobExecutor creates a Kubernetes executor for this job.
+// Since job needs to know its context, pistage is assigned too.
+func NewKubernetesJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, client kubeclient.Interface, exec podExecFunc, store store.Store, config *common.Config) (*KubernetesJobExecutor, error) {
+	return &KubernetesJobExecutor{
+		client:         client,
+		exec:           exec,
+		store:          store,
+		config:         config,
+		job:            job,
+		pistage:        pistage,
+		output:         output,
+		namespace:      config.Kubernetes.Namespace,
+		jobEnvironment: pistage.Environment,
+		workingDir:     config.Kubernetes.DefaultWorkingDir,
+	}, nil
+}
+
+// Prepare does all the preparations before actually running a job
+func (k *KubernetesJobExecutor) Prepare(ctx context.Context) error {
+	preparations := []func(context.Context) error{
+		k.prepareJobRuntime,
+		k.prepareFileContext,
+		k.restoreCache,
+	}
+	for _, f := range preparations {
+		if err := f(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareJobRuntime creates a pod running an empty workload command,
+// and waits until the pod is running.
+func (k *KubernetesJobExecutor) prepareJobRuntime(ctx context.Context) error {
+	pod, err := k.buildPod()
+	if err != nil {
+		return err
+	}
+
+	created, err := k.client.CoreV1().Pods(k.namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	k.podName = created.Name
+
+	return k.waitForPodRunning(ctx)
+}
+
+// waitForPodRunning polls the pod until it's running,
+// or it terminates, or PodReadyTimeoutSecs exceeds.
+func (k *KubernetesJobExecutor) waitForPodRunning(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(k.config.Kubernetes.PodReadyTimeoutSecs)*time.Second)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		pod, err := k.client.CoreV1().Pods(k.namespace).Get(ctx, k.podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			return nil
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return errors.WithMessagef(ErrorPodNotRunning, "pod: %s, phase: %s", k.podName, pod.Status.Phase)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.WithMessagef(ctx.Err(), "pod: %s", k.podName)
+		case <-ticker.C:
+		}
+	}
+}
+
+// defaultEnvironmentVariables sets some useful information into environment variables.
+// This will be set to the whole running context within the pod.
+func (k *KubernetesJobExecutor) defaultEnvironmentVariables() map[string]string {
+	return map[string]string{
+		"PISTAGE_WORKING_DIR":         k.workingDir,
+		"PISTAGE_JOB_NAME":            k.job.Name,
+		"PISTAGE_DEPENDS_ON":          strings.Join(k.job.DependsOn, ","),
+		"PISTAGE_WORKFLOW_IDENTIFIER": k.pistage.WorkflowIdentifier,
+		"PISTAGE_WORKFLOW_TYPE":       k.pistage.WorkflowType,
+	}
+}
+
+// buildPod builds the job pod.
+// The pod is never restarted, it just runs an empty workload command till
+// timeout, steps are executed through exec.
+func (k *KubernetesJobExecutor) buildPod() (*corev1.Pod, error) {
+	jobImage := k.job.Image
+	if jobImage == "" {
+		jobImage = k.config.Kubernetes.DefaultJobImage
+	}
+
+	resources, err := buildResourceRequirements(k.config.Kubernetes.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	var envs []corev1.EnvVar
+	for _, env := range command.ToEnvironmentList(command.MergeVariables(command.PreparePistageEnvs(k.jobEnvironment), k.defaultEnvironmentVariables())) {
+		kv := strings.SplitN(env, "=", 2)
+		envs = append(envs, corev1.EnvVar{Name: kv[0], Value: kv[1]})
+	}
+
+	name := sanitizeName(k.job.Name)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("pistage-%s-", name),
+			Namespace:    k.namespace,
+			Labels: map[string]string{
+				labelManagedBy: "pistage",
+				labelJobName:   name,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: k.config.Kubernetes.ServiceAccount,
+			NodeSelector:       k.config.Kubernetes.NodeSelector,
+			Containers: []corev1.Container{
+				{
+					Name:       jobContainerName,
+					Image:      jobImage,
+					Command:    command.EmptyWorkloadCommand(k.job.Timeout),
+					WorkingDir: k.workingDir,
+					Env:        envs,
+					Resources:  resources,
+				},
+			},
+		},
+	}, nil
+}
+
+func (k *KubernetesJobExecutor) prepareFileContext(ctx context.Context) error {
+	dependentJobs := k.pistage.GetJobs(k.job.DependsOn)
+	for _, job := range dependentJobs {
+		fc := job.GetFileCollector()
+		if fc == nil {
+			continue
+		}
+		if err := fc.CopyTo(ctx, k.podName, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute will execute all steps within this job one by one,
+// caches missed are saved after all steps succeeded.
+func (k *KubernetesJobExecutor) Execute(ctx context.Context) error {
+	if err := k.executeSteps(ctx, k.job.Steps); err != nil {
+		return err
+	}
+	k.saveCache(ctx)
+	return nil
+}
+
+func (k *KubernetesJobExecutor) executeSteps(ctx context.Context, steps []*common.Step) error {
+	// composite KhoriumSteps are expanded first,
+	// so only plain steps and plain KhoriumSteps are left.
+	steps, err := executors.ExpandSteps(ctx, k.store, steps, k.pistage.Vars)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		var err error
+		switch step.Uses {
+		case "":
+			err = k.executeStep(ctx, step)
+		default:
+			err = k.executeKhoriumStep(ctx, step)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeStep executes a step.
+// It prepares the arguments and environments to the command.
+// Then execute the command, retrieve the output, the execution will stop if any error occurs.
+// It then retries to execute the OnError commands, also with the arguments and environments.
+func (k *KubernetesJobExecutor) executeStep(ctx context.Context, step *common.Step) error {
+	var (
+		err  error
+		vars = k.pistage.Vars
+	)
+
+	environment := command.MergeVariables(command.MergeVariables(k.jobEnvironment, k.outputs.Environment()), step.Environment)
+
+	defer func() {
+		if !errors.Is(err, common.ErrExecutionError) {
+			return
+		}
+		if err := k.executeCommands(ctx, step.OnError, step.With, environment, vars); err != nil {
+			logrus.WithField("step", step.Name).WithError(err).Errorf("[KubernetesJobExecutor] error when executing on_error")
+		}
+	}()
+
+	err = k.executeCommands(ctx, step.Run, step.With, environment, vars)
+	return err
+}
+
+// executeKhoriumStep executes a KhoriumStep defined by step.Uses.
+func (k *KubernetesJobExecutor) executeKhoriumStep(ctx context.Context, step *common.Step) error {
+	ks, err := k.store.GetRegisteredKhoriumStep(ctx, step.Uses)
+	if err != nil {
+		return err
+	}
+
+	arguments, err := variable.RenderArguments(step.With, command.MergeVariables(k.outputs.Environment(), step.Environment), k.pistage.Vars)
+	if err != nil {
+		return err
+	}
+
+	ksEnv, err := ks.BuildEnvironmentVariables(arguments)
+	if err != nil {
+		return err
+	}
+	envs := command.MergeVariables(command.MergeVariables(k.outputs.Environment(), step.Environment), ksEnv)
+
+	if err := k.copyKhoriumStepFiles(ctx, khoriumStepWorkingDir, ks.Files); err != nil {
+		return err
+	}
+
+	outputFile, err := executors.OutputFile(khoriumStepWorkingDir, step)
+	if err != nil {
+		return err
+	}
+	if len(ks.Outputs) != 0 {
+		envs[executors.KhoriumStepOutputEnv] = outputFile
+	}
+
+	k.posts.Queue(step.Name, ks, envs, khoriumStepWorkingDir)
+
+	if err := k.executeShell(ctx, ks.Run.Main, envs, khoriumStepWorkingDir, k.output); err != nil {
+		return err
+	}
+	return k.outputs.Collect(ks, step.Name, outputFile, func(cmd string, output io.Writer) error {
+		return k.executeShell(ctx, cmd, envs, khoriumStepWorkingDir, output)
+	})
+}
+
+// executeCommands executes cmd with given arguments, environments and variables.
+// use args, envs, and reserved vars to build the cmd.
+func (k *KubernetesJobExecutor) executeCommands(ctx context.Context, cmds []string, args, env, vars map[string]string) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	var commands []string
+	for _, cmd := range cmds {
+		c, err := command.RenderCommand(cmd, args, env, vars)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, c)
+	}
+
+	shell, err := command.RenderShell(commands)
+	if err != nil {
+		return err
+	}
+
+	return k.executeShell(ctx, shell, env, k.workingDir, k.output)
+}
+
+// executeShell executes shell in workdir of the pod, writes the output to output.
+// exec subresource doesn't accept environment variables,
+// so they are exported before the shell.
+func (k *KubernetesJobExecutor) executeShell(ctx context.Context, shell string, env map[string]string, workdir string, output io.Writer) error {
+	commandShards := []string{
+		command.RenderEnvironmentForSSH(env),
+		fmt.Sprintf("cd %s", workdir),
+		shell,
+	}
+	cmd := []string{"/bin/sh", "-c", strings.Join(commandShards, "\n")}
+	return k.exec(ctx, k.namespace, k.podName, cmd, nil, output, output)
+}
+
+// copyKhoriumStepFiles copies files of a KhoriumStep into dir of the pod.
+func (k *KubernetesJobExecutor) copyKhoriumStepFiles(ctx context.Context, dir string, files map[string][]byte) error {
+	fc := NewKubernetesFileCollector(k.exec, k.namespace, dir)
+	fc.SetFiles(files)
+	return fc.CopyTo(ctx, k.podName, nil)
+}
+
+// executePost executes the post command of a KhoriumStep.
+func (k *KubernetesJobExecutor) executePost(ctx context.Context, hook *executors.PostHook) error {
+	return k.executeShell(ctx, hook.Command, hook.Environment, hook.WorkingDir, k.output)
+}
+
+// beforeCleanup collects files if any
+func (k *KubernetesJobExecutor) beforeCleanup(ctx context.Context) error {
+	if len(k.job.Files) == 0 || k.podName == "" {
+		return nil
+	}
+
+	fc := NewKubernetesFileCollector(k.exec, k.namespace, k.workingDir)
+	if err := fc.Collect(ctx, k.podName, k.job.Files); err != nil {
+		return err
+	}
+
+	k.job.SetFileCollector(fc)
+	return nil
+}
+
+// cleanup deletes the job pod immediately.
+func (k *KubernetesJobExecutor) cleanup(ctx context.Context) error {
+	if k.podName == "" {
+		return nil
+	}
+
+	gracePeriod := int64(0)
+	return k.client.CoreV1().Pods(k.namespace).Delete(ctx, k.podName, metav1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriod,
+	})
+}
+
+// Cleanup does all the cleanup work
+func (k *KubernetesJobExecutor) Cleanup(ctx context.Context) error {
+	// post commands are executed even if the job fails,
+	// every step of cleanup is executed, the first error is returned.
+	err := k.posts.Run(ctx, k.copyKhoriumStepFiles, k.executePost)
+
+	cleanups := []func(context.Context) error{
+		k.beforeCleanup,
+		k.cleanup,
+	}
+	for _, f := range cleanups {
+		if cerr := f(ctx); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Rollback is a function can execute rollback_steps commands which are defined in yaml file
+func (k *KubernetesJobExecutor) Rollback(ctx context.Context) error {
+	return k.executeSteps(ctx, k.job.RollbackSteps)
+}
+
+// buildResourceRequirements parses the quantities in config.
+func buildResourceRequirements(config common.KubernetesResourceConfig) (corev1.ResourceRequirements, error) {
+	parse := func(quantities map[string]string) (corev1.ResourceList, error) {
+		if len(quantities) == 0 {
+			return nil, nil
+		}
+		list := corev1.ResourceList{}
+		for name, value := range quantities {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "resource: %s", name)
+			}
+			list[corev1.ResourceName(name)] = quantity
+		}
+		return list, nil
+	}
+
+	requests, err := parse(config.Requests)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	limits, err := parse(config.Limits)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	return corev1.ResourceRequirements{Requests: requests, Limits: limits}, nil
+}
+
+// sanitizeName makes name a valid part of DNS-1123 label.
+func sanitizeName(name string) string {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-")
+	}
+	if name == "" {
+		name = "job"
+	}
+	return name
+}
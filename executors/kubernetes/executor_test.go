@@ -0,0 +1,207 @@
+package kubernetes
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// fakeExec records all commands, the result of each command is decided by handler.
+type fakeExec struct {
+	commands [][]string
+	handler  func(cmd []string, stdin io.Reader, stdout io.Writer) error
+}
+
+func (f *fakeExec) exec(ctx context.Context, namespace, pod string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	f.commands = append(f.commands, cmd)
+	if f.handler == nil {
+		return nil
+	}
+	return f.handler(cmd, stdin, stdout)
+}
+
+// newFakeClientset creates a clientset, pods created are named from GenerateName and running immediately.
+func newFakeClientset() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+		pod.Name = pod.GenerateName + "test"
+		pod.Status.Phase = corev1.PodRunning
+		return false, nil, nil
+	})
+	return client
+}
+
+func testingConfig() *common.Config {
+	return &common.Config{
+		Kubernetes: common.KubernetesConfig{
+			Namespace:           "ci",
+			ServiceAccount:      "pistage",
+			DefaultJobImage:     "alpine:latest",
+			DefaultWorkingDir:   "/pistage",
+			PodReadyTimeoutSecs: 5,
+			NodeSelector:        map[string]string{"pool": "ci"},
+			Resources: common.KubernetesResourceConfig{
+				Requests: map[string]string{"cpu": "500m"},
+				Limits:   map[string]string{"memory": "1Gi"},
+			},
+		},
+	}
+}
+
+func testingPistage() (*common.Job, *common.Pistage) {
+	job := &common.Job{
+		Name:    "Build_Binary",
+		Timeout: 120,
+		Steps: []*common.Step{
+			{Name: "build", Run: []string{"make {{ target }}"}, With: map[string]string{"target": "binary"}, OnError: []string{"echo failed"}},
+		},
+	}
+	return job, &common.Pistage{
+		WorkflowIdentifier: "identifier",
+		Jobs:               map[string]*common.Job{job.Name: job},
+		Environment:        map[string]string{"GOOS": "linux"},
+	}
+}
+
+func TestKubernetesJobExecutorPrepareAndCleanup(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	client := newFakeClientset()
+	fe := &fakeExec{}
+	job, pistage := testingPistage()
+
+	executor, err := NewKubernetesJobExecutor(job, pistage, ioutil.Discard, client, fe.exec, nil, testingConfig())
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.Equal("pistage-build-binary-test", executor.podName)
+
+	pod, err := client.CoreV1().Pods("ci").Get(ctx, executor.podName, metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal("pistage", pod.Spec.ServiceAccountName)
+	assert.Equal(corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.Equal(map[string]string{"pool": "ci"}, pod.Spec.NodeSelector)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal("alpine:latest", container.Image)
+	assert.Equal("/pistage", container.WorkingDir)
+	assert.Equal([]string{"/bin/sh", "-c", "sleep 120"}, container.Command)
+	assert.Equal("500m", container.Resources.Requests.Cpu().String())
+	assert.Equal("1Gi", container.Resources.Limits.Memory().String())
+	assert.Contains(container.Env, corev1.EnvVar{Name: "PISTAGE_ENV_VAR_GOOS", Value: "linux"})
+	assert.Contains(container.Env, corev1.EnvVar{Name: "PISTAGE_JOB_NAME", Value: "Build_Binary"})
+
+	assert.NoError(executor.Cleanup(ctx))
+	_, err = client.CoreV1().Pods("ci").Get(ctx, executor.podName, metav1.GetOptions{})
+	assert.Error(err)
+
+	// the pod is deleted even if files fail to be collected.
+	job.Files = []string{"bin/pistage"}
+	fe.handler = func(cmd []string, stdin io.Reader, stdout io.Writer) error {
+		return common.ErrExecutionError
+	}
+	executor, err = NewKubernetesJobExecutor(job, pistage, ioutil.Discard, client, fe.exec, nil, testingConfig())
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.ErrorIs(executor.Cleanup(ctx), common.ErrExecutionError)
+	_, err = client.CoreV1().Pods("ci").Get(ctx, executor.podName, metav1.GetOptions{})
+	assert.Error(err)
+}
+
+func TestKubernetesJobExecutorExecute(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	fe := &fakeExec{}
+	job, pistage := testingPistage()
+	output := &bytes.Buffer{}
+
+	executor, err := NewKubernetesJobExecutor(job, pistage, output, newFakeClientset(), fe.exec, nil, testingConfig())
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+
+	assert.Len(fe.commands, 1)
+	shell := fe.commands[0][2]
+	assert.Contains(shell, "export GOOS=linux")
+	assert.Contains(shell, "cd /pistage")
+	assert.Contains(shell, "make binary")
+
+	// a failed step triggers on_error.
+	fe.commands = nil
+	fe.handler = func(cmd []string, stdin io.Reader, stdout io.Writer) error {
+		if strings.Contains(cmd[2], "make") {
+			return common.ErrExecutionError
+		}
+		return nil
+	}
+	assert.ErrorIs(executor.Execute(ctx), common.ErrExecutionError)
+	assert.Len(fe.commands, 2)
+	assert.Contains(fe.commands[1][2], "echo failed")
+}
+
+func TestKubernetesFileCollector(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var sent []byte
+	fe := &fakeExec{
+		handler: func(cmd []string, stdin io.Reader, stdout io.Writer) error {
+			if stdin != nil {
+				content, err := ioutil.ReadAll(stdin)
+				sent = content
+				return err
+			}
+			// echo back what was sent as the tarball.
+			_, err := stdout.Write(sent)
+			return err
+		},
+	}
+
+	fc := NewKubernetesFileCollector(fe.exec, "ci", "/pistage")
+	fc.SetFiles(map[string][]byte{"a/file1": []byte("content1"), "file2": []byte("content2")})
+	assert.NoError(fc.CopyTo(ctx, "pod", nil))
+	assert.Equal([]string{"/bin/sh", "-c", "mkdir -p /pistage && tar -xf - -C /pistage"}, fe.commands[0])
+
+	names := map[string]bool{}
+	tr := tar.NewReader(bytes.NewReader(sent))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(err)
+		names[header.Name] = true
+	}
+	assert.Equal(map[string]bool{"a/file1": true, "file2": true}, names)
+
+	collector := NewKubernetesFileCollector(fe.exec, "ci", "/pistage")
+	assert.NoError(collector.Collect(ctx, "pod", []string{"a/file1", "file2", "../outside"}))
+	assert.Equal([]string{"tar", "-cf", "-", "-C", "/pistage", "a/file1", "file2"}, fe.commands[1])
+	content, ok := collector.getFile("a/file1")
+	assert.True(ok)
+	assert.Equal([]byte("content1"), content)
+	assert.ElementsMatch([]string{"a/file1", "file2"}, collector.Files())
+}
+
+func TestSanitizeName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("build-binary", sanitizeName("Build_Binary"))
+	assert.Equal("job", sanitizeName("___"))
+	assert.Equal(40, len(sanitizeName(strings.Repeat("a", 100))))
+}
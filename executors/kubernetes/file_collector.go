@@ -0,0 +1,174 @@
+package kubernetes
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// KubernetesFileCollector collects or sends files from or to the job pod.
+// Files are transferred as a tarball through tar over exec,
+// so tar must exist in the job image.
+// Note: the paths of files are relative to root.
+type KubernetesFileCollector struct {
+	mutex     sync.Mutex
+	exec      podExecFunc
+	namespace string
+
+	// files kept in collector.
+	// The names are **RELATIVE** to root.
+	files map[string][]byte
+
+	// root is the root dir of this file collector.
+	// This should be **ABSOLUTE**.
+	root string
+}
+
+// NewKubernetesFileCollector creates a KubernetesFileCollector,
+// note that root must be an absolute path.
+func NewKubernetesFileCollector(exec podExecFunc, namespace, root string) *KubernetesFileCollector {
+	return &KubernetesFileCollector{
+		exec:      exec,
+		namespace: namespace,
+		files:     map[string][]byte{},
+		root:      root,
+	}
+}
+
+func (k *KubernetesFileCollector) SetFiles(files map[string][]byte) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.files = files
+}
+
+// Collect collects files from the pod.
+// For a KubernetesFileCollector, identifier represents the pod name.
+func (k *KubernetesFileCollector) Collect(ctx context.Context, identifier string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	var names []string
+	for _, file := range files {
+		path := filepath.Join(k.root, file)
+		// We don't allow files out of k.root to be collected.
+		if !strings.HasPrefix(path, k.root) {
+			continue
+		}
+		names = append(names, strings.TrimPrefix(strings.TrimPrefix(path, k.root), "/"))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := append([]string{"tar", "-cf", "-", "-C", k.root}, names...)
+	if err := k.exec(ctx, k.namespace, identifier, cmd, nil, stdout, stderr); err != nil {
+		return errors.WithMessagef(err, "stderr: %s", stderr.String())
+	}
+
+	tr := tar.NewReader(stdout)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		buffer := &bytes.Buffer{}
+		if _, err := io.Copy(buffer, tr); err != nil {
+			return err
+		}
+		k.files[filepath.Clean(header.Name)] = buffer.Bytes()
+	}
+	return nil
+}
+
+// CopyTo copies files to the pod.
+// For a KubernetesFileCollector, identifier represents the pod name.
+func (k *KubernetesFileCollector) CopyTo(ctx context.Context, identifier string, files []string) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if len(files) == 0 {
+		for name := range k.files {
+			files = append(files, name)
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	tw := tar.NewWriter(buffer)
+	count := 0
+	for _, filename := range files {
+		content, ok := k.files[filename]
+		if !ok {
+			continue
+		}
+		// We don't allow to copy files out of root.
+		if !strings.HasPrefix(filepath.Join(k.root, filename), k.root) {
+			continue
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name: filename,
+			Mode: 0644,
+			Size: int64(len(content)),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd := []string{"/bin/sh", "-c", fmt.Sprintf("mkdir -p %s && tar -xf - -C %s", k.root, k.root)}
+	if err := k.exec(ctx, k.namespace, identifier, cmd, buffer, io.Discard, stderr); err != nil {
+		return errors.WithMessagef(err, "stderr: %s", stderr.String())
+	}
+	return nil
+}
+
+// Files returns all file names including path this collector holds.
+func (k *KubernetesFileCollector) Files() []string {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	var files []string
+	for file := range k.files {
+		files = append(files, file)
+	}
+	return files
+}
+
+// getFile returns the content of the file this collector holds.
+func (k *KubernetesFileCollector) getFile(name string) ([]byte, bool) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	content, ok := k.files[name]
+	return content, ok
+}
@@ -0,0 +1,95 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/store"
+)
+
+// jobContainerName is the name of the container in job pod.
+const jobContainerName = "job"
+
+// podExecFunc executes cmd in the job container of pod through the exec subresource.
+// stdin can be nil if nothing needs to be sent.
+// A non-zero exit code is returned as common.ErrExecutionError.
+type podExecFunc func(ctx context.Context, namespace, pod string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+type KubernetesJobExecutorProvider struct {
+	config *common.Config
+	store  store.Store
+	client kubeclient.Interface
+	exec   podExecFunc
+}
+
+func NewKubernetesJobExecutorProvider(config *common.Config, store store.Store) (*KubernetesJobExecutorProvider, error) {
+	// empty kubeconfig falls back to in-cluster config.
+	restConfig, err := clientcmd.BuildConfigFromFlags("", config.Kubernetes.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kubeclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KubernetesJobExecutorProvider{
+		config: config,
+		store:  store,
+		client: client,
+		exec:   newPodExecFunc(client, restConfig),
+	}, nil
+}
+
+func (kp *KubernetesJobExecutorProvider) GetName() string {
+	return "kubernetes"
+}
+
+func (kp *KubernetesJobExecutorProvider) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
+	return NewKubernetesJobExecutor(job, pistage, output, kp.client, kp.exec, kp.store, kp.config)
+}
+
+func newPodExecFunc(client kubeclient.Interface, restConfig *rest.Config) podExecFunc {
+	return func(ctx context.Context, namespace, pod string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+		req := client.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(pod).
+			SubResource("exec").
+			VersionedParams(&corev1.PodExecOptions{
+				Container: jobContainerName,
+				Command:   cmd,
+				Stdin:     stdin != nil,
+				Stdout:    true,
+				Stderr:    true,
+			}, scheme.ParameterCodec)
+
+		executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+		if err != nil {
+			return err
+		}
+
+		err = executor.Stream(remotecommand.StreamOptions{
+			Stdin:  stdin,
+			Stdout: stdout,
+			Stderr: stderr,
+		})
+		var exitError utilexec.ExitError
+		if errors.As(err, &exitError) {
+			return errors.WithMessagef(common.ErrExecutionError, "exitcode: %d", exitError.ExitStatus())
+		}
+		return err
+	}
+}
@@ -0,0 +1,94 @@
+package executors
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/helpers"
+)
+
+// KhoriumStepOutputEnv is the environment variable of the file KhoriumStep writes outputs into.
+const KhoriumStepOutputEnv = "KHORIUMSTEP_OUTPUT"
+
+var outputNameRe = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// OutputFile returns the file in dir the KhoriumStep used by step writes outputs into,
+// it's named by the digest of step name, so outputs of different steps never mix up.
+func OutputFile(dir string, step *common.Step) (string, error) {
+	digest, err := helpers.Sha1HexDigest(step.Name)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, ".outputs-"+digest), nil
+}
+
+// ParseOutputs parses the name=value lines written by ks,
+// only outputs declared by ks are kept, the later line wins.
+func ParseOutputs(ks *common.KhoriumStep, content []byte) map[string]string {
+	outputs := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		if _, ok := ks.Outputs[name]; ok {
+			outputs[name] = parts[1]
+		}
+	}
+	return outputs
+}
+
+// StepOutputs holds the outputs of KhoriumSteps executed in a job,
+// later steps see them as environment variables KHORIUMSTEP_OUTPUT_<STEP>_<NAME>,
+// with names upper cased and characters other than letters and digits replaced with _.
+// Outputs declared but not written are empty.
+type StepOutputs struct {
+	env map[string]string
+}
+
+// Collect reads the outputs ks wrote into file after step succeeded,
+// run executes the command within the workload and writes its output to the writer.
+// Nothing is done if ks declares no outputs.
+func (o *StepOutputs) Collect(ks *common.KhoriumStep, step, file string, run func(cmd string, output io.Writer) error) error {
+	if len(ks.Outputs) == 0 {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := run(fmt.Sprintf("cat '%s' 2>/dev/null; rm -f '%s'", file, file), buf); err != nil {
+		return err
+	}
+	outputs := ParseOutputs(ks, buf.Bytes())
+
+	if o.env == nil {
+		o.env = map[string]string{}
+	}
+	for name := range ks.Outputs {
+		o.env[outputEnvironmentName(step, name)] = outputs[name]
+	}
+	return nil
+}
+
+// Environment returns the outputs collected as environment variables.
+func (o *StepOutputs) Environment() map[string]string {
+	env := map[string]string{}
+	for k, v := range o.env {
+		env[k] = v
+	}
+	return env
+}
+
+func outputEnvironmentName(step, name string) string {
+	normalize := func(s string) string {
+		return strings.Trim(outputNameRe.ReplaceAllString(strings.ToUpper(s), "_"), "_")
+	}
+	return fmt.Sprintf("KHORIUMSTEP_OUTPUT_%s_%s", normalize(step), normalize(name))
+}
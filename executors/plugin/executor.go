@@ -0,0 +1,213 @@
+package plugin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	pluginpb "github.com/projecteru2/pistage/executors/plugin/proto"
+	"github.com/projecteru2/pistage/store"
+)
+
+// outputStream is the stream of output of a phase.
+type outputStream interface {
+	Recv() (*pluginpb.Output, error)
+}
+
+// PluginJobExecutor executes the job in plugin,
+// it just forwards all phases to plugin, and writes the output back.
+type PluginJobExecutor struct {
+	client pluginpb.ExecutorPluginClient
+	store  store.Store
+
+	job     *common.Job
+	pistage *common.Pistage
+
+	output     io.Writer
+	executorID string
+	prepared   bool
+}
+
+// NewPluginJobExecutor creates a plugin executor for this job.
+// Since job needs to know its context, pistage is assigned too.
+func NewPluginJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, client pluginpb.ExecutorPluginClient, store store.Store) (*PluginJobExecutor, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &PluginJobExecutor{
+		client:     client,
+		store:      store,
+		job:        job,
+		pistage:    pistage,
+		output:     output,
+		executorID: hex.EncodeToString(id),
+	}, nil
+}
+
+// Prepare sends the job to plugin with all KhoriumSteps it uses,
+// then copies files from dependent jobs.
+// Composite KhoriumSteps are expanded before sending, so plugins never see them.
+func (p *PluginJobExecutor) Prepare(ctx context.Context) error {
+	expanded, err := p.expandJob(ctx)
+	if err != nil {
+		return err
+	}
+	job, err := json.Marshal(expanded)
+	if err != nil {
+		return err
+	}
+	pistage, err := json.Marshal(p.pistage)
+	if err != nil {
+		return err
+	}
+	khoriumSteps, err := p.resolveKhoriumSteps(ctx, expanded)
+	if err != nil {
+		return err
+	}
+
+	stream, err := p.client.Prepare(ctx, &pluginpb.PrepareRequest{
+		ExecutorID:   p.executorID,
+		Job:          job,
+		Pistage:      pistage,
+		KhoriumSteps: khoriumSteps,
+		Vars:         p.pistage.Vars,
+		Bundle:       p.pistage.Bundle,
+	})
+	if err != nil {
+		return err
+	}
+	p.prepared = true
+	if err := p.writeOutput(stream); err != nil {
+		return err
+	}
+
+	dependentJobs := p.pistage.GetJobs(p.job.DependsOn)
+	for _, job := range dependentJobs {
+		fc := job.GetFileCollector()
+		if fc == nil {
+			continue
+		}
+		if err := fc.CopyTo(ctx, p.executorID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandJob returns a copy of job with composite KhoriumSteps expanded.
+func (p *PluginJobExecutor) expandJob(ctx context.Context) (*common.Job, error) {
+	steps, err := executors.ExpandSteps(ctx, p.store, p.job.Steps, p.pistage.Vars)
+	if err != nil {
+		return nil, err
+	}
+	rollbackSteps, err := executors.ExpandSteps(ctx, p.store, p.job.RollbackSteps, p.pistage.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	job := *p.job
+	job.Steps = steps
+	job.RollbackSteps = rollbackSteps
+	return &job, nil
+}
+
+// resolveKhoriumSteps gets all the KhoriumSteps used by steps and rollback steps of job.
+func (p *PluginJobExecutor) resolveKhoriumSteps(ctx context.Context, job *common.Job) (map[string]*pluginpb.KhoriumStep, error) {
+	khoriumSteps := map[string]*pluginpb.KhoriumStep{}
+	steps := append(append([]*common.Step{}, job.Steps...), job.RollbackSteps...)
+	for _, step := range steps {
+		if step.Uses == "" {
+			continue
+		}
+		if _, ok := khoriumSteps[step.Uses]; ok {
+			continue
+		}
+
+		ks, err := p.store.GetRegisteredKhoriumStep(ctx, step.Uses)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := json.Marshal(ks)
+		if err != nil {
+			return nil, err
+		}
+		khoriumSteps[step.Uses] = &pluginpb.KhoriumStep{
+			Spec:  spec,
+			Files: ks.Files,
+		}
+	}
+	return khoriumSteps, nil
+}
+
+// Execute executes all the steps in plugin.
+func (p *PluginJobExecutor) Execute(ctx context.Context) error {
+	stream, err := p.client.Execute(ctx, &pluginpb.PhaseRequest{ExecutorID: p.executorID})
+	if err != nil {
+		return err
+	}
+	return p.writeOutput(stream)
+}
+
+// Cleanup collects files if any, then cleans up in plugin.
+// Nothing to clean up if the job is never sent to plugin.
+func (p *PluginJobExecutor) Cleanup(ctx context.Context) error {
+	if !p.prepared {
+		return nil
+	}
+
+	var err error
+	if len(p.job.Files) > 0 {
+		fc := NewPluginFileCollector(p.client)
+		if err = fc.Collect(ctx, p.executorID, p.job.Files); err == nil {
+			p.job.SetFileCollector(fc)
+		}
+	}
+
+	stream, cerr := p.client.Cleanup(ctx, &pluginpb.PhaseRequest{ExecutorID: p.executorID})
+	if cerr == nil {
+		cerr = p.writeOutput(stream)
+	}
+	if err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Rollback executes the rollback steps in plugin.
+func (p *PluginJobExecutor) Rollback(ctx context.Context) error {
+	stream, err := p.client.Rollback(ctx, &pluginpb.PhaseRequest{ExecutorID: p.executorID})
+	if err != nil {
+		return err
+	}
+	return p.writeOutput(stream)
+}
+
+// writeOutput writes all the output of stream,
+// error with code Aborted is returned as ErrExecutionError.
+func (p *PluginJobExecutor) writeOutput(stream outputStream) error {
+	for {
+		output, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if s, ok := status.FromError(err); ok && s.Code() == codes.Aborted {
+				return errors.WithMessage(common.ErrExecutionError, s.Message())
+			}
+			return err
+		}
+		if _, err := p.output.Write(output.Data); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+
+	pluginpb "github.com/projecteru2/pistage/executors/plugin/proto"
+)
+
+// PluginFileCollector collects or sends files from or to the job executor in plugin.
+// Note: paths of files are relative to the working dir of the job,
+// it's the plugin's duty to resolve them.
+type PluginFileCollector struct {
+	mutex  sync.Mutex
+	client pluginpb.ExecutorPluginClient
+	files  map[string][]byte
+}
+
+func NewPluginFileCollector(client pluginpb.ExecutorPluginClient) *PluginFileCollector {
+	return &PluginFileCollector{
+		client: client,
+		files:  map[string][]byte{},
+	}
+}
+
+func (p *PluginFileCollector) SetFiles(files map[string][]byte) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.files = files
+}
+
+// Collect collects files from the job executor.
+// For a PluginFileCollector, identifier represents the executor id.
+func (p *PluginFileCollector) Collect(ctx context.Context, identifier string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	reply, err := p.client.CollectFiles(ctx, &pluginpb.CollectFilesRequest{
+		ExecutorID: identifier,
+		Files:      files,
+	})
+	if err != nil {
+		return err
+	}
+	for name, content := range reply.Files {
+		p.files[name] = content
+	}
+	return nil
+}
+
+// CopyTo copies files to the job executor.
+// For a PluginFileCollector, identifier represents the executor id.
+func (p *PluginFileCollector) CopyTo(ctx context.Context, identifier string, files []string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	data := map[string][]byte{}
+	if len(files) == 0 {
+		data = p.files
+	} else {
+		for _, name := range files {
+			content, ok := p.files[name]
+			if !ok {
+				continue
+			}
+			data[name] = content
+		}
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	_, err := p.client.CopyFiles(ctx, &pluginpb.CopyFilesRequest{
+		ExecutorID: identifier,
+		Files:      data,
+	})
+	return err
+}
+
+// Files returns all file names including path this collector holds.
+func (p *PluginFileCollector) Files() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var files []string
+	for file := range p.files {
+		files = append(files, file)
+	}
+	return files
+}
@@ -0,0 +1,210 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/store"
+)
+
+const testPluginEnv = "PISTAGE_PLUGIN_TEST"
+
+// TestMain serves the test plugin if launched as a plugin.
+func TestMain(m *testing.M) {
+	if os.Getenv(testPluginEnv) == "1" {
+		if err := Serve(&testProvider{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// testProvider provides executors which don't run anything,
+// commands are written to output, except:
+//   - "exit 1" fails the execution.
+//   - "write <name> <content>" writes a file.
+//   - "cat <name>" writes content of the file to output.
+//   - "var <name>" writes the value of var to output.
+//   - "bundle <name>" writes content of the file in bundle to output.
+type testProvider struct{}
+
+func (p *testProvider) GetName() string {
+	return "test"
+}
+
+func (p *testProvider) GetJobExecutor(job *common.Job, pistage *common.Pistage, khoriumSteps map[string]*common.KhoriumStep, output io.Writer) (executors.JobExecutor, error) {
+	return &testExecutor{job: job, pistage: pistage, khoriumSteps: khoriumSteps, output: output, files: map[string][]byte{}}, nil
+}
+
+type testExecutor struct {
+	mutex        sync.Mutex
+	job          *common.Job
+	pistage      *common.Pistage
+	khoriumSteps map[string]*common.KhoriumStep
+	output       io.Writer
+	files        map[string][]byte
+}
+
+func (e *testExecutor) Prepare(ctx context.Context) error {
+	fmt.Fprintf(e.output, "prepare %s\n", e.job.Name)
+	return nil
+}
+
+func (e *testExecutor) Execute(ctx context.Context) error {
+	return e.executeSteps(e.job.Steps)
+}
+
+func (e *testExecutor) executeSteps(steps []*common.Step) error {
+	for _, step := range steps {
+		if step.Uses != "" {
+			ks := e.khoriumSteps[step.Uses]
+			fmt.Fprintf(e.output, "%s %s\n", ks.Run.Main, ks.Files["main.sh"])
+			continue
+		}
+		for _, cmd := range step.Run {
+			if cmd == "exit 1" {
+				return common.ErrExecutionError
+			}
+			if parts := strings.SplitN(cmd, " ", 3); parts[0] == "write" {
+				e.mutex.Lock()
+				e.files[parts[1]] = []byte(parts[2])
+				e.mutex.Unlock()
+				continue
+			}
+			if parts := strings.SplitN(cmd, " ", 2); parts[0] == "cat" {
+				e.mutex.Lock()
+				fmt.Fprintf(e.output, "%s\n", e.files[parts[1]])
+				e.mutex.Unlock()
+				continue
+			}
+			if parts := strings.SplitN(cmd, " ", 2); parts[0] == "var" {
+				fmt.Fprintln(e.output, e.pistage.Vars[parts[1]])
+				continue
+			}
+			if parts := strings.SplitN(cmd, " ", 2); parts[0] == "bundle" {
+				fmt.Fprintf(e.output, "%s\n", e.pistage.Bundle[parts[1]])
+				continue
+			}
+			fmt.Fprintln(e.output, cmd)
+		}
+	}
+	return nil
+}
+
+func (e *testExecutor) Cleanup(ctx context.Context) error {
+	fmt.Fprintf(e.output, "cleanup %s\n", e.job.Name)
+	return nil
+}
+
+func (e *testExecutor) Rollback(ctx context.Context) error {
+	return e.executeSteps(e.job.RollbackSteps)
+}
+
+func (e *testExecutor) CollectFiles(ctx context.Context, files []string) (map[string][]byte, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	collected := map[string][]byte{}
+	for _, file := range files {
+		if content, ok := e.files[file]; ok {
+			collected[file] = content
+		}
+	}
+	return collected, nil
+}
+
+func (e *testExecutor) CopyFiles(ctx context.Context, files map[string][]byte) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for name, content := range files {
+		e.files[name] = content
+	}
+	return nil
+}
+
+// fakeStore only provides KhoriumSteps.
+type fakeStore struct {
+	store.Store
+}
+
+func (s *fakeStore) GetRegisteredKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	return &common.KhoriumStep{
+		Name:  name,
+		Run:   &common.KhoriumStepRun{Main: "sh main.sh"},
+		Files: map[string][]byte{"main.sh": []byte("echo " + name)},
+	}, nil
+}
+
+func TestPluginJobExecutor(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := NewPluginJobExecutorProvider(ctx, common.PluginConfig{Name: "none"}, nil)
+	assert.ErrorIs(err, ErrorPluginNotConfigured)
+
+	provider, err := NewPluginJobExecutorProvider(ctx, common.PluginConfig{
+		Name:             "test-plugin",
+		Command:          []string{os.Args[0]},
+		Env:              map[string]string{testPluginEnv: "1"},
+		StartTimeoutSecs: 10,
+	}, &fakeStore{})
+	assert.NoError(err)
+	defer provider.Close()
+	assert.Equal("test-plugin", provider.GetName())
+
+	build := &common.Job{
+		Name:  "build",
+		Files: []string{"out.txt"},
+		Steps: []*common.Step{
+			{Name: "compile", Run: []string{"echo compile", "var version", "bundle notes.txt", "write out.txt binary"}},
+			{Name: "lint", Uses: "lint@v1"},
+		},
+	}
+	test := &common.Job{
+		Name:          "test",
+		DependsOn:     []string{"build"},
+		Steps:         []*common.Step{{Name: "test", Run: []string{"cat out.txt", "exit 1"}}},
+		RollbackSteps: []*common.Step{{Name: "revert", Run: []string{"echo revert"}}},
+	}
+	pistage := &common.Pistage{
+		WorkflowIdentifier: "plugin",
+		Jobs:               map[string]*common.Job{"build": build, "test": test},
+		Vars:               map[string]string{"version": "v1"},
+		Bundle:             map[string][]byte{"notes.txt": []byte("notes")},
+	}
+
+	output := &bytes.Buffer{}
+	executor, err := provider.GetJobExecutor(build, pistage, output)
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("prepare build\necho compile\nv1\nnotes\nsh main.sh echo lint@v1\ncleanup build\n", output.String())
+	assert.Equal([]string{"out.txt"}, build.GetFileCollector().Files())
+
+	output.Reset()
+	executor, err = provider.GetJobExecutor(test, pistage, output)
+	assert.NoError(err)
+	assert.NoError(executor.Prepare(ctx))
+	assert.ErrorIs(executor.Execute(ctx), common.ErrExecutionError)
+	assert.NoError(executor.Rollback(ctx))
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("prepare test\nbinary\necho revert\ncleanup test\n", output.String())
+
+	// executor is forgotten after cleanup.
+	assert.Error(executor.Execute(ctx))
+}
@@ -0,0 +1,426 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ExecutorPluginClient is the client API for ExecutorPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExecutorPluginClient interface {
+	Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeReply, error)
+	Prepare(ctx context.Context, in *PrepareRequest, opts ...grpc.CallOption) (ExecutorPlugin_PrepareClient, error)
+	Execute(ctx context.Context, in *PhaseRequest, opts ...grpc.CallOption) (ExecutorPlugin_ExecuteClient, error)
+	Cleanup(ctx context.Context, in *PhaseRequest, opts ...grpc.CallOption) (ExecutorPlugin_CleanupClient, error)
+	Rollback(ctx context.Context, in *PhaseRequest, opts ...grpc.CallOption) (ExecutorPlugin_RollbackClient, error)
+	CollectFiles(ctx context.Context, in *CollectFilesRequest, opts ...grpc.CallOption) (*Files, error)
+	CopyFiles(ctx context.Context, in *CopyFilesRequest, opts ...grpc.CallOption) (*CopyFilesReply, error)
+}
+
+type executorPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExecutorPluginClient(cc grpc.ClientConnInterface) ExecutorPluginClient {
+	return &executorPluginClient{cc}
+}
+
+func (c *executorPluginClient) Handshake(ctx context.Context, in *HandshakeRequest, opts ...grpc.CallOption) (*HandshakeReply, error) {
+	out := new(HandshakeReply)
+	err := c.cc.Invoke(ctx, "/plugin.ExecutorPlugin/Handshake", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorPluginClient) Prepare(ctx context.Context, in *PrepareRequest, opts ...grpc.CallOption) (ExecutorPlugin_PrepareClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ExecutorPlugin_ServiceDesc.Streams[0], "/plugin.ExecutorPlugin/Prepare", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &executorPluginPrepareClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExecutorPlugin_PrepareClient interface {
+	Recv() (*Output, error)
+	grpc.ClientStream
+}
+
+type executorPluginPrepareClient struct {
+	grpc.ClientStream
+}
+
+func (x *executorPluginPrepareClient) Recv() (*Output, error) {
+	m := new(Output)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *executorPluginClient) Execute(ctx context.Context, in *PhaseRequest, opts ...grpc.CallOption) (ExecutorPlugin_ExecuteClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ExecutorPlugin_ServiceDesc.Streams[1], "/plugin.ExecutorPlugin/Execute", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &executorPluginExecuteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExecutorPlugin_ExecuteClient interface {
+	Recv() (*Output, error)
+	grpc.ClientStream
+}
+
+type executorPluginExecuteClient struct {
+	grpc.ClientStream
+}
+
+func (x *executorPluginExecuteClient) Recv() (*Output, error) {
+	m := new(Output)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *executorPluginClient) Cleanup(ctx context.Context, in *PhaseRequest, opts ...grpc.CallOption) (ExecutorPlugin_CleanupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ExecutorPlugin_ServiceDesc.Streams[2], "/plugin.ExecutorPlugin/Cleanup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &executorPluginCleanupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExecutorPlugin_CleanupClient interface {
+	Recv() (*Output, error)
+	grpc.ClientStream
+}
+
+type executorPluginCleanupClient struct {
+	grpc.ClientStream
+}
+
+func (x *executorPluginCleanupClient) Recv() (*Output, error) {
+	m := new(Output)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *executorPluginClient) Rollback(ctx context.Context, in *PhaseRequest, opts ...grpc.CallOption) (ExecutorPlugin_RollbackClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ExecutorPlugin_ServiceDesc.Streams[3], "/plugin.ExecutorPlugin/Rollback", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &executorPluginRollbackClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExecutorPlugin_RollbackClient interface {
+	Recv() (*Output, error)
+	grpc.ClientStream
+}
+
+type executorPluginRollbackClient struct {
+	grpc.ClientStream
+}
+
+func (x *executorPluginRollbackClient) Recv() (*Output, error) {
+	m := new(Output)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *executorPluginClient) CollectFiles(ctx context.Context, in *CollectFilesRequest, opts ...grpc.CallOption) (*Files, error) {
+	out := new(Files)
+	err := c.cc.Invoke(ctx, "/plugin.ExecutorPlugin/CollectFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorPluginClient) CopyFiles(ctx context.Context, in *CopyFilesRequest, opts ...grpc.CallOption) (*CopyFilesReply, error) {
+	out := new(CopyFilesReply)
+	err := c.cc.Invoke(ctx, "/plugin.ExecutorPlugin/CopyFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExecutorPluginServer is the server API for ExecutorPlugin service.
+// All implementations must embed UnimplementedExecutorPluginServer
+// for forward compatibility
+type ExecutorPluginServer interface {
+	Handshake(context.Context, *HandshakeRequest) (*HandshakeReply, error)
+	Prepare(*PrepareRequest, ExecutorPlugin_PrepareServer) error
+	Execute(*PhaseRequest, ExecutorPlugin_ExecuteServer) error
+	Cleanup(*PhaseRequest, ExecutorPlugin_CleanupServer) error
+	Rollback(*PhaseRequest, ExecutorPlugin_RollbackServer) error
+	CollectFiles(context.Context, *CollectFilesRequest) (*Files, error)
+	CopyFiles(context.Context, *CopyFilesRequest) (*CopyFilesReply, error)
+	mustEmbedUnimplementedExecutorPluginServer()
+}
+
+// UnimplementedExecutorPluginServer must be embedded to have forward compatible implementations.
+type UnimplementedExecutorPluginServer struct {
+}
+
+func (UnimplementedExecutorPluginServer) Handshake(context.Context, *HandshakeRequest) (*HandshakeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Handshake not implemented")
+}
+func (UnimplementedExecutorPluginServer) Prepare(*PrepareRequest, ExecutorPlugin_PrepareServer) error {
+	return status.Errorf(codes.Unimplemented, "method Prepare not implemented")
+}
+func (UnimplementedExecutorPluginServer) Execute(*PhaseRequest, ExecutorPlugin_ExecuteServer) error {
+	return status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedExecutorPluginServer) Cleanup(*PhaseRequest, ExecutorPlugin_CleanupServer) error {
+	return status.Errorf(codes.Unimplemented, "method Cleanup not implemented")
+}
+func (UnimplementedExecutorPluginServer) Rollback(*PhaseRequest, ExecutorPlugin_RollbackServer) error {
+	return status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (UnimplementedExecutorPluginServer) CollectFiles(context.Context, *CollectFilesRequest) (*Files, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectFiles not implemented")
+}
+func (UnimplementedExecutorPluginServer) CopyFiles(context.Context, *CopyFilesRequest) (*CopyFilesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyFiles not implemented")
+}
+func (UnimplementedExecutorPluginServer) mustEmbedUnimplementedExecutorPluginServer() {}
+
+// UnsafeExecutorPluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExecutorPluginServer will
+// result in compilation errors.
+type UnsafeExecutorPluginServer interface {
+	mustEmbedUnimplementedExecutorPluginServer()
+}
+
+func RegisterExecutorPluginServer(s grpc.ServiceRegistrar, srv ExecutorPluginServer) {
+	s.RegisterService(&ExecutorPlugin_ServiceDesc, srv)
+}
+
+func _ExecutorPlugin_Handshake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandshakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorPluginServer).Handshake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.ExecutorPlugin/Handshake",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorPluginServer).Handshake(ctx, req.(*HandshakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExecutorPlugin_Prepare_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PrepareRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutorPluginServer).Prepare(m, &executorPluginPrepareServer{stream})
+}
+
+type ExecutorPlugin_PrepareServer interface {
+	Send(*Output) error
+	grpc.ServerStream
+}
+
+type executorPluginPrepareServer struct {
+	grpc.ServerStream
+}
+
+func (x *executorPluginPrepareServer) Send(m *Output) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ExecutorPlugin_Execute_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PhaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutorPluginServer).Execute(m, &executorPluginExecuteServer{stream})
+}
+
+type ExecutorPlugin_ExecuteServer interface {
+	Send(*Output) error
+	grpc.ServerStream
+}
+
+type executorPluginExecuteServer struct {
+	grpc.ServerStream
+}
+
+func (x *executorPluginExecuteServer) Send(m *Output) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ExecutorPlugin_Cleanup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PhaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutorPluginServer).Cleanup(m, &executorPluginCleanupServer{stream})
+}
+
+type ExecutorPlugin_CleanupServer interface {
+	Send(*Output) error
+	grpc.ServerStream
+}
+
+type executorPluginCleanupServer struct {
+	grpc.ServerStream
+}
+
+func (x *executorPluginCleanupServer) Send(m *Output) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ExecutorPlugin_Rollback_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PhaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutorPluginServer).Rollback(m, &executorPluginRollbackServer{stream})
+}
+
+type ExecutorPlugin_RollbackServer interface {
+	Send(*Output) error
+	grpc.ServerStream
+}
+
+type executorPluginRollbackServer struct {
+	grpc.ServerStream
+}
+
+func (x *executorPluginRollbackServer) Send(m *Output) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ExecutorPlugin_CollectFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorPluginServer).CollectFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.ExecutorPlugin/CollectFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorPluginServer).CollectFiles(ctx, req.(*CollectFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExecutorPlugin_CopyFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorPluginServer).CopyFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.ExecutorPlugin/CopyFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorPluginServer).CopyFiles(ctx, req.(*CopyFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExecutorPlugin_ServiceDesc is the grpc.ServiceDesc for ExecutorPlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExecutorPlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.ExecutorPlugin",
+	HandlerType: (*ExecutorPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Handshake",
+			Handler:    _ExecutorPlugin_Handshake_Handler,
+		},
+		{
+			MethodName: "CollectFiles",
+			Handler:    _ExecutorPlugin_CollectFiles_Handler,
+		},
+		{
+			MethodName: "CopyFiles",
+			Handler:    _ExecutorPlugin_CopyFiles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Prepare",
+			Handler:       _ExecutorPlugin_Prepare_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Execute",
+			Handler:       _ExecutorPlugin_Execute_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Cleanup",
+			Handler:       _ExecutorPlugin_Cleanup_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Rollback",
+			Handler:       _ExecutorPlugin_Rollback_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "executors/plugin/proto/plugin.proto",
+}
@@ -0,0 +1,147 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	pluginpb "github.com/projecteru2/pistage/executors/plugin/proto"
+	"github.com/projecteru2/pistage/helpers/command"
+	"github.com/projecteru2/pistage/store"
+)
+
+const (
+	// ProtocolVersion is the version of plugin protocol,
+	// plugins must respond the same version in handshake.
+	ProtocolVersion = 1
+
+	// SocketEnv is the environment variable telling the launched plugin where to listen.
+	SocketEnv = "PISTAGE_PLUGIN_SOCKET"
+)
+
+var (
+	// ErrorPluginNotConfigured is returned when neither socket nor command is given.
+	ErrorPluginNotConfigured = errors.New("Plugin has neither socket nor command")
+
+	// ErrorProtocolVersionMismatch is returned when the plugin speaks another protocol version.
+	ErrorProtocolVersionMismatch = errors.New("Plugin protocol version mismatch")
+)
+
+// PluginJobExecutorProvider provides job executors running in a plugin process,
+// it talks to the plugin over gRPC on a unix socket.
+type PluginJobExecutorProvider struct {
+	config common.PluginConfig
+	store  store.Store
+	conn   *grpc.ClientConn
+	client pluginpb.ExecutorPluginClient
+	cmd    *exec.Cmd
+}
+
+// NewPluginJobExecutorProvider connects to the plugin, launches it first if no socket is given.
+// The launched plugin is killed when ctx is done.
+func NewPluginJobExecutorProvider(ctx context.Context, config common.PluginConfig, store store.Store) (*PluginJobExecutorProvider, error) {
+	p := &PluginJobExecutorProvider{
+		config: config,
+		store:  store,
+	}
+
+	socket := config.Socket
+	if socket == "" {
+		if len(config.Command) == 0 {
+			return nil, errors.WithMessagef(ErrorPluginNotConfigured, "plugin: %s", config.Name)
+		}
+		socket = filepath.Join(os.TempDir(), fmt.Sprintf("pistage-plugin-%s-%d.sock", config.Name, os.Getpid()))
+		if err := p.launch(ctx, socket); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.connect(ctx, socket); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// launch starts the plugin process, telling it to listen on socket.
+func (p *PluginJobExecutorProvider) launch(ctx context.Context, socket string) error {
+	os.Remove(socket)
+
+	cmd := exec.CommandContext(ctx, p.config.Command[0], p.config.Command[1:]...)
+	cmd.Env = append(os.Environ(), command.ToEnvironmentList(p.config.Env)...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", SocketEnv, socket))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	p.cmd = cmd
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			logrus.WithField("plugin", p.config.Name).WithError(err).Error("[PluginJobExecutorProvider] plugin exited")
+		}
+	}()
+	return nil
+}
+
+// connect dials socket, and handshakes with the plugin.
+// The plugin may not be listening yet if just launched, so dial blocks until timeout.
+func (p *PluginJobExecutorProvider) connect(ctx context.Context, socket string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.config.StartTimeoutSecs)*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, socket,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", address)
+		}),
+	)
+	if err != nil {
+		return errors.WithMessagef(err, "plugin: %s", p.config.Name)
+	}
+	p.conn = conn
+	p.client = pluginpb.NewExecutorPluginClient(conn)
+
+	reply, err := p.client.Handshake(ctx, &pluginpb.HandshakeRequest{ProtocolVersion: ProtocolVersion})
+	if err != nil {
+		return err
+	}
+	if reply.ProtocolVersion != ProtocolVersion {
+		return errors.WithMessagef(ErrorProtocolVersionMismatch, "plugin: %s, version: %d", p.config.Name, reply.ProtocolVersion)
+	}
+	logrus.WithField("plugin", p.config.Name).Infof("[PluginJobExecutorProvider] connected to plugin %s", reply.Name)
+	return nil
+}
+
+// Close closes the connection and kills the plugin if it's launched by pistage.
+func (p *PluginJobExecutorProvider) Close() {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	if p.cmd != nil && p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+}
+
+// GetName returns the name in config, not the name reported by plugin,
+// so the same plugin can be registered for multiple times with different configs.
+func (p *PluginJobExecutorProvider) GetName() string {
+	return p.config.Name
+}
+
+func (p *PluginJobExecutorProvider) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
+	return NewPluginJobExecutor(job, pistage, output, p.client, p.store)
+}
@@ -0,0 +1,255 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	pluginpb "github.com/projecteru2/pistage/executors/plugin/proto"
+)
+
+// ErrorNoSocket is returned by Serve when PISTAGE_PLUGIN_SOCKET is not set.
+var ErrorNoSocket = errors.New("PISTAGE_PLUGIN_SOCKET not set")
+
+// Provider is implemented by plugins to provide job executors,
+// it's like executors.ExecutorProvider, but KhoriumSteps used by the job
+// are given by pistage, since plugins have no access to the store.
+type Provider interface {
+	GetName() string
+	GetJobExecutor(job *common.Job, pistage *common.Pistage, khoriumSteps map[string]*common.KhoriumStep, output io.Writer) (executors.JobExecutor, error)
+}
+
+// FileTransfer is implemented by job executors supporting files,
+// paths of files are relative to the working dir of the job.
+type FileTransfer interface {
+	CollectFiles(ctx context.Context, files []string) (map[string][]byte, error)
+	CopyFiles(ctx context.Context, files map[string][]byte) error
+}
+
+// Serve serves provider on the socket in PISTAGE_PLUGIN_SOCKET,
+// it's usually the only thing main of a plugin does.
+func Serve(provider Provider) error {
+	socket := os.Getenv(SocketEnv)
+	if socket == "" {
+		return ErrorNoSocket
+	}
+
+	os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	s := grpc.NewServer()
+	pluginpb.RegisterExecutorPluginServer(s, NewServer(provider))
+	return s.Serve(listener)
+}
+
+// NewServer returns the gRPC server of plugin protocol, serving provider.
+func NewServer(provider Provider) pluginpb.ExecutorPluginServer {
+	return &server{
+		provider:  provider,
+		executors: map[string]*pluginExecutor{},
+	}
+}
+
+// pluginExecutor is a job executor in plugin,
+// output is switched to the stream of the current phase.
+type pluginExecutor struct {
+	executor executors.JobExecutor
+	output   *streamWriter
+}
+
+type server struct {
+	pluginpb.UnimplementedExecutorPluginServer
+
+	mutex     sync.Mutex
+	provider  Provider
+	executors map[string]*pluginExecutor
+}
+
+func (s *server) Handshake(ctx context.Context, req *pluginpb.HandshakeRequest) (*pluginpb.HandshakeReply, error) {
+	return &pluginpb.HandshakeReply{
+		ProtocolVersion: ProtocolVersion,
+		Name:            s.provider.GetName(),
+	}, nil
+}
+
+func (s *server) Prepare(req *pluginpb.PrepareRequest, stream pluginpb.ExecutorPlugin_PrepareServer) error {
+	job := &common.Job{}
+	if err := json.Unmarshal(req.Job, job); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	pistage := &common.Pistage{}
+	if err := json.Unmarshal(req.Pistage, pistage); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	pistage.Vars = req.Vars
+	pistage.Bundle = req.Bundle
+	// jobs in pistage must be the same object as job,
+	// since executors may find job by its name.
+	if pistage.Jobs == nil {
+		pistage.Jobs = map[string]*common.Job{}
+	}
+	pistage.Jobs[job.Name] = job
+
+	khoriumSteps := map[string]*common.KhoriumStep{}
+	for name, ks := range req.KhoriumSteps {
+		khoriumStep := &common.KhoriumStep{}
+		if err := json.Unmarshal(ks.Spec, khoriumStep); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		khoriumStep.Files = ks.Files
+		khoriumSteps[name] = khoriumStep
+	}
+
+	output := &streamWriter{}
+	executor, err := s.provider.GetJobExecutor(job, pistage, khoriumSteps, output)
+	if err != nil {
+		return toStatus(err)
+	}
+
+	s.mutex.Lock()
+	s.executors[req.ExecutorID] = &pluginExecutor{executor: executor, output: output}
+	s.mutex.Unlock()
+
+	output.setStream(stream)
+	defer output.setStream(nil)
+	return toStatus(executor.Prepare(stream.Context()))
+}
+
+func (s *server) Execute(req *pluginpb.PhaseRequest, stream pluginpb.ExecutorPlugin_ExecuteServer) error {
+	return s.runPhase(req.ExecutorID, stream, func(e executors.JobExecutor) error {
+		return e.Execute(stream.Context())
+	})
+}
+
+// Cleanup cleans up the job executor, and forgets it.
+func (s *server) Cleanup(req *pluginpb.PhaseRequest, stream pluginpb.ExecutorPlugin_CleanupServer) error {
+	defer func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.executors, req.ExecutorID)
+	}()
+	return s.runPhase(req.ExecutorID, stream, func(e executors.JobExecutor) error {
+		return e.Cleanup(stream.Context())
+	})
+}
+
+func (s *server) Rollback(req *pluginpb.PhaseRequest, stream pluginpb.ExecutorPlugin_RollbackServer) error {
+	return s.runPhase(req.ExecutorID, stream, func(e executors.JobExecutor) error {
+		return e.Rollback(stream.Context())
+	})
+}
+
+func (s *server) CollectFiles(ctx context.Context, req *pluginpb.CollectFilesRequest) (*pluginpb.Files, error) {
+	transfer, err := s.getFileTransfer(req.ExecutorID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := transfer.CollectFiles(ctx, req.Files)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pluginpb.Files{Files: files}, nil
+}
+
+func (s *server) CopyFiles(ctx context.Context, req *pluginpb.CopyFilesRequest) (*pluginpb.CopyFilesReply, error) {
+	transfer, err := s.getFileTransfer(req.ExecutorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := transfer.CopyFiles(ctx, req.Files); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pluginpb.CopyFilesReply{}, nil
+}
+
+func (s *server) getExecutor(id string) (*pluginExecutor, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e, ok := s.executors[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "executor %s not found", id)
+	}
+	return e, nil
+}
+
+func (s *server) getFileTransfer(id string) (FileTransfer, error) {
+	e, err := s.getExecutor(id)
+	if err != nil {
+		return nil, err
+	}
+	transfer, ok := e.executor.(FileTransfer)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "executor doesn't support files")
+	}
+	return transfer, nil
+}
+
+// runPhase runs f on the executor, with output written to stream.
+func (s *server) runPhase(id string, stream outputSender, f func(executors.JobExecutor) error) error {
+	e, err := s.getExecutor(id)
+	if err != nil {
+		return err
+	}
+
+	e.output.setStream(stream)
+	defer e.output.setStream(nil)
+	return toStatus(f(e.executor))
+}
+
+// toStatus converts ErrExecutionError to status with code Aborted.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, common.ErrExecutionError) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+type outputSender interface {
+	Send(*pluginpb.Output) error
+}
+
+// streamWriter writes output to the stream of the current phase,
+// output is discarded if no phase is running.
+type streamWriter struct {
+	mutex  sync.Mutex
+	stream outputSender
+}
+
+func (w *streamWriter) setStream(stream outputSender) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.stream = stream
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stream == nil {
+		return len(p), nil
+	}
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := w.stream.Send(&pluginpb.Output{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,69 @@
+package shell
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/helpers/command"
+)
+
+// runShell runs shell in the working dir, writes the output to output.
+// It's run like steps, with the same environment, isolation, user and rlimits,
+// so caches can only be restored to and saved from where steps can reach.
+func (sje *ShellJobExecutor) runShell(ctx context.Context, shell string, output io.Writer) error {
+	cmd := sje.command(ctx, shell, sje.workingDir, command.MergeVariables(sje.defaultEnvironmentVariables(), sje.jobEnvironment))
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Run(); err != nil {
+		return errors.WithMessagef(common.ErrExecutionError, "exec error: %v", err)
+	}
+	return nil
+}
+
+// restoreCache restores the caches of job into the working dir.
+func (sje *ShellJobExecutor) restoreCache(ctx context.Context) error {
+	return sje.caches.Restore(ctx, sje.job, sje.jobEnvironment, sje.store, sje.output, sje.cacheTransport())
+}
+
+// saveCache saves the missed caches of job.
+func (sje *ShellJobExecutor) saveCache(ctx context.Context) {
+	sje.caches.Save(ctx, sje.job, sje.store, sje.output, sje.cacheTransport())
+}
+
+// cacheTransport runs commands with runShell, tarballs are local files in the working dir,
+// uploaded ones are owned by the user commands run as, so they can be extracted.
+func (sje *ShellJobExecutor) cacheTransport() *executors.CacheTransport {
+	return &executors.CacheTransport{
+		Run: sje.runShell,
+		Upload: func(ctx context.Context, name string, content io.Reader) error {
+			path := filepath.Join(sje.workingDir, name)
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, content); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			return chownAll(path, sje.config.Shell.UID, sje.config.Shell.GID)
+		},
+		Download: func(ctx context.Context, name string, w io.Writer) error {
+			f, err := os.Open(filepath.Join(sje.workingDir, name))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		},
+	}
+}
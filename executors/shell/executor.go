@@ -11,6 +11,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
 	"github.com/projecteru2/pistage/helpers/command"
 	"github.com/projecteru2/pistage/helpers/variable"
 	"github.com/projecteru2/pistage/store"
@@ -23,17 +24,37 @@ type ShellJobExecutor struct {
 	job     *common.Job
 	pistage *common.Pistage
 
+	// workspaces manages the working dirs,
+	// namespaces tells if commands run in new namespaces.
+	workspaces *workspaceManager
+	namespaces bool
+
 	output         io.Writer
 	workingDir     string
 	jobEnvironment map[string]string
+
+	// failed is set if any of preparing, executing or rolling back fails,
+	// it decides whether to keep the working dir.
+	failed bool
+
+	// caches are the caches of job, restored in Prepare.
+	caches executors.JobCaches
+
+	// posts are run in Cleanup, see executors.PostHooks.
+	posts executors.PostHooks
+
+	// outputs holds the outputs of KhoriumSteps executed.
+	outputs executors.StepOutputs
 }
 
 // NewShellJobExecutor creates an Shell executor for this job.
 // Since job needs to know its context, pistage is assigned too.
-func NewShellJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, store store.Store, config *common.Config) (*ShellJobExecutor, error) {
+func NewShellJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, store store.Store, config *common.Config, workspaces *workspaceManager, namespaces bool) (*ShellJobExecutor, error) {
 	return &ShellJobExecutor{
 		store:          store,
 		config:         config,
+		workspaces:     workspaces,
+		namespaces:     namespaces,
 		job:            job,
 		pistage:        pistage,
 		output:         output,
@@ -46,22 +67,31 @@ func (sje *ShellJobExecutor) Prepare(ctx context.Context) error {
 	preparations := []func(context.Context) error{
 		sje.prepareJobRuntime,
 		sje.prepareFileContext,
+		sje.prepareOwnership,
+		sje.restoreCache,
 	}
 	for _, f := range preparations {
 		if err := f(ctx); err != nil {
+			sje.failed = true
 			return err
 		}
 	}
 	return nil
 }
 
-// Prepare creates a temp working dir for this job.
+// prepareJobRuntime creates a working dir for this job.
 func (sje *ShellJobExecutor) prepareJobRuntime(ctx context.Context) error {
 	var err error
-	sje.workingDir, err = ioutil.TempDir("", "pistage-*")
+	sje.workingDir, err = sje.workspaces.create()
 	return err
 }
 
+// prepareOwnership gives the working dir to the user commands run as,
+// files are copied by pistage itself, caches are restored after as the user.
+func (sje *ShellJobExecutor) prepareOwnership(ctx context.Context) error {
+	return chownAll(sje.workingDir, sje.config.Shell.UID, sje.config.Shell.GID)
+}
+
 func (sje *ShellJobExecutor) prepareFileContext(ctx context.Context) error {
 	dependentJobs := sje.pistage.GetJobs(sje.job.DependsOn)
 	for _, job := range dependentJobs {
@@ -85,12 +115,25 @@ func (sje *ShellJobExecutor) defaultEnvironmentVariables() map[string]string {
 	}
 }
 
-// Execute will execute all steps within this job one by one
+// Execute will execute all steps within this job one by one,
+// caches missed are saved after all steps succeeded.
 func (sje *ShellJobExecutor) Execute(ctx context.Context) error {
-	return sje.executeSteps(ctx, sje.job.Steps)
+	if err := sje.executeSteps(ctx, sje.job.Steps); err != nil {
+		sje.failed = true
+		return err
+	}
+	sje.saveCache(ctx)
+	return nil
 }
 
 func (sje *ShellJobExecutor) executeSteps(ctx context.Context, steps []*common.Step) error {
+	// composite KhoriumSteps are expanded first,
+	// so only plain steps and plain KhoriumSteps are left.
+	steps, err := executors.ExpandSteps(ctx, sje.store, steps, sje.pistage.Vars)
+	if err != nil {
+		return err
+	}
+
 	for _, step := range steps {
 		var err error
 		switch step.Uses {
@@ -118,10 +161,10 @@ func (sje *ShellJobExecutor) executeSteps(ctx context.Context, steps []*common.S
 func (sje *ShellJobExecutor) executeStep(ctx context.Context, step *common.Step) error {
 	var (
 		err  error
-		vars map[string]string
+		vars = sje.pistage.Vars
 	)
 
-	environment := command.MergeVariables(sje.jobEnvironment, step.Environment)
+	environment := command.MergeVariables(command.MergeVariables(sje.jobEnvironment, sje.outputs.Environment()), step.Environment)
 
 	defer func() {
 		if !errors.Is(err, common.ErrExecutionError) {
@@ -143,7 +186,7 @@ func (sje *ShellJobExecutor) executeKhoriumStep(ctx context.Context, step *commo
 		return err
 	}
 
-	arguments, err := variable.RenderArguments(step.With, step.Environment, map[string]string{})
+	arguments, err := variable.RenderArguments(step.With, command.MergeVariables(sje.outputs.Environment(), step.Environment), sje.pistage.Vars)
 	if err != nil {
 		return err
 	}
@@ -152,30 +195,51 @@ func (sje *ShellJobExecutor) executeKhoriumStep(ctx context.Context, step *commo
 	if err != nil {
 		return err
 	}
-	envs := command.MergeVariables(sje.defaultEnvironmentVariables(), step.Environment)
+	envs := command.MergeVariables(command.MergeVariables(sje.defaultEnvironmentVariables(), sje.outputs.Environment()), step.Environment)
 	envs = command.MergeVariables(envs, ksEnv)
 
-	khoriumStepWorkingDir, err := ioutil.TempDir("", "pistage-khoriumstep-*")
+	// within the working dir, so it's under the workspace root, and removed along with the workspace.
+	khoriumStepWorkingDir, err := ioutil.TempDir(sje.workingDir, ".pistage-khoriumstep-*")
 	if err != nil {
 		return err
 	}
-	defer os.RemoveAll(khoriumStepWorkingDir)
+	// working dir is kept for post once main is executed,
+	// it's removed after post is executed.
+	queued := false
+	defer func() {
+		if !queued {
+			os.RemoveAll(khoriumStepWorkingDir)
+		}
+	}()
 
 	fc := NewShellFileCollector()
 	fc.SetFiles(ks.Files)
 	if err := fc.CopyTo(ctx, khoriumStepWorkingDir, nil); err != nil {
 		return err
 	}
+	if err := chownAll(khoriumStepWorkingDir, sje.config.Shell.UID, sje.config.Shell.GID); err != nil {
+		return err
+	}
 
-	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", ks.Run.Main)
-	cmd.Dir = khoriumStepWorkingDir
-	cmd.Env = command.ToEnvironmentList(envs)
-	cmd.Stdout = sje.output
-	cmd.Stderr = sje.output
+	outputFile, err := executors.OutputFile(khoriumStepWorkingDir, step)
+	if err != nil {
+		return err
+	}
+	if len(ks.Outputs) != 0 {
+		envs[executors.KhoriumStepOutputEnv] = outputFile
+	}
+
+	queued = sje.posts.Queue(step.Name, ks, envs, khoriumStepWorkingDir)
+
+	cmd := sje.command(ctx, ks.Run.Main, khoriumStepWorkingDir, envs)
 	if err := cmd.Run(); err != nil {
 		return errors.WithMessagef(common.ErrExecutionError, "exec error: %v", err)
 	}
-	return nil
+	return sje.outputs.Collect(ks, step.Name, outputFile, func(c string, output io.Writer) error {
+		cmd := sje.command(ctx, c, khoriumStepWorkingDir, envs)
+		cmd.Stdout = output
+		return cmd.Run()
+	})
 }
 
 // executeCommands executes cmd with given arguments, environments and variables.
@@ -195,11 +259,7 @@ func (sje *ShellJobExecutor) executeCommands(ctx context.Context, cmds []string,
 	}
 
 	for _, c := range commands {
-		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", c)
-		cmd.Dir = sje.workingDir
-		cmd.Env = command.ToEnvironmentList(command.MergeVariables(sje.defaultEnvironmentVariables(), env))
-		cmd.Stdout = sje.output
-		cmd.Stderr = sje.output
+		cmd := sje.command(ctx, c, sje.workingDir, command.MergeVariables(sje.defaultEnvironmentVariables(), env))
 		if err := cmd.Run(); err != nil {
 			return errors.WithMessagef(common.ErrExecutionError, "exec error: %v", err)
 		}
@@ -207,9 +267,34 @@ func (sje *ShellJobExecutor) executeCommands(ctx context.Context, cmds []string,
 	return nil
 }
 
+// command builds the cmd to run shell in dir with envs,
+// the rlimits of job capped by config, and the isolation in config are applied.
+func (sje *ShellJobExecutor) command(ctx context.Context, shell, dir string, envs map[string]string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", limitShell(jobRlimits(sje.config.Shell.Rlimits, sje.job.Rlimits), shell))
+	cmd.Dir = dir
+	cmd.Env = command.ToEnvironmentList(envs)
+	cmd.Stdout = sje.output
+	cmd.Stderr = sje.output
+	cmd.SysProcAttr = sysProcAttr(sje.config.Shell, sje.namespaces)
+	return cmd
+}
+
+// executePost executes the post command of a KhoriumStep,
+// the working dir of KhoriumStep is removed afterwards.
+func (sje *ShellJobExecutor) executePost(ctx context.Context, hook *executors.PostHook) error {
+	defer os.RemoveAll(hook.WorkingDir)
+
+	cmd := sje.command(ctx, hook.Command, hook.WorkingDir, hook.Environment)
+	if err := cmd.Run(); err != nil {
+		sje.failed = true
+		return errors.WithMessagef(common.ErrExecutionError, "exec error: %v", err)
+	}
+	return nil
+}
+
 // beforeCleanup collects files
 func (sje *ShellJobExecutor) beforeCleanup(ctx context.Context) error {
-	if len(sje.job.Files) == 0 {
+	if len(sje.job.Files) == 0 || sje.workingDir == "" {
 		return nil
 	}
 
@@ -222,26 +307,34 @@ func (sje *ShellJobExecutor) beforeCleanup(ctx context.Context) error {
 	return nil
 }
 
-// cleanup removes the temp working dir.
+// cleanup removes the working dir by the cleanup policy.
 func (sje *ShellJobExecutor) cleanup(ctx context.Context) error {
-	return nil // return os.RemoveAll(sje.workingDir)
+	if sje.workingDir == "" {
+		return nil
+	}
+	return sje.workspaces.release(sje.workingDir, sje.failed)
 }
 
-// Cleanup does all the cleanup work
+// Cleanup does all the cleanup work,
+// post commands of KhoriumSteps are executed in reverse order first,
+// the working dir is always cleaned up even if any of them fails.
 func (sje *ShellJobExecutor) Cleanup(ctx context.Context) error {
-	cleanups := []func(context.Context) error{
-		sje.beforeCleanup,
-		sje.cleanup,
+	// each KhoriumStep has its own working dir, files are never copied again.
+	err := sje.posts.Run(ctx, nil, sje.executePost)
+	if cerr := sje.beforeCleanup(ctx); cerr != nil && err == nil {
+		err = cerr
 	}
-	for _, f := range cleanups {
-		if err := f(ctx); err != nil {
-			return err
-		}
+	if cerr := sje.cleanup(ctx); cerr != nil && err == nil {
+		err = cerr
 	}
-	return nil
+	return err
 }
 
 // Rollback is used to execute Rollback_steps commands
 func (sje *ShellJobExecutor) Rollback(ctx context.Context) error {
-	return sje.executeSteps(ctx, sje.job.RollbackSteps)
+	if err := sje.executeSteps(ctx, sje.job.RollbackSteps); err != nil {
+		sje.failed = true
+		return err
+	}
+	return nil
 }
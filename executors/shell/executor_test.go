@@ -0,0 +1,302 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/store"
+)
+
+func newTestExecutor(t *testing.T, config *common.Config, steps ...string) (*ShellJobExecutor, *bytes.Buffer) {
+	workspaces, err := newWorkspaceManager(config.Shell)
+	assert.NoError(t, err)
+
+	job := &common.Job{Name: "test", Steps: []*common.Step{{Name: "step", Run: steps}}}
+	pistage := &common.Pistage{WorkflowIdentifier: "test", Jobs: map[string]*common.Job{"test": job}}
+	output := &bytes.Buffer{}
+
+	namespaces := config.Shell.Isolation && namespacesAvailable()
+	executor, err := NewShellJobExecutor(job, pistage, output, nil, config, workspaces, namespaces)
+	assert.NoError(t, err)
+	return executor, output
+}
+
+func TestWorkspaceManager(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := newWorkspaceManager(common.ShellConfig{WorkspaceCleanup: "never"})
+	assert.ErrorIs(err, ErrorUnknownCleanupPolicy)
+
+	root := t.TempDir()
+	always, err := newWorkspaceManager(common.ShellConfig{WorkspaceRoot: root, WorkspaceCleanup: cleanupAlways})
+	assert.NoError(err)
+	dir, err := always.create()
+	assert.NoError(err)
+	assert.True(strings.HasPrefix(dir, filepath.Join(root, workspacePrefix)))
+	assert.NoError(always.release(dir, true))
+	assert.NoDirExists(dir)
+
+	onFailure, err := newWorkspaceManager(common.ShellConfig{WorkspaceRoot: root, WorkspaceCleanup: cleanupKeepOnFailure})
+	assert.NoError(err)
+	succeeded, _ := onFailure.create()
+	failed, _ := onFailure.create()
+	assert.NoError(onFailure.release(succeeded, false))
+	assert.NoError(onFailure.release(failed, true))
+	assert.NoDirExists(succeeded)
+	assert.DirExists(failed)
+	os.RemoveAll(failed)
+
+	last, err := newWorkspaceManager(common.ShellConfig{WorkspaceRoot: root, WorkspaceCleanup: cleanupKeepLast, KeepWorkspaces: 1})
+	assert.NoError(err)
+	var dirs []string
+	for i := 0; i < 3; i++ {
+		dir, err := last.create()
+		assert.NoError(err)
+		// make sure the modification times differ.
+		mtime := time.Now().Add(time.Duration(i) * time.Second)
+		assert.NoError(os.Chtimes(dir, mtime, mtime))
+		dirs = append(dirs, dir)
+	}
+	// dirs[0] and dirs[2] are still in use, only dirs[1] is removed.
+	assert.NoError(last.release(dirs[1], false))
+	assert.DirExists(dirs[0])
+	assert.NoDirExists(dirs[1])
+	assert.DirExists(dirs[2])
+
+	assert.NoError(last.release(dirs[2], false))
+	assert.DirExists(dirs[0])
+	assert.DirExists(dirs[2])
+
+	assert.NoError(last.release(dirs[0], false))
+	assert.NoDirExists(dirs[0])
+	assert.DirExists(dirs[2])
+}
+
+func TestLimitShell(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("echo", limitShell(common.ShellRlimitConfig{}, "echo"))
+
+	shell := limitShell(common.ShellRlimitConfig{CPUSecs: 10, MemoryMB: 512, OpenFiles: 64}, "ulimit -t; ulimit -v; ulimit -n")
+	output, err := exec.Command("/bin/sh", "-c", shell).Output()
+	assert.NoError(err)
+	assert.Equal("10\n524288\n64\n", string(output))
+
+	// limits of job are capped by config, 0 means the limit in config.
+	config := common.ShellRlimitConfig{CPUSecs: 10, MemoryMB: 512}
+	assert.Equal(config, jobRlimits(config, nil))
+	assert.Equal(
+		common.ShellRlimitConfig{CPUSecs: 5, MemoryMB: 512, OpenFiles: 64},
+		jobRlimits(config, &common.Rlimits{CPU: 5, Memory: 1 << 30, OpenFiles: 64}),
+	)
+	assert.Equal(
+		common.ShellRlimitConfig{CPUSecs: 10, MemoryMB: 256},
+		jobRlimits(config, &common.Rlimits{Memory: 256 << 20}),
+	)
+}
+
+func TestCheckUser(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(checkUser(common.ShellConfig{}, 1000))
+	assert.NoError(checkUser(common.ShellConfig{UID: 65534}, 0))
+	assert.ErrorIs(checkUser(common.ShellConfig{UID: 65534}, 1000), ErrorChangeUserRequiresRoot)
+	assert.ErrorIs(checkUser(common.ShellConfig{GID: 65534}, 1000), ErrorChangeUserRequiresRoot)
+}
+
+func TestShellJobExecutorCleanup(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	config := &common.Config{Shell: common.ShellConfig{WorkspaceRoot: t.TempDir(), WorkspaceCleanup: cleanupKeepOnFailure}}
+
+	executor, output := newTestExecutor(t, config, "echo hello > hello.txt", "cat hello.txt")
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("hello\n", output.String())
+	assert.NoDirExists(executor.workingDir)
+
+	executor, _ = newTestExecutor(t, config, "echo hello > hello.txt", "exit 1")
+	assert.NoError(executor.Prepare(ctx))
+	assert.ErrorIs(executor.Execute(ctx), common.ErrExecutionError)
+	assert.NoError(executor.Cleanup(ctx))
+	content, err := ioutil.ReadFile(filepath.Join(executor.workingDir, "hello.txt"))
+	assert.NoError(err)
+	assert.Equal("hello\n", string(content))
+}
+
+func TestShellJobExecutorIsolation(t *testing.T) {
+	if !namespacesAvailable() {
+		t.Skip("namespaces not available")
+	}
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// the workspace root must be accessible by the user commands run as.
+	root := t.TempDir()
+	assert.NoError(os.Chmod(filepath.Dir(root), 0755))
+	assert.NoError(os.Chmod(root, 0755))
+
+	config := &common.Config{Shell: common.ShellConfig{
+		WorkspaceRoot:    root,
+		WorkspaceCleanup: cleanupAlways,
+		Isolation:        true,
+	}}
+
+	// shell is the init process of the new PID namespace.
+	executor, output := newTestExecutor(t, config, "echo $$")
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("1\n", output.String())
+
+	if os.Geteuid() != 0 {
+		return
+	}
+
+	config.Shell.UID = 65534
+	config.Shell.GID = 65534
+	executor, output = newTestExecutor(t, config, "id -u", "id -g", "touch owned")
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	_, err := os.Stat(filepath.Join(executor.workingDir, "owned"))
+	assert.NoError(err)
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("65534\n65534\n", output.String())
+}
+
+func TestShellJobExecutorCacheShell(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	root := t.TempDir()
+	assert.NoError(os.Chmod(filepath.Dir(root), 0755))
+	assert.NoError(os.Chmod(root, 0755))
+
+	config := &common.Config{Shell: common.ShellConfig{
+		WorkspaceRoot:    root,
+		WorkspaceCleanup: cleanupAlways,
+		Rlimits:          common.ShellRlimitConfig{OpenFiles: 64},
+	}}
+	shell, expected := "ulimit -n; echo $PHISTAGE_JOB_NAME", "64\ntest\n"
+	if os.Geteuid() == 0 {
+		config.Shell.UID = 65534
+		shell, expected = shell+"; id -u", expected+"65534\n"
+	}
+
+	// cache commands run like steps, with the same environment, user and rlimits.
+	executor, _ := newTestExecutor(t, config)
+	assert.NoError(executor.Prepare(ctx))
+	output := &bytes.Buffer{}
+	assert.NoError(executor.runShell(ctx, shell, output))
+	assert.Equal(expected, output.String())
+	assert.NoError(executor.Cleanup(ctx))
+}
+
+// fakeStore only provides KhoriumSteps,
+// github.com/test/version outputs a version,
+// others write the input into a file, and post prints it.
+type fakeStore struct {
+	store.Store
+}
+
+func (s *fakeStore) GetRegisteredKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	if name == "github.com/test/version" {
+		return &common.KhoriumStep{
+			Name:    name,
+			Outputs: map[string]*common.KhoriumStepOutput{"version": {}, "missing": {}},
+			Run: &common.KhoriumStepRun{
+				Main: "echo version=1.2.3 >> $KHORIUMSTEP_OUTPUT; echo undeclared=x >> $KHORIUMSTEP_OUTPUT",
+			},
+		}, nil
+	}
+	return &common.KhoriumStep{
+		Name:   name,
+		Inputs: map[string]*common.KhoriumStepInput{"name": {Required: true}},
+		Run: &common.KhoriumStepRun{
+			Main: "echo $KHORIUMSTEP_INPUT_NAME > name.txt",
+			Post: "echo post $(cat name.txt)",
+		},
+	}, nil
+}
+
+func TestShellJobExecutorPost(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	config := &common.Config{Shell: common.ShellConfig{WorkspaceRoot: t.TempDir(), WorkspaceCleanup: cleanupAlways}}
+	executor, output := newTestExecutor(t, config)
+	executor.store = &fakeStore{}
+	executor.job.Steps = []*common.Step{
+		{Name: "first", Uses: "github.com/test/login", With: map[string]string{"name": "first"}},
+		{Name: "second", Uses: "github.com/test/login", With: map[string]string{"name": "second"}},
+		{Name: "fail", Run: []string{"exit 1"}},
+		{Name: "skipped", Uses: "github.com/test/login", With: map[string]string{"name": "skipped"}},
+	}
+
+	assert.NoError(executor.Prepare(ctx))
+	assert.ErrorIs(executor.Execute(ctx), common.ErrExecutionError)
+	assert.Equal("", output.String())
+
+	// working dirs of KhoriumSteps are within the workspace.
+	dirs, err := filepath.Glob(filepath.Join(executor.workingDir, ".pistage-khoriumstep-*"))
+	assert.NoError(err)
+	assert.Len(dirs, 2)
+
+	// posts are executed in reverse order even if the job fails.
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("post second\npost first\n", output.String())
+
+	// posts are executed only once.
+	output.Reset()
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("", output.String())
+}
+
+func TestShellJobExecutorOutputs(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	config := &common.Config{Shell: common.ShellConfig{WorkspaceRoot: t.TempDir(), WorkspaceCleanup: cleanupAlways}}
+	executor, output := newTestExecutor(t, config)
+	executor.store = &fakeStore{}
+	executor.job.Steps = []*common.Step{
+		{Name: "version", Uses: "github.com/test/version"},
+		{Name: "print", Run: []string{
+			"echo $KHORIUMSTEP_OUTPUT_VERSION_VERSION",
+			"echo {{ env.KHORIUMSTEP_OUTPUT_VERSION_VERSION }}",
+			"echo missing=$KHORIUMSTEP_OUTPUT_VERSION_MISSING undeclared=$KHORIUMSTEP_OUTPUT_VERSION_UNDECLARED",
+		}},
+		{Name: "login", Uses: "github.com/test/login", With: map[string]string{"name": "{{ env.KHORIUMSTEP_OUTPUT_VERSION_VERSION }}"}},
+	}
+
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("1.2.3\n1.2.3\nmissing= undeclared=\npost 1.2.3\n", output.String())
+}
+
+func TestShellJobExecutorVars(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	config := &common.Config{Shell: common.ShellConfig{WorkspaceRoot: t.TempDir(), WorkspaceCleanup: cleanupAlways}}
+	executor, output := newTestExecutor(t, config, "echo {{ vars.version | upper }} {{ $vars.region }}")
+	executor.pistage.Vars = map[string]string{"version": "v1", "region": "sg"}
+
+	assert.NoError(executor.Prepare(ctx))
+	assert.NoError(executor.Execute(ctx))
+	assert.NoError(executor.Cleanup(ctx))
+	assert.Equal("V1 sg\n", output.String())
+}
@@ -0,0 +1,53 @@
+//go:build linux
+// +build linux
+
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// sysProcAttr returns the attributes to run commands isolated.
+// Mount and PID namespaces are created if namespaces is true,
+// when not running as root, a user namespace mapping current user to root is created too,
+// so namespaces can be created without privileges.
+func sysProcAttr(config common.ShellConfig, namespaces bool) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{}
+	if namespaces {
+		attr.Cloneflags = syscall.CLONE_NEWNS | syscall.CLONE_NEWPID
+		if os.Geteuid() != 0 {
+			attr.Cloneflags |= syscall.CLONE_NEWUSER
+			attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+			attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+			attr.GidMappingsEnableSetgroups = false
+		}
+	}
+
+	if config.UID != 0 || config.GID != 0 {
+		credential := &syscall.Credential{
+			Uid:         uint32(os.Getuid()),
+			Gid:         uint32(os.Getgid()),
+			NoSetGroups: true,
+		}
+		if config.UID != 0 {
+			credential.Uid = uint32(config.UID)
+		}
+		if config.GID != 0 {
+			credential.Gid = uint32(config.GID)
+		}
+		attr.Credential = credential
+	}
+	return attr
+}
+
+// namespacesAvailable tells if namespaces can be created,
+// by running true within them.
+func namespacesAvailable() bool {
+	cmd := exec.Command("/bin/true")
+	cmd.SysProcAttr = sysProcAttr(common.ShellConfig{}, true)
+	return cmd.Run() == nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+// +build !linux
+
+package shell
+
+import (
+	"syscall"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// sysProcAttr returns nil, isolation is not supported on this platform.
+func sysProcAttr(config common.ShellConfig, namespaces bool) *syscall.SysProcAttr {
+	return nil
+}
+
+// namespacesAvailable is always false on this platform.
+func namespacesAvailable() bool {
+	return false
+}
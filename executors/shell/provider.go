@@ -2,21 +2,49 @@ package shell
 
 import (
 	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/projecteru2/pistage/common"
 	"github.com/projecteru2/pistage/executors"
 	"github.com/projecteru2/pistage/store"
 )
 
+// ErrorChangeUserRequiresRoot is returned when uid or gid is configured, but pistage is not running as root.
+// Commands can't run as another user then, the user namespace created for isolation maps only the current user.
+var ErrorChangeUserRequiresRoot = errors.New("Changing user requires running as root")
+
 type ShellJobExecutorProvider struct {
-	config *common.Config
-	store  store.Store
+	config     *common.Config
+	store      store.Store
+	workspaces *workspaceManager
+	namespaces bool
 }
 
 func NewShellJobExecutorProvider(config *common.Config, store store.Store) (*ShellJobExecutorProvider, error) {
+	if err := checkUser(config.Shell, os.Geteuid()); err != nil {
+		return nil, err
+	}
+
+	workspaces, err := newWorkspaceManager(config.Shell)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := false
+	if config.Shell.Isolation {
+		if namespaces = namespacesAvailable(); !namespaces {
+			logrus.Warn("[ShellJobExecutorProvider] namespaces not available, commands are not isolated")
+		}
+	}
+
 	return &ShellJobExecutorProvider{
-		config: config,
-		store:  store,
+		config:     config,
+		store:      store,
+		workspaces: workspaces,
+		namespaces: namespaces,
 	}, nil
 }
 
@@ -25,5 +53,13 @@ func (ls *ShellJobExecutorProvider) GetName() string {
 }
 
 func (ls *ShellJobExecutorProvider) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
-	return NewShellJobExecutor(job, pistage, output, ls.store, ls.config)
+	return NewShellJobExecutor(job, pistage, output, ls.store, ls.config, ls.workspaces, ls.namespaces)
+}
+
+// checkUser rejects uid and gid in config if euid is not root.
+func checkUser(config common.ShellConfig, euid int) error {
+	if (config.UID != 0 || config.GID != 0) && euid != 0 {
+		return errors.WithMessagef(ErrorChangeUserRequiresRoot, "uid: %d, gid: %d", config.UID, config.GID)
+	}
+	return nil
 }
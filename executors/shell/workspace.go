@@ -0,0 +1,190 @@
+package shell
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+const (
+	workspacePrefix = "pistage-workspace-"
+
+	cleanupAlways        = "always"
+	cleanupKeepOnFailure = "keep_on_failure"
+	cleanupKeepLast      = "keep_last"
+)
+
+// ErrorUnknownCleanupPolicy is returned when the workspace cleanup policy is not supported.
+var ErrorUnknownCleanupPolicy = errors.New("Unknown workspace cleanup policy")
+
+// workspaceManager creates workspaces for jobs, and removes them by the cleanup policy.
+// Workspaces in use are never removed, even if they are out of the most recent ones.
+type workspaceManager struct {
+	mutex  sync.Mutex
+	root   string
+	policy string
+	keep   int
+	active map[string]struct{}
+}
+
+func newWorkspaceManager(config common.ShellConfig) (*workspaceManager, error) {
+	switch config.WorkspaceCleanup {
+	case cleanupAlways, cleanupKeepOnFailure, cleanupKeepLast:
+	default:
+		return nil, errors.WithMessagef(ErrorUnknownCleanupPolicy, "policy: %s", config.WorkspaceCleanup)
+	}
+
+	root := config.WorkspaceRoot
+	if root == "" {
+		root = os.TempDir()
+	}
+	return &workspaceManager{
+		root:   root,
+		policy: config.WorkspaceCleanup,
+		keep:   config.KeepWorkspaces,
+		active: map[string]struct{}{},
+	}, nil
+}
+
+// create creates a new workspace and marks it in use.
+func (w *workspaceManager) create() (string, error) {
+	if err := os.MkdirAll(w.root, 0755); err != nil {
+		return "", err
+	}
+
+	dir, err := ioutil.TempDir(w.root, workspacePrefix+"*")
+	if err != nil {
+		return "", err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.active[dir] = struct{}{}
+	return dir, nil
+}
+
+// release marks dir not in use, and removes workspaces by the policy.
+// failed tells if the job using dir failed.
+func (w *workspaceManager) release(dir string, failed bool) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	delete(w.active, dir)
+
+	switch w.policy {
+	case cleanupAlways:
+		return os.RemoveAll(dir)
+	case cleanupKeepOnFailure:
+		if failed {
+			return nil
+		}
+		return os.RemoveAll(dir)
+	default:
+		return w.removeStale()
+	}
+}
+
+// removeStale removes the workspaces out of the most recent ones.
+// Must be called with mutex held.
+func (w *workspaceManager) removeStale() error {
+	entries, err := ioutil.ReadDir(w.root)
+	if err != nil {
+		return err
+	}
+
+	var workspaces []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), workspacePrefix) {
+			workspaces = append(workspaces, entry)
+		}
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].ModTime().After(workspaces[j].ModTime())
+	})
+
+	for index, workspace := range workspaces {
+		if index < w.keep {
+			continue
+		}
+		dir := filepath.Join(w.root, workspace.Name())
+		if _, ok := w.active[dir]; ok {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			logrus.WithField("workspace", dir).WithError(err).Warn("[ShellJobExecutor] error removing workspace")
+		}
+	}
+	return nil
+}
+
+// chownAll changes the owner of dir and everything in it,
+// so commands running as another user can access them.
+// uid or gid 0 means not to change.
+func chownAll(dir string, uid, gid int) error {
+	if uid == 0 && gid == 0 {
+		return nil
+	}
+	if uid == 0 {
+		uid = -1
+	}
+	if gid == 0 {
+		gid = -1
+	}
+	return filepath.Walk(dir, func(path string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, uid, gid)
+	})
+}
+
+// jobRlimits returns the rlimits of job, limits in config are the defaults,
+// limits of job exceeding them are capped.
+func jobRlimits(config common.ShellRlimitConfig, job *common.Rlimits) common.ShellRlimitConfig {
+	if job == nil {
+		return config
+	}
+	memoryMB := (int64(job.Memory) + 1<<20 - 1) >> 20
+	return common.ShellRlimitConfig{
+		CPUSecs:   int(capRlimit(int64(config.CPUSecs), int64(job.CPU))),
+		MemoryMB:  capRlimit(config.MemoryMB, memoryMB),
+		OpenFiles: int(capRlimit(int64(config.OpenFiles), int64(job.OpenFiles))),
+	}
+}
+
+// capRlimit returns limit of job, 0 means limit in config,
+// which is also the cap unless it's 0.
+func capRlimit(config, job int64) int64 {
+	if job <= 0 || config > 0 && job > config {
+		return config
+	}
+	return job
+}
+
+// limitShell prepends ulimit commands to shell,
+// so all the processes started by shell are limited.
+func limitShell(rlimits common.ShellRlimitConfig, shell string) string {
+	var limits []string
+	if rlimits.CPUSecs > 0 {
+		limits = append(limits, fmt.Sprintf("ulimit -t %d", rlimits.CPUSecs))
+	}
+	if rlimits.MemoryMB > 0 {
+		limits = append(limits, fmt.Sprintf("ulimit -v %d", rlimits.MemoryMB*1024))
+	}
+	if rlimits.OpenFiles > 0 {
+		limits = append(limits, fmt.Sprintf("ulimit -n %d", rlimits.OpenFiles))
+	}
+	if len(limits) == 0 {
+		return shell
+	}
+	return fmt.Sprintf("%s || exit 1\n%s", strings.Join(limits, " && "), shell)
+}
@@ -0,0 +1,62 @@
+package ssh
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/sftp"
+
+	"github.com/projecteru2/pistage/executors"
+)
+
+// restoreCache restores the caches of job into the working dir.
+func (s *SSHJobExecutor) restoreCache(ctx context.Context) error {
+	return s.caches.Restore(ctx, s.job, s.jobEnvironment, s.store, s.output, s.cacheTransport())
+}
+
+// saveCache saves the missed caches of job.
+func (s *SSHJobExecutor) saveCache(ctx context.Context) {
+	s.caches.Save(ctx, s.job, s.store, s.output, s.cacheTransport())
+}
+
+// cacheTransport runs commands in the working dir, tarballs are transferred through sftp.
+func (s *SSHJobExecutor) cacheTransport() *executors.CacheTransport {
+	return &executors.CacheTransport{
+		Run: func(ctx context.Context, cmd string, output io.Writer) error {
+			return executeCommand(s.client, cmd, s.workingDir, nil, output)
+		},
+		Upload: func(ctx context.Context, name string, content io.Reader) error {
+			sc, err := sftp.NewClient(s.client)
+			if err != nil {
+				return err
+			}
+			defer sc.Close()
+
+			f, err := sc.Create(filepath.Join(s.workingDir, name))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, content); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		},
+		Download: func(ctx context.Context, name string, w io.Writer) error {
+			sc, err := sftp.NewClient(s.client)
+			if err != nil {
+				return err
+			}
+			defer sc.Close()
+
+			f, err := sc.Open(filepath.Join(s.workingDir, name))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		},
+	}
+}
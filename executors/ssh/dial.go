@@ -0,0 +1,298 @@
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+const (
+	hostKeyPolicyStrict   = "strict"
+	hostKeyPolicyTOFU     = "tofu"
+	hostKeyPolicyInsecure = "insecure"
+)
+
+var (
+	// ErrorUnknownHostKeyPolicy is returned when the host key policy is not supported.
+	ErrorUnknownHostKeyPolicy = errors.New("Unknown SSH host key policy")
+
+	// ErrorNoAuthMethod is returned when no auth method is configured for a host.
+	ErrorNoAuthMethod = errors.New("No SSH auth method")
+
+	// ErrorDialTimeout is returned when a host is not connected within dialTimeout.
+	ErrorDialTimeout = errors.New("SSH dial timeout")
+
+	// knownHostsMutex serializes the reading and appending of known_hosts files.
+	knownHostsMutex sync.Mutex
+
+	// dialTimeout limits connecting and handshaking with each host, jump hosts included.
+	dialTimeout = 10 * time.Second
+)
+
+// dialHost dials host, through all the jump hosts in order if any.
+// When the client is closed, connections to jump hosts are closed as well.
+func dialHost(host common.SSHHostConfig) (*ssh.Client, error) {
+	var clients []*ssh.Client
+	closeAll := func() {
+		for i := len(clients) - 1; i >= 0; i-- {
+			clients[i].Close()
+		}
+	}
+
+	hops := append([]common.SSHJumpHostConfig{}, host.ProxyJump...)
+	hops = append(hops, common.SSHJumpHostConfig{SSHAuthConfig: host.SSHAuthConfig, Address: host.Address})
+	for _, hop := range hops {
+		config, agentConn, err := clientConfig(hop.SSHAuthConfig)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		var (
+			conn   net.Conn
+			client *ssh.Client
+		)
+		if len(clients) == 0 {
+			conn, err = net.DialTimeout("tcp", hop.Address, dialTimeout)
+		} else {
+			conn, err = clients[len(clients)-1].Dial("tcp", hop.Address)
+		}
+		if err == nil {
+			client, err = handshake(conn, hop.Address, config)
+		}
+		// agent is only used during handshake.
+		if agentConn != nil {
+			agentConn.Close()
+		}
+		if err != nil {
+			closeAll()
+			return nil, errors.WithMessagef(err, "dial %s", hop.Address)
+		}
+		clients = append(clients, client)
+	}
+
+	target := clients[len(clients)-1]
+	if len(clients) > 1 {
+		go func() {
+			target.Wait()
+			closeAll()
+		}()
+	}
+	return target, nil
+}
+
+// handshake establishes the ssh connection over conn, conn is closed if it fails,
+// or it's not done within dialTimeout, e.g. the host accepts but never replies.
+func handshake(conn net.Conn, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	type result struct {
+		client *ssh.Client
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		c, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		done <- result{client: ssh.NewClient(c, chans, reqs)}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			conn.Close()
+		}
+		return r.client, r.err
+	case <-time.After(dialTimeout):
+		// the handshake fails once conn is closed.
+		conn.Close()
+		return nil, errors.WithMessagef(ErrorDialTimeout, "address: %s", address)
+	}
+}
+
+// clientConfig builds the ssh client config with all the configured auth methods.
+// If agent is used, the connection to agent is returned and must be closed after handshake.
+func clientConfig(auth common.SSHAuthConfig) (*ssh.ClientConfig, io.Closer, error) {
+	hostKeyCallback, err := hostKeyCallback(auth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		methods   []ssh.AuthMethod
+		agentConn io.Closer
+	)
+	closeAgent := func() {
+		if agentConn != nil {
+			agentConn.Close()
+		}
+	}
+
+	if auth.UseAgent != nil && *auth.UseAgent {
+		conn, err := dialAgent()
+		if err != nil {
+			return nil, nil, err
+		}
+		agentConn = conn
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	if auth.PrivateKey != "" {
+		method, err := privateKeyAuth(auth.PrivateKey, auth.Passphrase)
+		if err != nil {
+			closeAgent()
+			return nil, nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	if auth.Password != "" {
+		password := auth.Password
+		methods = append(methods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range questions {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		)
+	}
+
+	if len(methods) == 0 {
+		return nil, nil, errors.WithMessagef(ErrorNoAuthMethod, "user: %s", auth.User)
+	}
+
+	return &ssh.ClientConfig{
+		User:            auth.User,
+		Auth:            methods,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}, agentConn, nil
+}
+
+// dialAgent connects the ssh-agent listening on SSH_AUTH_SOCK.
+func dialAgent() (net.Conn, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, errors.WithMessage(ErrorNoAuthMethod, "SSH_AUTH_SOCK not set")
+	}
+	return net.Dial("unix", socket)
+}
+
+// privateKeyAuth reads the private key, decrypts it with passphrase if given.
+func privateKeyAuth(path, passphrase string) (ssh.AuthMethod, error) {
+	key, err := ioutil.ReadFile(expandHome(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var signer ssh.Signer
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ssh.PublicKeys(signer), nil
+}
+
+// hostKeyCallback verifies the host key by the policy.
+// known_hosts file is read on every handshake, so keys appended by tofu are seen by later dials.
+func hostKeyCallback(auth common.SSHAuthConfig) (ssh.HostKeyCallback, error) {
+	path := expandHome(auth.KnownHosts)
+
+	switch auth.HostKeyPolicy {
+	case hostKeyPolicyInsecure:
+		return ssh.InsecureIgnoreHostKey(), nil
+	case hostKeyPolicyStrict, hostKeyPolicyTOFU:
+	default:
+		return nil, errors.WithMessagef(ErrorUnknownHostKeyPolicy, "policy: %s", auth.HostKeyPolicy)
+	}
+
+	tofu := auth.HostKeyPolicy == hostKeyPolicyTOFU
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		knownHostsMutex.Lock()
+		defer knownHostsMutex.Unlock()
+
+		if tofu {
+			if err := ensureFile(path); err != nil {
+				return err
+			}
+		}
+
+		callback, err := knownhosts.New(path)
+		if err != nil {
+			return err
+		}
+
+		err = callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		// Want is empty means the host is unknown,
+		// otherwise the host key mismatches, which is never trusted.
+		if !tofu || !errors.As(err, &keyErr) || len(keyErr.Want) > 0 {
+			return err
+		}
+		return appendKnownHost(path, hostname, remote, key)
+	}, nil
+}
+
+// appendKnownHost appends the host key to known_hosts file.
+func appendKnownHost(path, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	addresses := []string{knownhosts.Normalize(hostname)}
+	if remote != nil {
+		if address := knownhosts.Normalize(remote.String()); address != addresses[0] {
+			addresses = append(addresses, address)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintln(f, knownhosts.Line(addresses, key))
+	return err
+}
+
+// ensureFile creates the file and its dir if not exist.
+func ensureFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// expandHome replaces the leading ~ with the home dir of current user.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,248 @@
+package ssh
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func passwordServerConfig(password string) *ssh.ServerConfig {
+	return &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, p []byte) (*ssh.Permissions, error) {
+			if string(p) != password {
+				return nil, ErrorNoAuthMethod
+			}
+			return nil, nil
+		},
+	}
+}
+
+func TestDialHostKeyPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, hostKey := startTestServer(t, passwordServerConfig("secret"))
+	defer listener.Close()
+
+	knownHosts := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+	host := common.SSHHostConfig{
+		Address: listener.Addr().String(),
+		SSHAuthConfig: common.SSHAuthConfig{
+			User:          "pistage",
+			Password:      "secret",
+			KnownHosts:    knownHosts,
+			HostKeyPolicy: hostKeyPolicyStrict,
+		},
+	}
+
+	// strict needs known_hosts.
+	_, err := dialHost(host)
+	assert.Error(err)
+
+	// tofu trusts the unknown host, and records the key.
+	host.HostKeyPolicy = hostKeyPolicyTOFU
+	client, err := dialHost(host)
+	assert.NoError(err)
+	client.Close()
+
+	content, err := ioutil.ReadFile(knownHosts)
+	assert.NoError(err)
+	assert.Contains(string(content), knownhosts.Normalize(host.Address))
+
+	// now strict accepts the recorded key.
+	host.HostKeyPolicy = hostKeyPolicyStrict
+	client, err = dialHost(host)
+	assert.NoError(err)
+	client.Close()
+
+	// host key changed, even tofu rejects.
+	_, other := startTestServer(t, nil)
+	assert.NotEqual(hostKey.Marshal(), other.Marshal())
+	line := knownhosts.Line([]string{knownhosts.Normalize(host.Address)}, other)
+	assert.NoError(ioutil.WriteFile(knownHosts, []byte(line+"\n"), 0600))
+	host.HostKeyPolicy = hostKeyPolicyTOFU
+	_, err = dialHost(host)
+	assert.Error(err)
+
+	// insecure ignores the mismatch.
+	host.HostKeyPolicy = hostKeyPolicyInsecure
+	client, err = dialHost(host)
+	assert.NoError(err)
+	client.Close()
+
+	host.HostKeyPolicy = "trust_all"
+	_, err = dialHost(host)
+	assert.ErrorIs(err, ErrorUnknownHostKeyPolicy)
+
+	host.HostKeyPolicy = hostKeyPolicyInsecure
+	host.Password = ""
+	_, err = dialHost(host)
+	assert.ErrorIs(err, ErrorNoAuthMethod)
+}
+
+func TestDialAuthMethods(t *testing.T) {
+	assert := assert.New(t)
+
+	// keyboard-interactive is answered with password.
+	listener, _ := startTestServer(t, &ssh.ServerConfig{
+		KeyboardInteractiveCallback: func(conn ssh.ConnMetadata, challenge ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := challenge(conn.User(), "", []string{"Password: ", "OTP: "}, []bool{false, false})
+			if err != nil {
+				return nil, err
+			}
+			if len(answers) != 2 || answers[0] != "secret" || answers[1] != "secret" {
+				return nil, ErrorNoAuthMethod
+			}
+			return nil, nil
+		},
+	})
+	defer listener.Close()
+
+	host := common.SSHHostConfig{
+		Address: listener.Addr().String(),
+		SSHAuthConfig: common.SSHAuthConfig{
+			User:          "pistage",
+			Password:      "secret",
+			HostKeyPolicy: hostKeyPolicyInsecure,
+		},
+	}
+	client, err := dialHost(host)
+	assert.NoError(err)
+	client.Close()
+
+	// private key protected by passphrase.
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), []byte("passphrase"), x509.PEMCipherAES256) //nolint:staticcheck
+	assert.NoError(err)
+	keyFile := filepath.Join(t.TempDir(), "id_rsa")
+	assert.NoError(ioutil.WriteFile(keyFile, pem.EncodeToMemory(block), 0600))
+
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	assert.NoError(err)
+	keyListener, _ := startTestServer(t, &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, k ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(k.Marshal()) != string(publicKey.Marshal()) {
+				return nil, ErrorNoAuthMethod
+			}
+			return nil, nil
+		},
+	})
+	defer keyListener.Close()
+
+	host = common.SSHHostConfig{
+		Address: keyListener.Addr().String(),
+		SSHAuthConfig: common.SSHAuthConfig{
+			User:          "pistage",
+			PrivateKey:    keyFile,
+			HostKeyPolicy: hostKeyPolicyInsecure,
+		},
+	}
+	_, err = dialHost(host)
+	assert.Error(err)
+
+	host.Passphrase = "passphrase"
+	client, err = dialHost(host)
+	assert.NoError(err)
+	client.Close()
+}
+
+func TestDialProxyJump(t *testing.T) {
+	assert := assert.New(t)
+
+	bastion, _ := startTestServer(t, passwordServerConfig("bastion"))
+	defer bastion.Close()
+	target, _ := startTestServer(t, passwordServerConfig("target"))
+	defer target.Close()
+
+	config := common.SSHConfig{
+		SSHAuthConfig: common.SSHAuthConfig{
+			User:          "pistage",
+			Password:      "target",
+			HostKeyPolicy: hostKeyPolicyInsecure,
+		},
+		ProxyJump: []common.SSHJumpHostConfig{
+			{Address: bastion.Addr().String(), SSHAuthConfig: common.SSHAuthConfig{Password: "bastion"}},
+		},
+		Hosts: []common.SSHHostConfig{
+			{Address: target.Addr().String()},
+		},
+	}
+
+	hosts := config.GetHosts()
+	assert.Len(hosts, 1)
+	assert.Equal("pistage", hosts[0].ProxyJump[0].User)
+
+	client, err := dialHost(hosts[0])
+	assert.NoError(err)
+	assert.True(healthy(client))
+	client.Close()
+
+	// the target is not reachable with the password of bastion.
+	hosts[0].Password = "bastion"
+	_, err = dialHost(hosts[0])
+	assert.Error(err)
+}
+
+// startSilentServer accepts connections and never replies, like a host stuck before handshake.
+func startSilentServer(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	return listener
+}
+
+func TestDialTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	timeout := dialTimeout
+	dialTimeout = 200 * time.Millisecond
+	defer func() { dialTimeout = timeout }()
+
+	bastion, _ := startTestServer(t, passwordServerConfig("bastion"))
+	defer bastion.Close()
+	silent := startSilentServer(t)
+	defer silent.Close()
+
+	auth := common.SSHAuthConfig{User: "pistage", Password: "bastion", HostKeyPolicy: hostKeyPolicyInsecure}
+
+	// the host itself never replies.
+	_, err := dialHost(common.SSHHostConfig{SSHAuthConfig: auth, Address: silent.Addr().String()})
+	assert.ErrorIs(err, ErrorDialTimeout)
+
+	// the host behind a jump host never replies.
+	_, err = dialHost(common.SSHHostConfig{
+		SSHAuthConfig: auth,
+		Address:       silent.Addr().String(),
+		ProxyJump:     []common.SSHJumpHostConfig{{Address: bastion.Addr().String(), SSHAuthConfig: auth}},
+	})
+	assert.ErrorIs(err, ErrorDialTimeout)
+
+	// the jump host never replies.
+	_, err = dialHost(common.SSHHostConfig{
+		SSHAuthConfig: auth,
+		Address:       bastion.Addr().String(),
+		ProxyJump:     []common.SSHJumpHostConfig{{Address: silent.Addr().String(), SSHAuthConfig: auth}},
+	})
+	assert.ErrorIs(err, ErrorDialTimeout)
+}
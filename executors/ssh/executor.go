@@ -12,6 +12,7 @@ import (
 	"golang.org/x/crypto/ssh"
 
 	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
 	"github.com/projecteru2/pistage/helpers"
 	"github.com/projecteru2/pistage/helpers/command"
 	"github.com/projecteru2/pistage/helpers/variable"
@@ -29,6 +30,8 @@ type SSHJobExecutor struct {
 	store  store.Store
 	config *common.Config
 
+	pool   *hostPool
+	lease  *hostLease
 	client *ssh.Client
 	home   string
 
@@ -38,34 +41,25 @@ type SSHJobExecutor struct {
 	output         io.Writer
 	workingDir     string
 	jobEnvironment map[string]string
-}
 
-// NewEruJobExecutor creates an ERU executor for this job.
-// Since job needs to know its context, pistage is assigned too.
-func NewSSHJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, client *ssh.Client, store store.Store, config *common.Config) (*SSHJobExecutor, error) {
-	// get the current working dir as writable home.
-	session, err := client.NewSession()
-	if err != nil {
-		return nil, err
-	}
-	defer session.Close()
+	// caches are the caches of job, restored in Prepare.
+	caches executors.JobCaches
 
-	// home dir should be $HOME after login
-	out, err := session.Output("echo $HOME")
-	if err != nil {
-		return nil, err
-	}
+	// posts are run in Cleanup, see executors.PostHooks.
+	posts executors.PostHooks
 
-	home := strings.TrimSuffix(string(out), "\n")
-	if len(home) == 0 {
-		home = "/home/" + config.SSH.User
-	}
+	// outputs holds the outputs of KhoriumSteps executed.
+	outputs executors.StepOutputs
+}
 
+// NewSSHJobExecutor creates an SSH executor for this job.
+// Since job needs to know its context, pistage is assigned too.
+// The host is acquired from pool when preparing, and released when cleaning up.
+func NewSSHJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer, pool *hostPool, store store.Store, config *common.Config) (*SSHJobExecutor, error) {
 	return &SSHJobExecutor{
 		store:          store,
 		config:         config,
-		client:         client,
-		home:           home,
+		pool:           pool,
 		job:            job,
 		pistage:        pistage,
 		output:         output,
@@ -97,8 +91,10 @@ func executeCommand(client *ssh.Client, cmd, home string, envs map[string]string
 // Prepare does all the preparations before actually running a job
 func (s *SSHJobExecutor) Prepare(ctx context.Context) error {
 	preparations := []func(context.Context) error{
+		s.acquireHost,
 		s.prepareJobRuntime,
 		s.prepareFileContext,
+		s.restoreCache,
 	}
 	for _, f := range preparations {
 		if err := f(ctx); err != nil {
@@ -108,6 +104,42 @@ func (s *SSHJobExecutor) Prepare(ctx context.Context) error {
 	return nil
 }
 
+// acquireHost acquires a host with all the labels in runs_on from pool,
+// then gets the current working dir as writable home.
+func (s *SSHJobExecutor) acquireHost(ctx context.Context) error {
+	var labels []string
+	if s.job.RunsOn != nil {
+		labels = s.job.RunsOn.Labels
+	}
+
+	lease, err := s.pool.Acquire(ctx, labels)
+	if err != nil {
+		return err
+	}
+	s.lease = lease
+	s.client = lease.client
+
+	session, err := s.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	// home dir should be $HOME after login
+	out, err := session.Output("echo $HOME")
+	if err != nil {
+		return err
+	}
+
+	home := strings.TrimSuffix(string(out), "\n")
+	if len(home) == 0 {
+		home = "/home/" + lease.User()
+	}
+	s.home = home
+	fmt.Fprintf(s.output, "Running on host: %s\n", lease.Address())
+	return nil
+}
+
 // prepareJobRuntime creates a working dir for this job.
 func (s *SSHJobExecutor) prepareJobRuntime(ctx context.Context) error {
 	digest, err := helpers.Sha1HexDigest(fmt.Sprintf("%s:%s", s.pistage.WorkflowIdentifier, s.job.Name))
@@ -132,6 +164,11 @@ func (s *SSHJobExecutor) prepareFileContext(ctx context.Context) error {
 		if fc == nil {
 			continue
 		}
+		// dependent job may run on another host,
+		// files must be copied with the client of this job.
+		if sfc, ok := fc.(*SSHFileCollector); ok {
+			fc = sfc.withClient(s.client)
+		}
 		if err := fc.CopyTo(ctx, s.workingDir, nil); err != nil {
 			return err
 		}
@@ -148,13 +185,25 @@ func (s *SSHJobExecutor) defaultEnvironmentVariables() map[string]string {
 	}
 }
 
-// Execute will execute all steps within this job one by one
+// Execute will execute all steps within this job one by one,
+// caches missed are saved after all steps succeeded.
 func (s *SSHJobExecutor) Execute(ctx context.Context) error {
-	return s.executeSteps(ctx, s.job.Steps)
+	if err := s.executeSteps(ctx, s.job.Steps); err != nil {
+		return err
+	}
+	s.saveCache(ctx)
+	return nil
 }
 
 // executeSteps will execute steps, steps can be steps or rollback_steps
 func (s *SSHJobExecutor) executeSteps(ctx context.Context, steps []*common.Step) error {
+	// composite KhoriumSteps are expanded first,
+	// so only plain steps and plain KhoriumSteps are left.
+	steps, err := executors.ExpandSteps(ctx, s.store, steps, s.pistage.Vars)
+	if err != nil {
+		return err
+	}
+
 	for _, step := range steps {
 		var err error
 		switch step.Uses {
@@ -182,10 +231,10 @@ func (s *SSHJobExecutor) executeSteps(ctx context.Context, steps []*common.Step)
 func (s *SSHJobExecutor) executeStep(ctx context.Context, step *common.Step) error {
 	var (
 		err  error
-		vars map[string]string
+		vars = s.pistage.Vars
 	)
 
-	environment := command.MergeVariables(s.jobEnvironment, step.Environment)
+	environment := command.MergeVariables(command.MergeVariables(s.jobEnvironment, s.outputs.Environment()), step.Environment)
 
 	defer func() {
 		if !errors.Is(err, common.ErrExecutionError) {
@@ -207,7 +256,7 @@ func (s *SSHJobExecutor) executeKhoriumStep(ctx context.Context, step *common.St
 		return err
 	}
 
-	arguments, err := variable.RenderArguments(step.With, step.Environment, map[string]string{})
+	arguments, err := variable.RenderArguments(step.With, command.MergeVariables(s.outputs.Environment(), step.Environment), s.pistage.Vars)
 	if err != nil {
 		return err
 	}
@@ -216,7 +265,7 @@ func (s *SSHJobExecutor) executeKhoriumStep(ctx context.Context, step *common.St
 	if err != nil {
 		return err
 	}
-	envs := command.MergeVariables(s.defaultEnvironmentVariables(), step.Environment)
+	envs := command.MergeVariables(command.MergeVariables(s.defaultEnvironmentVariables(), s.outputs.Environment()), step.Environment)
 	envs = command.MergeVariables(envs, ksEnv)
 
 	// Prepare KhoriumStep environment.
@@ -228,17 +277,27 @@ func (s *SSHJobExecutor) executeKhoriumStep(ctx context.Context, step *common.St
 	khoriumStepWorkingDir := filepath.Join(s.home, sshExecutorKhoriumStepRootWorkingDir, digest)
 	defer s.cleanupDir(khoriumStepWorkingDir)
 
-	fc := NewSSHFileCollector(s.client)
-	fc.SetFiles(ks.Files)
-	if err := fc.CopyTo(ctx, khoriumStepWorkingDir, nil); err != nil {
+	if err := s.copyKhoriumStepFiles(ctx, khoriumStepWorkingDir, ks.Files); err != nil {
 		return err
 	}
 
+	outputFile, err := executors.OutputFile(khoriumStepWorkingDir, step)
+	if err != nil {
+		return err
+	}
+	if len(ks.Outputs) != 0 {
+		envs[executors.KhoriumStepOutputEnv] = outputFile
+	}
+
+	s.posts.Queue(step.Name, ks, envs, khoriumStepWorkingDir)
+
 	// Now we can execute the script written in specification.
 	if err := executeCommand(s.client, ks.Run.Main, khoriumStepWorkingDir, envs, s.output); err != nil {
 		return errors.WithMessagef(common.ErrExecutionError, "exec error: %v", err)
 	}
-	return nil
+	return s.outputs.Collect(ks, step.Name, outputFile, func(cmd string, output io.Writer) error {
+		return executeCommand(s.client, cmd, khoriumStepWorkingDir, envs, output)
+	})
 }
 
 // executeCommands executes cmd with given arguments, environments and variables.
@@ -269,9 +328,34 @@ func (s *SSHJobExecutor) executeCommands(ctx context.Context, cmds []string, arg
 	return nil
 }
 
+// copyKhoriumStepFiles copies files of a KhoriumStep into dir,
+// dir is removed if any of them fails.
+func (s *SSHJobExecutor) copyKhoriumStepFiles(ctx context.Context, dir string, files map[string][]byte) error {
+	fc := NewSSHFileCollector(s.client)
+	fc.SetFiles(files)
+	if err := fc.CopyTo(ctx, dir, nil); err != nil {
+		if cerr := s.cleanupDir(dir); cerr != nil {
+			logrus.WithField("dir", dir).WithError(cerr).Warn("[SSHJobExecutor] error removing dir")
+		}
+		return err
+	}
+	return nil
+}
+
+// executePost executes the post command of a KhoriumStep,
+// the working dir of KhoriumStep is removed afterwards.
+func (s *SSHJobExecutor) executePost(ctx context.Context, hook *executors.PostHook) error {
+	defer s.cleanupDir(hook.WorkingDir)
+
+	if err := executeCommand(s.client, hook.Command, hook.WorkingDir, hook.Environment, s.output); err != nil {
+		return errors.WithMessagef(common.ErrExecutionError, "exec error: %v", err)
+	}
+	return nil
+}
+
 // beforeCleanup collects files
 func (s *SSHJobExecutor) beforeCleanup(ctx context.Context) error {
-	if len(s.job.Files) == 0 {
+	if len(s.job.Files) == 0 || s.client == nil {
 		return nil
 	}
 
@@ -297,18 +381,27 @@ func (s *SSHJobExecutor) cleanup(ctx context.Context) error {
 	return s.cleanupDir(s.workingDir)
 }
 
-// Cleanup does all the cleanup work
+// Cleanup does all the cleanup work,
+// the host is always released even if any cleanup fails.
 func (ls *SSHJobExecutor) Cleanup(ctx context.Context) error {
+	if ls.lease != nil {
+		defer ls.lease.Release()
+	}
+
+	// post commands are executed even if the job fails,
+	// every step of cleanup is executed, the first error is returned.
+	err := ls.posts.Run(ctx, ls.copyKhoriumStepFiles, ls.executePost)
+
 	cleanups := []func(context.Context) error{
 		ls.beforeCleanup,
 		ls.cleanup,
 	}
 	for _, f := range cleanups {
-		if err := f(ctx); err != nil {
-			return err
+		if cerr := f(ctx); cerr != nil && err == nil {
+			err = cerr
 		}
 	}
-	return nil
+	return err
 }
 
 // Rollback is a function can rollback steps by rollback_steps which defined in YAML
@@ -38,6 +38,18 @@ func (s *SSHFileCollector) SetFiles(files map[string][]byte) {
 	s.files = files
 }
 
+// withClient returns a collector holding the same files,
+// but sends them with client.
+func (s *SSHFileCollector) withClient(client *ssh.Client) *SSHFileCollector {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return &SSHFileCollector{
+		files:  s.files,
+		client: client,
+	}
+}
+
 // Collect collects files.
 // For an SSHFileCollector, identifier represents the current working dir,
 // identifier will be used to do a file path join with files.
@@ -154,3 +166,12 @@ func (s *SSHFileCollector) Files() []string {
 	}
 	return files
 }
+
+// getFile returns the content of the file this collector holds.
+func (s *SSHFileCollector) getFile(name string) ([]byte, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	content, ok := s.files[name]
+	return content, ok
+}
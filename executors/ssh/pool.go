@@ -0,0 +1,275 @@
+package ssh
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+const (
+	strategyLeastLoaded = "least_loaded"
+	strategyRoundRobin  = "round_robin"
+)
+
+// healthCheckTimeout is how long to wait for the reply of health check.
+var healthCheckTimeout = 5 * time.Second
+
+var (
+	// ErrorNoHostMatched is returned when no host has all the labels required.
+	ErrorNoHostMatched = errors.New("No SSH host matched")
+
+	// ErrorUnknownStrategy is returned when the host selection strategy is not supported.
+	ErrorUnknownStrategy = errors.New("Unknown SSH host selection strategy")
+)
+
+// dialFunc dials the host and returns a connected client.
+type dialFunc func(host common.SSHHostConfig) (*ssh.Client, error)
+
+// sshHost is a host in pool, with the running jobs counted
+// and the idle connections kept.
+type sshHost struct {
+	config  common.SSHHostConfig
+	running int
+	idle    []*ssh.Client
+}
+
+func (h *sshHost) hasLabels(labels []string) bool {
+	for _, label := range labels {
+		found := false
+		for _, l := range h.config.Labels {
+			if l == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *sshHost) available() bool {
+	return h.config.MaxJobs <= 0 || h.running < h.config.MaxJobs
+}
+
+// hostPool selects hosts for jobs and keeps connections to hosts.
+// A host is acquired before the job runs, and released after the job ends,
+// the connection is put back to pool for the following jobs if it's still healthy.
+type hostPool struct {
+	mutex    sync.Mutex
+	hosts    []*sshHost
+	strategy string
+	maxIdle  int
+	next     int
+	dial     dialFunc
+
+	// released is closed and renewed every time a host is released,
+	// to wake up all the jobs waiting for hosts.
+	released chan struct{}
+	closed   bool
+}
+
+func newHostPool(config common.SSHConfig, dial dialFunc) (*hostPool, error) {
+	if config.Strategy != strategyLeastLoaded && config.Strategy != strategyRoundRobin {
+		return nil, errors.WithMessagef(ErrorUnknownStrategy, "strategy: %s", config.Strategy)
+	}
+
+	var hosts []*sshHost
+	for _, host := range config.GetHosts() {
+		hosts = append(hosts, &sshHost{config: host})
+	}
+	return &hostPool{
+		hosts:    hosts,
+		strategy: config.Strategy,
+		maxIdle:  config.MaxIdleConnsPerHost,
+		dial:     dial,
+		released: make(chan struct{}),
+	}, nil
+}
+
+// hostLease is a host acquired by a job, with a connected client.
+// Release must be called after the job ends.
+type hostLease struct {
+	pool   *hostPool
+	host   *sshHost
+	client *ssh.Client
+	once   sync.Once
+}
+
+// Address returns the address of the host.
+func (l *hostLease) Address() string {
+	return l.host.config.Address
+}
+
+// User returns the user to login the host.
+func (l *hostLease) User() string {
+	return l.host.config.User
+}
+
+// Release puts the connection back to pool and frees the host.
+// It's safe to call Release multiple times.
+func (l *hostLease) Release() {
+	l.once.Do(func() {
+		l.pool.release(l.host, l.client)
+	})
+}
+
+// Acquire selects a host with all the labels, waits until it's available.
+// Returns ErrorNoHostMatched if no host has all the labels.
+func (p *hostPool) Acquire(ctx context.Context, labels []string) (*hostLease, error) {
+	for {
+		host, released, err := p.selectHost(labels)
+		if err != nil {
+			return nil, err
+		}
+		if host == nil {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-released:
+				continue
+			}
+		}
+
+		client, err := p.connect(host)
+		if err != nil {
+			p.release(host, nil)
+			return nil, err
+		}
+		return &hostLease{pool: p, host: host, client: client}, nil
+	}
+}
+
+// selectHost selects an available host by the strategy and counts it as running.
+// If all the matched hosts are busy, nil is returned with a channel
+// to wait for the next release.
+func (p *hostPool) selectHost(labels []string) (*sshHost, <-chan struct{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var (
+		selected *sshHost
+		matched  = false
+		count    = len(p.hosts)
+	)
+	for i := 0; i < count; i++ {
+		index := i
+		if p.strategy == strategyRoundRobin {
+			index = (p.next + i) % count
+		}
+
+		host := p.hosts[index]
+		if !host.hasLabels(labels) {
+			continue
+		}
+		matched = true
+		if !host.available() {
+			continue
+		}
+
+		if p.strategy == strategyRoundRobin {
+			selected = host
+			p.next = (index + 1) % count
+			break
+		}
+		if selected == nil || host.running < selected.running {
+			selected = host
+		}
+	}
+
+	if !matched {
+		return nil, nil, errors.WithMessagef(ErrorNoHostMatched, "labels: %s", strings.Join(labels, ","))
+	}
+	if selected == nil {
+		return nil, p.released, nil
+	}
+	selected.running++
+	return selected, nil, nil
+}
+
+// connect takes an idle connection of host, if it's not healthy,
+// closes it and tries the next one; dials a new connection if no idle one is left.
+func (p *hostPool) connect(host *sshHost) (*ssh.Client, error) {
+	for {
+		p.mutex.Lock()
+		if len(host.idle) == 0 {
+			p.mutex.Unlock()
+			break
+		}
+		client := host.idle[len(host.idle)-1]
+		host.idle = host.idle[:len(host.idle)-1]
+		p.mutex.Unlock()
+
+		if healthy(client) {
+			return client, nil
+		}
+		client.Close()
+	}
+	return p.dial(host.config)
+}
+
+// release frees host, puts client back as idle if there's room
+// and it's still healthy, otherwise closes it.
+// Health is checked without holding the lock, an unresponsive host
+// may take healthCheckTimeout to fail, other jobs should not wait for it.
+func (p *hostPool) release(host *sshHost, client *ssh.Client) {
+	alive := client != nil && healthy(client)
+
+	p.mutex.Lock()
+	host.running--
+	close(p.released)
+	p.released = make(chan struct{})
+	parked := alive && !p.closed && len(host.idle) < p.maxIdle
+	if parked {
+		host.idle = append(host.idle, client)
+	}
+	p.mutex.Unlock()
+
+	if client == nil || parked {
+		return
+	}
+	if err := client.Close(); err != nil {
+		logrus.WithField("host", host.config.Address).WithError(err).Warn("[SSHJobExecutor] error closing connection")
+	}
+}
+
+// Close closes all the idle connections,
+// connections released after are closed instead of kept.
+func (p *hostPool) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.closed = true
+	for _, host := range p.hosts {
+		for _, client := range host.idle {
+			client.Close()
+		}
+		host.idle = nil
+	}
+}
+
+// healthy checks the connection by sending a keepalive request,
+// it's not healthy if no reply comes within healthCheckTimeout.
+// The pending request is abandoned, it fails once the connection is closed.
+func healthy(client *ssh.Client) bool {
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err == nil
+	case <-time.After(healthCheckTimeout):
+		return false
+	}
+}
@@ -0,0 +1,291 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// startTestServer starts an ssh server with config, any client is accepted if config is nil.
+// It replies to global requests, which is enough for health check,
+// and forwards direct-tcpip channels, so it can be used as a jump host.
+func startTestServer(t *testing.T, config *ssh.ServerConfig) (net.Listener, ssh.PublicKey) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	assert.NoError(t, err)
+
+	if config == nil {
+		config = &ssh.ServerConfig{NoClientAuth: true}
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go handleTestChannels(chans)
+				for req := range reqs {
+					req.Reply(true, nil)
+				}
+			}()
+		}
+	}()
+	return listener, signer.PublicKey()
+}
+
+func handleTestChannels(chans <-chan ssh.NewChannel) {
+	for ch := range chans {
+		if ch.ChannelType() != "direct-tcpip" {
+			ch.Reject(ssh.Prohibited, "")
+			continue
+		}
+
+		var payload struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(ch.ExtraData(), &payload); err != nil {
+			ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		conn, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, reqs, err := ch.Accept()
+		if err != nil {
+			conn.Close()
+			continue
+		}
+		go ssh.DiscardRequests(reqs)
+		go func() {
+			defer channel.Close()
+			defer conn.Close()
+			go io.Copy(conn, channel)
+			io.Copy(channel, conn)
+		}()
+	}
+}
+
+// testDial dials the test server whatever the host address is, counts the dials.
+func testDial(address string, dials *int32) dialFunc {
+	return func(host common.SSHHostConfig) (*ssh.Client, error) {
+		atomic.AddInt32(dials, 1)
+		return ssh.Dial("tcp", address, &ssh.ClientConfig{
+			User:            host.User,
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		})
+	}
+}
+
+func TestHostPoolAcquire(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, _ := startTestServer(t, nil)
+	defer listener.Close()
+
+	var dials int32
+	pool, err := newHostPool(common.SSHConfig{
+		SSHAuthConfig:       common.SSHAuthConfig{User: "pistage"},
+		Strategy:            strategyLeastLoaded,
+		MaxIdleConnsPerHost: 1,
+		Hosts: []common.SSHHostConfig{
+			{Address: "a", Labels: []string{"linux"}},
+			{Address: "b", Labels: []string{"linux", "gpu"}, MaxJobs: 1},
+		},
+	}, testDial(listener.Addr().String(), &dials))
+	assert.NoError(err)
+	defer pool.Close()
+
+	ctx := context.Background()
+
+	_, err = pool.Acquire(ctx, []string{"arm"})
+	assert.ErrorIs(err, ErrorNoHostMatched)
+
+	gpu, err := pool.Acquire(ctx, []string{"gpu"})
+	assert.NoError(err)
+	assert.Equal("b", gpu.Address())
+	assert.Equal("pistage", gpu.User())
+
+	// b is busy, a is least loaded.
+	linux, err := pool.Acquire(ctx, []string{"linux"})
+	assert.NoError(err)
+	assert.Equal("a", linux.Address())
+	linux.Release()
+
+	// b is full, wait until it's released.
+	timeout, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = pool.Acquire(timeout, []string{"gpu"})
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	acquired := make(chan *hostLease)
+	go func() {
+		lease, err := pool.Acquire(ctx, []string{"gpu"})
+		assert.NoError(err)
+		acquired <- lease
+	}()
+	time.Sleep(50 * time.Millisecond)
+	gpu.Release()
+	gpu.Release()
+
+	lease := <-acquired
+	assert.Equal("b", lease.Address())
+	// connection released is reused.
+	assert.Equal(gpu.client, lease.client)
+	assert.Equal(int32(2), atomic.LoadInt32(&dials))
+
+	// connections released after pool is closed are not kept.
+	pool.Close()
+	lease.Release()
+	assert.Empty(pool.hosts[1].idle)
+}
+
+// startStalledServer starts an ssh server never replying to global requests,
+// like a host hanging up.
+func startStalledServer(t *testing.T) net.Listener {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	assert.NoError(t, err)
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go handleTestChannels(chans)
+				for range reqs {
+				}
+			}()
+		}
+	}()
+	return listener
+}
+
+func TestHostPoolReleaseUnhealthy(t *testing.T) {
+	assert := assert.New(t)
+
+	listener := startStalledServer(t)
+	defer listener.Close()
+
+	timeout := healthCheckTimeout
+	healthCheckTimeout = 200 * time.Millisecond
+	defer func() { healthCheckTimeout = timeout }()
+
+	var dials int32
+	pool, err := newHostPool(common.SSHConfig{
+		Address:             "a",
+		Strategy:            strategyLeastLoaded,
+		MaxIdleConnsPerHost: 1,
+	}, testDial(listener.Addr().String(), &dials))
+	assert.NoError(err)
+	defer pool.Close()
+
+	ctx := context.Background()
+	lease, err := pool.Acquire(ctx, nil)
+	assert.NoError(err)
+
+	released := make(chan struct{})
+	go func() {
+		lease.Release()
+		close(released)
+	}()
+
+	// the pool is not locked while checking health.
+	time.Sleep(50 * time.Millisecond)
+	another, err := pool.Acquire(ctx, nil)
+	assert.NoError(err)
+	select {
+	case <-released:
+		assert.Fail("released before health check times out")
+	default:
+	}
+
+	// the unhealthy connection is closed instead of kept.
+	<-released
+	assert.Empty(pool.hosts[0].idle)
+	assert.Error(lease.client.Wait())
+	another.Release()
+}
+
+func TestHostPoolRoundRobin(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, _ := startTestServer(t, nil)
+	defer listener.Close()
+
+	var dials int32
+	pool, err := newHostPool(common.SSHConfig{
+		Address:             "a",
+		Strategy:            strategyRoundRobin,
+		MaxIdleConnsPerHost: 1,
+		Hosts: []common.SSHHostConfig{
+			{Address: "b"},
+			{Address: "c"},
+		},
+	}, testDial(listener.Addr().String(), &dials))
+	assert.NoError(err)
+	defer pool.Close()
+
+	var addresses []string
+	for i := 0; i < 4; i++ {
+		lease, err := pool.Acquire(context.Background(), nil)
+		assert.NoError(err)
+		addresses = append(addresses, lease.Address())
+		lease.Release()
+	}
+	assert.Equal([]string{"a", "b", "c", "a"}, addresses)
+	assert.Equal(int32(3), atomic.LoadInt32(&dials))
+
+	// connections not healthy are dropped, a new one is dialed.
+	for _, host := range pool.hosts {
+		for _, client := range host.idle {
+			client.Close()
+		}
+	}
+	lease, err := pool.Acquire(context.Background(), nil)
+	assert.NoError(err)
+	assert.Equal("b", lease.Address())
+	assert.Equal(int32(4), atomic.LoadInt32(&dials))
+	lease.Release()
+
+	_, err = newHostPool(common.SSHConfig{Strategy: "random"}, dialHost)
+	assert.ErrorIs(err, ErrorUnknownStrategy)
+}
@@ -1,52 +1,51 @@
 package ssh
 
 import (
+	"context"
 	"io"
-	"io/ioutil"
 
 	"github.com/projecteru2/pistage/common"
 	"github.com/projecteru2/pistage/executors"
 	"github.com/projecteru2/pistage/store"
-
-	"golang.org/x/crypto/ssh"
 )
 
 type SSHJobExecutorProvider struct {
 	config *common.Config
 	store  store.Store
+	pool   *hostPool
 }
 
-func NewSSHJobExecutorProvider(config *common.Config, store store.Store) (*SSHJobExecutorProvider, error) {
-	return &SSHJobExecutorProvider{
+// NewSSHJobExecutorProvider creates an SSHJobExecutorProvider,
+// the connections kept are closed when ctx is done.
+func NewSSHJobExecutorProvider(ctx context.Context, config *common.Config, store store.Store) (*SSHJobExecutorProvider, error) {
+	pool, err := newHostPool(config.SSH, dialHost)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SSHJobExecutorProvider{
 		config: config,
 		store:  store,
-	}, nil
+		pool:   pool,
+	}
+	go func() {
+		<-ctx.Done()
+		s.Close()
+	}()
+	return s, nil
+}
+
+// Close closes the connections kept by the host pool.
+func (s *SSHJobExecutorProvider) Close() {
+	s.pool.Close()
 }
 
 func (s *SSHJobExecutorProvider) GetName() string {
 	return "ssh"
 }
 
+// GetJobExecutor returns an executor for job,
+// the host is not selected until the executor prepares.
 func (s *SSHJobExecutorProvider) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
-	key, err := ioutil.ReadFile(s.config.SSH.PrivateKey)
-	if err != nil {
-		return nil, err
-	}
-
-	singer, err := ssh.ParsePrivateKey(key)
-	if err != nil {
-		return nil, err
-	}
-
-	client, err := ssh.Dial("tcp", s.config.SSH.Address, &ssh.ClientConfig{
-		User: s.config.SSH.User,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(singer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	})
-	if err != nil {
-		return nil, err
-	}
-	return NewSSHJobExecutor(job, pistage, output, client, s.store, s.config)
+	return NewSSHJobExecutor(job, pistage, output, s.pool, s.store, s.config)
 }
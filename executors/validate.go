@@ -0,0 +1,67 @@
+package executors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/helpers/variable"
+	"github.com/projecteru2/pistage/store"
+)
+
+// ValidateKhoriumSteps checks the inputs of all KhoriumSteps used by steps,
+// composites are expanded first, so the steps they're made of are checked too.
+// It's called before any workload is created, errors of all invalid inputs are returned together,
+// warnings are the deprecation messages of inputs given.
+// Inputs referencing outputs of other steps are only known when executing, so they're not checked.
+// vars are the values of workflow inputs, used when rendering arguments.
+func ValidateKhoriumSteps(ctx context.Context, store store.Store, steps []*common.Step, vars map[string]string) ([]string, error) {
+	steps, err := ExpandSteps(ctx, store, steps, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		warnings []string
+		errs     common.InputErrors
+	)
+	for _, step := range steps {
+		if step.Uses == "" {
+			continue
+		}
+		ks, err := store.GetRegisteredKhoriumStep(ctx, step.Uses)
+		if err != nil {
+			return nil, err
+		}
+
+		arguments, err := variable.RenderArguments(step.With, step.Environment, vars)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, ks.Deprecations(arguments)...)
+
+		if _, err := withoutOutputInputs(ks, step).ResolveInputs(arguments); err != nil {
+			errs = append(errs, errors.WithMessagef(err, "step: %s", step.Name))
+		}
+	}
+
+	if len(errs) != 0 {
+		return warnings, errs
+	}
+	return warnings, nil
+}
+
+// withoutOutputInputs returns a copy of ks without the inputs step gives with outputs of other steps.
+func withoutOutputInputs(ks *common.KhoriumStep, step *common.Step) *common.KhoriumStep {
+	inputs := map[string]*common.KhoriumStepInput{}
+	for name, input := range ks.Inputs {
+		if !strings.Contains(step.With[name], "KHORIUMSTEP_OUTPUT_") {
+			inputs[name] = input
+		}
+	}
+	copied := *ks
+	copied.Inputs = inputs
+	return &copied
+}
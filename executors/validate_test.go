@@ -0,0 +1,51 @@
+package executors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func TestValidateKhoriumSteps(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	deploy := &common.KhoriumStep{
+		Name: "deploy",
+		Inputs: map[string]*common.KhoriumStepInput{
+			"replicas": {Type: common.InputTypeNumber, Required: true},
+			"token":    {Deprecated: "use secrets instead"},
+		},
+		Run: &common.KhoriumStepRun{Main: "deploy"},
+	}
+	s := &mapStore{steps: map[string]*common.KhoriumStep{
+		"deploy":  deploy,
+		"release": composite("release", &common.Step{Name: "deploy", Uses: "deploy", With: map[string]string{"replicas": "two"}}),
+	}}
+
+	warnings, err := ValidateKhoriumSteps(ctx, s, []*common.Step{
+		{Name: "plain", Run: []string{"true"}},
+		{Name: "first", Uses: "deploy", With: map[string]string{"replicas": "{{ env.REPLICAS }}", "token": "x"}, Environment: map[string]string{"REPLICAS": "3"}},
+		{Name: "second", Uses: "deploy"},
+		{Name: "release", Uses: "release"},
+		{Name: "output", Uses: "deploy", With: map[string]string{"replicas": "{{ env.KHORIUMSTEP_OUTPUT_BUILD_REPLICAS }}"}},
+	}, nil)
+	assert.Equal([]string{"input token of deploy is deprecated: use secrets instead"}, warnings)
+	assert.ErrorIs(err, common.ErrorInputIsRequired)
+	assert.ErrorIs(err, common.ErrorInputIsInvalid)
+	assert.Len(err.(common.InputErrors), 2)
+	assert.Contains(err.Error(), "step: second")
+	assert.Contains(err.Error(), "step: release/deploy")
+}
+
+func TestParseOutputs(t *testing.T) {
+	assert := assert.New(t)
+
+	ks := &common.KhoriumStep{Outputs: map[string]*common.KhoriumStepOutput{"version": {}, "url": {}}}
+	outputs := ParseOutputs(ks, []byte("version=1.0\nurl=http://a?b=c\nother=x\nbroken\nversion=1.1\n"))
+	assert.Equal(map[string]string{"version": "1.1", "url": "http://a?b=c"}, outputs)
+	assert.Equal("KHORIUMSTEP_OUTPUT_CI_BUILD_GO_IMAGE_TAG", outputEnvironmentName("ci/build-go", "image.tag"))
+}
@@ -5,36 +5,38 @@ import (
 	"strings"
 
 	"github.com/flosch/pongo2/v4"
+
+	"github.com/projecteru2/pistage/helpers/variable"
 )
 
 // RenderCommand renders commandTemplate with the given arguments using Jinja
 // "env" and "vars" will be injected into context and render the template,
 // if they are also defined in arguments, arguments will be overridden.
+// $env and $vars can also be used, they're the same as env and vars.
+// Arguments are rendered before commandTemplate, every template is rendered only once,
+// so values of arguments, env and vars are never parsed as templates.
 func RenderCommand(commandTemplate string, arguments, env, vars map[string]string) (string, error) {
-	tmpl, err := pongo2.FromString(commandTemplate)
-	if err != nil {
-		return "", err
-	}
-
-	context := pongo2.Context{}
+	rendered := make(map[string]string, len(arguments))
 	for k, v := range arguments {
-		context[k] = v
+		o, err := render(v, arguments, env, vars)
+		if err != nil {
+			return "", err
+		}
+		rendered[k] = o
 	}
-	context["vars"] = vars
-	context["env"] = env
+	return render(commandTemplate, rendered, env, vars)
+}
 
-	// first render, replace arguments in template
-	out, err := tmpl.Execute(context)
+func render(t string, arguments, env, vars map[string]string) (string, error) {
+	tmpl, err := pongo2.FromString(variable.ReplaceVariables(t))
 	if err != nil {
 		return "", err
 	}
 
-	// second render, replace vars and env in arguments
-	tmpl2, err := pongo2.FromString(out)
-	if err != nil {
-		return "", err
-	}
-	return tmpl2.Execute(context)
+	context := variable.BuildTemplateContext(arguments, env, vars)
+	context["vars"] = vars
+	context["env"] = env
+	return tmpl.Execute(context)
 }
 
 var shell = `{% for cmd in commands %}{{ cmd | safe }}
@@ -98,3 +100,50 @@ func MergeVariables(lowerPriority, higherPriority map[string]string) map[string]
 	}
 	return m
 }
+
+// RenderCacheKey renders the cache key template.
+// hashFiles is injected into context, it should return the digest of
+// the content of the files matching the given patterns.
+func RenderCacheKey(keyTemplate string, env map[string]string, hashFiles func(patterns ...string) (string, error)) (string, error) {
+	tmpl, err := pongo2.FromString(keyTemplate)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Execute(pongo2.Context{
+		"env":       env,
+		"hashFiles": hashFiles,
+	})
+}
+
+// HashFilesCommand returns the shell command to calculate sha256 digest
+// of all the files matching patterns, patterns are shell globs relative
+// to the working dir.
+// Files are concatenated in the order of patterns, files not found are ignored.
+func HashFilesCommand(patterns []string) string {
+	return fmt.Sprintf(`for f in %s; do [ -f "$f" ] && cat "$f"; done | sha256sum | cut -d ' ' -f 1`, strings.Join(patterns, " "))
+}
+
+// CacheArchiveCommand returns the shell command to archive paths into a tarball.
+// Relative paths are relative to the working dir, paths don't exist are ignored.
+// All paths are stored relative to /, so the tarball can be extracted
+// by CacheExtractCommand regardless of the working dir.
+func CacheArchiveCommand(archive string, paths []string) string {
+	return fmt.Sprintf(`paths=""
+for p in %s; do
+  case "$p" in /*) ;; *) p="$PWD/$p" ;; esac
+  [ -e "$p" ] && paths="$paths ${p#/}"
+done
+tar -czf '%s' -C / $paths`, strings.Join(paths, " "), archive)
+}
+
+// CacheExtractCommand returns the shell command to extract the tarball
+// created by CacheArchiveCommand, the tarball is removed after extraction.
+func CacheExtractCommand(archive string) string {
+	return fmt.Sprintf("tar -xzf '%s' -C / && rm -f '%s'", archive, archive)
+}
+
+// CacheRemoveCommand returns the shell command to remove the tarball
+// created by CacheArchiveCommand.
+func CacheRemoveCommand(archive string) string {
+	return fmt.Sprintf("rm -f '%s'", archive)
+}
@@ -36,4 +36,37 @@ func TestRenderCommand(t *testing.T) {
 	o7, err := RenderCommand("{{a}} {{env.TEST}} {{vars.b | default_if_none: 'xxx'}}", map[string]string{"a": "testa"}, map[string]string{"TEST": "notest"}, nil)
 	assert.NoError(err)
 	assert.Equal(o7, "testa notest xxx")
+
+	o8, err := RenderCommand("{{ $env.GOOS }} {{ $vars.version | upper }}", nil, map[string]string{"GOOS": "linux"}, map[string]string{"version": "v1"})
+	assert.NoError(err)
+	assert.Equal(o8, "linux V1")
+
+	// values of vars, env and arguments are never parsed as templates
+	o9, err := RenderCommand("echo {{ vars.a }} {{ env.B }} {{ c }}", map[string]string{"c": "{{ vars.c }}"}, map[string]string{"B": "{% if"}, map[string]string{"a": "{{ secrets.X }}", "c": "{{ env.B }} & 'd'"})
+	assert.NoError(err)
+	assert.Equal(o9, "echo {{ secrets.X }} {% if {{ env.B }} & 'd'")
+}
+
+func TestRenderCacheKey(t *testing.T) {
+	assert := assert.New(t)
+
+	var patterns []string
+	hashFiles := func(p ...string) (string, error) {
+		patterns = p
+		return "digest", nil
+	}
+
+	k1, err := RenderCacheKey("go-{{ hashFiles('go.sum') }}", nil, hashFiles)
+	assert.NoError(err)
+	assert.Equal(k1, "go-digest")
+	assert.Equal(patterns, []string{"go.sum"})
+
+	k2, err := RenderCacheKey("{{ env.GOOS }}-npm-{{ hashFiles('package-lock.json', 'yarn.lock') }}", map[string]string{"GOOS": "linux"}, hashFiles)
+	assert.NoError(err)
+	assert.Equal(k2, "linux-npm-digest")
+	assert.Equal(patterns, []string{"package-lock.json", "yarn.lock"})
+
+	k3, err := RenderCacheKey("static", nil, hashFiles)
+	assert.NoError(err)
+	assert.Equal(k3, "static")
 }
@@ -8,12 +8,22 @@ import (
 )
 
 var (
-	varsRe = regexp.MustCompile(`(?U){{\s*(\$env|\$vars).*}}`)
+	varsRe     = regexp.MustCompile(`(?U){{\s*(\$env|\$vars).*}}`)
+	inputsRe   = regexp.MustCompile(`{{\s*inputs\.([A-Za-z0-9_-]+)\s*}}`)
+	secretsRe  = regexp.MustCompile(`{{\s*secrets\.([A-Za-z0-9_-]+)\s*}}`)
+	varsNameRe = regexp.MustCompile(`{{\s*vars\.([A-Za-z0-9_-]+)\s*}}`)
+	blockRe    = regexp.MustCompile(`(?s){[{%].*?[}%]}`)
+	varsRefRe  = regexp.MustCompile(`\$?\bvars\.([A-Za-z0-9_]+)`)
 
 	pistageEnvVarName  = "__pistage_env__"
 	pistageVarsVarName = "__pistage_vars__"
 )
 
+// templates here are commands and arguments of shell, not html.
+func init() {
+	pongo2.SetAutoescape(false)
+}
+
 // ReplaceVariables replaces variables startswith $ provided by pistage
 // to pistage private variables, which will later be renderred.
 func ReplaceVariables(t string) string {
@@ -25,6 +35,52 @@ func ReplaceVariables(t string) string {
 	})
 }
 
+// RenderInputs replaces {{ inputs.name }} in t with the value of inputs,
+// inputs not given are replaced with empty string.
+// Other templates are kept as is, so they can be rendered later with arguments and environments.
+func RenderInputs(t string, inputs map[string]string) string {
+	return inputsRe.ReplaceAllStringFunc(t, func(m string) string {
+		return inputs[inputsRe.FindStringSubmatch(m)[1]]
+	})
+}
+
+// SecretNames returns the names of secrets referenced by {{ secrets.NAME }} in t.
+func SecretNames(t string) []string {
+	var names []string
+	for _, m := range secretsRe.FindAllStringSubmatch(t, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// RenderSecrets replaces {{ secrets.NAME }} in t with the value of secrets.
+// Other templates are kept as is, like RenderInputs.
+func RenderSecrets(t string, secrets map[string]string) string {
+	return secretsRe.ReplaceAllStringFunc(t, func(m string) string {
+		return secrets[secretsRe.FindStringSubmatch(m)[1]]
+	})
+}
+
+// RenderVars replaces {{ vars.NAME }} in t with the value of vars.
+// Only plain references are replaced, expressions like {{ vars.NAME | upper }} are left to pongo2.
+func RenderVars(t string, vars map[string]string) string {
+	return varsNameRe.ReplaceAllStringFunc(t, func(m string) string {
+		return vars[varsNameRe.FindStringSubmatch(m)[1]]
+	})
+}
+
+// VarNames returns the names of vars referenced by t, in plain references like {{ vars.NAME }},
+// or in expressions like {{ $vars.NAME | upper }} and {% if vars.NAME %}.
+func VarNames(t string) []string {
+	var names []string
+	for _, block := range blockRe.FindAllString(t, -1) {
+		for _, m := range varsRefRe.FindAllStringSubmatch(block, -1) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
 // BuildTemplateContext uses arguments, env, and vars to build pongo2 context
 // for rendering the template
 func BuildTemplateContext(arguments, envs, vars map[string]string) pongo2.Context {
@@ -24,3 +24,27 @@ func TestBuildTemplateContext(t *testing.T) {
 	assert.Equal(c[pistageEnvVarName].(map[string]string)["X"], "1")
 	assert.Equal(c[pistageVarsVarName].(map[string]string)["v1"], "c1")
 }
+
+func TestRenderInputs(t *testing.T) {
+	assert := assert.New(t)
+
+	r := RenderInputs("go build {{ inputs.pkg }} -o {{inputs.out}} {{ inputs.missing }}{{ $env.HOME }}", map[string]string{"pkg": "./...", "out": "bin"})
+	assert.Equal("go build ./... -o bin {{ $env.HOME }}", r)
+}
+
+func TestRenderSecrets(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl := "docker login -u {{ secrets.USER }} -p {{secrets.PASSWORD}} {{ env.REGISTRY }}"
+	assert.Equal([]string{"USER", "PASSWORD"}, SecretNames(tmpl))
+
+	r := RenderSecrets(tmpl, map[string]string{"USER": "tonic", "PASSWORD": "p@ss"})
+	assert.Equal("docker login -u tonic -p p@ss {{ env.REGISTRY }}", r)
+}
+
+func TestVarNames(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl := "deploy {{ vars.version }} {{ $vars.region | upper }} {% if vars.canary %}--canary{% endif %} vars.plain {{ env.vars }}"
+	assert.Equal([]string{"version", "region", "canary"}, VarNames(tmpl))
+}
@@ -11,6 +11,8 @@ CREATE TABLE `pistage_run_tab` (
   `workflow_identifier` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
   `snapshot_version` bigint(20) unsigned NOT NULL,
   `run_status` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `vars` text COLLATE utf8mb4_unicode_ci,
+  `queue_time` bigint(20) unsigned NOT NULL DEFAULT 0,
   PRIMARY KEY (`id`),
   KEY `uk_run` (`workflow_identifier`,`create_time`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -43,6 +45,7 @@ CREATE TABLE `job_run_tab` (
   `pistage_run_id` bigint(20) unsigned NOT NULL,
   `job_name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
   `run_status` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `prepare_time` bigint(20) unsigned NOT NULL DEFAULT 0,
   PRIMARY KEY (`id`),
   UNIQUE KEY `uk_job_run` (`pistage_run_id`,`job_name`),
   KEY `idx_job_run` (`workflow_identifier`,`job_name`,`create_time`)
@@ -50,6 +53,22 @@ CREATE TABLE `job_run_tab` (
 /*!40101 SET character_set_client = @saved_cs_client */;
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `step_run_tab` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `create_time` bigint(20) unsigned NOT NULL,
+  `job_run_id` bigint(20) unsigned NOT NULL,
+  `step_name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `uses` varchar(1024) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `commit` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `digest` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `signed_by` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `rollback` tinyint(1) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_step_run` (`job_run_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
 CREATE TABLE `uuid_tab` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
   `create_time` bigint(20) unsigned NOT NULL,
@@ -0,0 +1,29 @@
+package secrets
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// EnvProvider provides secrets from environment variables of pistage.
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates an EnvProvider.
+func NewEnvProvider(config common.SecretsEnvConfig) *EnvProvider {
+	return &EnvProvider{prefix: config.Prefix}
+}
+
+// Get implements SecretProvider, secret NAME is read from environment variable prefix+NAME.
+func (e *EnvProvider) Get(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(e.prefix + name)
+	if !ok {
+		return "", errors.WithMessagef(ErrorSecretNotFound, "secret: %s", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// ErrorBadSecretsFile is returned when the secrets file can't be decrypted or parsed.
+var ErrorBadSecretsFile = errors.New("Bad secrets file")
+
+// FileProvider provides secrets from a file encrypted with AES-256-GCM.
+// The file is read every time, so it can be replaced without restarting pistage.
+type FileProvider struct {
+	path string
+	key  []byte
+}
+
+// NewFileProvider creates a FileProvider, the key is read from KeyFile.
+func NewFileProvider(config common.SecretsFileConfig) (*FileProvider, error) {
+	key, err := ReadKey(config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &FileProvider{path: config.Path, key: key}, nil
+}
+
+// Get implements SecretProvider.
+func (f *FileProvider) Get(ctx context.Context, name string) (string, error) {
+	content, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := Open(f.key, content)
+	if err != nil {
+		return "", err
+	}
+
+	secrets := map[string]string{}
+	if err := yaml.Unmarshal(plaintext, &secrets); err != nil {
+		return "", errors.WithMessagef(ErrorBadSecretsFile, "%v", err)
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", errors.WithMessagef(ErrorSecretNotFound, "secret: %s", name)
+	}
+	return value, nil
+}
+
+// ReadKey reads the base64 encoded 32 bytes key from file.
+func ReadKey(file string) ([]byte, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "key file: %s", file)
+	}
+	if len(key) != 32 {
+		return nil, errors.Errorf("key in %s is %d bytes, 32 bytes expected", file, len(key))
+	}
+	return key, nil
+}
+
+// Seal encrypts plaintext with key, the result is the base64 encoded nonce followed by ciphertext.
+func Seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Open decrypts content sealed by Seal.
+func Open(key, content []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, errors.WithMessagef(ErrorBadSecretsFile, "%v", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.WithMessage(ErrorBadSecretsFile, "content too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.WithMessagef(ErrorBadSecretsFile, "%v", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
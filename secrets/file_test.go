@@ -0,0 +1,55 @@
+package secrets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func TestFileProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "pistage-secrets")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	assert.NoError(err)
+	keyFile := filepath.Join(dir, "key")
+	assert.NoError(ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+
+	sealed, err := Seal(key, []byte("REGISTRY_PASSWORD: s3cr3t\nTOKEN: t0k3n\n"))
+	assert.NoError(err)
+	assert.NotContains(string(sealed), "s3cr3t")
+	file := filepath.Join(dir, "secrets.enc")
+	assert.NoError(ioutil.WriteFile(file, sealed, 0600))
+
+	provider, err := NewFileProvider(common.SecretsFileConfig{Path: file, KeyFile: keyFile})
+	assert.NoError(err)
+
+	value, err := provider.Get(context.Background(), "REGISTRY_PASSWORD")
+	assert.NoError(err)
+	assert.Equal("s3cr3t", value)
+
+	_, err = provider.Get(context.Background(), "MISSING")
+	assert.True(errors.Is(err, ErrorSecretNotFound))
+
+	// a different key can't open the file
+	other := make([]byte, 32)
+	_, err = Open(other, sealed)
+	assert.True(errors.Is(err, ErrorBadSecretsFile))
+
+	assert.NoError(ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key[:16])), 0600))
+	_, err = NewFileProvider(common.SecretsFileConfig{Path: file, KeyFile: keyFile})
+	assert.Error(err)
+}
@@ -0,0 +1,115 @@
+package secrets
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/helpers/variable"
+)
+
+var (
+	// ErrorSecretNotFound is returned when no provider has the secret.
+	ErrorSecretNotFound = errors.New("Secret not found")
+
+	// ErrorUnknownSecretProvider is returned when a provider in config is not supported.
+	ErrorUnknownSecretProvider = errors.New("Unknown secret provider")
+)
+
+// SecretProvider provides the values of secrets by names.
+// ErrorSecretNotFound should be returned if the secret doesn't exist,
+// so the next provider can be asked.
+type SecretProvider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// Chain asks providers in order, the first one having the secret wins.
+type Chain []SecretProvider
+
+// Get implements SecretProvider.
+func (c Chain) Get(ctx context.Context, name string) (string, error) {
+	for _, provider := range c {
+		value, err := provider.Get(ctx, name)
+		if errors.Is(err, ErrorSecretNotFound) {
+			continue
+		}
+		return value, err
+	}
+	return "", errors.WithMessagef(ErrorSecretNotFound, "secret: %s", name)
+}
+
+// NewSecretProvider creates the providers in config, chained in order.
+func NewSecretProvider(config common.SecretsConfig) (SecretProvider, error) {
+	var chain Chain
+	for _, name := range config.Providers {
+		switch name {
+		case "env":
+			chain = append(chain, NewEnvProvider(config.Env))
+		case "file":
+			provider, err := NewFileProvider(config.File)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, provider)
+		case "vault":
+			provider, err := NewVaultProvider(config.Vault)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, provider)
+		default:
+			return nil, errors.WithMessagef(ErrorUnknownSecretProvider, "provider: %s", name)
+		}
+	}
+	return chain, nil
+}
+
+// ResolvePistage returns a copy of p with all {{ secrets.NAME }} replaced by values from provider,
+// along with the values resolved, so they can be masked in logs.
+// p itself is never changed, so its content, which is saved as snapshot, keeps only the references.
+// p is returned as is if it references no secrets.
+func ResolvePistage(ctx context.Context, provider SecretProvider, p *common.Pistage) (*common.Pistage, []string, error) {
+	names := referencedSecrets(p)
+	if len(names) == 0 {
+		return p, nil, nil
+	}
+	if provider == nil {
+		provider = Chain{}
+	}
+
+	secrets := map[string]string{}
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		value, err := provider.Get(ctx, name)
+		if err != nil {
+			return nil, nil, errors.WithMessagef(err, "secret: %s", name)
+		}
+		secrets[name] = value
+		values = append(values, value)
+	}
+
+	resolved := p.Map(func(s string) string { return variable.RenderSecrets(s, secrets) })
+	return resolved, values, nil
+}
+
+// referencedSecrets returns the sorted names of secrets referenced by p.
+func referencedSecrets(p *common.Pistage) []string {
+	set := map[string]struct{}{}
+	collect := func(s string) string {
+		for _, name := range variable.SecretNames(s) {
+			set[name] = struct{}{}
+		}
+		return s
+	}
+
+	p.Map(collect)
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
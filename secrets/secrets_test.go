@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+type mapProvider map[string]string
+
+func (m mapProvider) Get(ctx context.Context, name string) (string, error) {
+	value, ok := m[name]
+	if !ok {
+		return "", errors.WithMessagef(ErrorSecretNotFound, "secret: %s", name)
+	}
+	return value, nil
+}
+
+func TestChain(t *testing.T) {
+	assert := assert.New(t)
+
+	os.Setenv("PISTAGE_TEST_SECRET_TOKEN", "from-env")
+	defer os.Unsetenv("PISTAGE_TEST_SECRET_TOKEN")
+
+	chain := Chain{
+		mapProvider{"PASSWORD": "first"},
+		NewEnvProvider(common.SecretsEnvConfig{Prefix: "PISTAGE_TEST_SECRET_"}),
+		mapProvider{"PASSWORD": "second", "USER": "tonic"},
+	}
+	ctx := context.Background()
+
+	value, err := chain.Get(ctx, "PASSWORD")
+	assert.NoError(err)
+	assert.Equal("first", value)
+
+	value, err = chain.Get(ctx, "TOKEN")
+	assert.NoError(err)
+	assert.Equal("from-env", value)
+
+	value, err = chain.Get(ctx, "USER")
+	assert.NoError(err)
+	assert.Equal("tonic", value)
+
+	_, err = chain.Get(ctx, "MISSING")
+	assert.True(errors.Is(err, ErrorSecretNotFound))
+
+	_, err = NewSecretProvider(common.SecretsConfig{Providers: []string{"keychain"}})
+	assert.True(errors.Is(err, ErrorUnknownSecretProvider))
+}
+
+func TestResolvePistage(t *testing.T) {
+	a := assert.New(t)
+
+	p := &common.Pistage{
+		WorkflowIdentifier: "w",
+		Environment:        map[string]string{"REGISTRY": "harbor"},
+		Jobs: map[string]*common.Job{
+			"job1": {
+				Name:        "job1",
+				Environment: map[string]string{"TOKEN": "{{ secrets.TOKEN }}"},
+				Steps: []*common.Step{
+					{
+						Name: "login",
+						Run:  []string{"docker login -u {{ dockerusername }} -p {{ dockerpassword }} {{ env.REGISTRY }}"},
+						With: map[string]string{"dockerusername": "tonic", "dockerpassword": "{{ secrets.REGISTRY_PASSWORD }}"},
+					},
+				},
+			},
+		},
+	}
+	a.NoError(p.GenerateHash())
+	content := string(p.Content)
+
+	resolved, values, err := ResolvePistage(context.Background(), mapProvider{"TOKEN": "t0k3n", "REGISTRY_PASSWORD": "s3cr3t"}, p)
+	a.NoError(err)
+	a.Equal([]string{"s3cr3t", "t0k3n"}, values)
+	a.Equal("t0k3n", resolved.Jobs["job1"].Environment["TOKEN"])
+	a.Equal("s3cr3t", resolved.Jobs["job1"].Steps[0].With["dockerpassword"])
+	a.Equal(p.Jobs["job1"].Steps[0].Run, resolved.Jobs["job1"].Steps[0].Run)
+
+	// the original pistage and its content only have references
+	a.Equal("{{ secrets.REGISTRY_PASSWORD }}", p.Jobs["job1"].Steps[0].With["dockerpassword"])
+	a.Equal(content, string(p.Content))
+	a.NotContains(string(resolved.Content), "s3cr3t")
+
+	_, _, err = ResolvePistage(context.Background(), mapProvider{"TOKEN": "t0k3n"}, p)
+	a.True(errors.Is(err, ErrorSecretNotFound))
+
+	_, _, err = ResolvePistage(context.Background(), nil, p)
+	a.True(errors.Is(err, ErrorSecretNotFound))
+
+	plain := &common.Pistage{Jobs: map[string]*common.Job{"job1": {}}}
+	same, values, err := ResolvePistage(context.Background(), nil, plain)
+	a.NoError(err)
+	a.Nil(values)
+	a.True(same == plain)
+
+	// vars rendered after secrets never reference secrets
+	p.Vars = map[string]string{"user": "{{ secrets.TOKEN }}"}
+	p.Jobs["job1"].Environment["USER"] = "{{ vars.user }}"
+	resolved, values, err = ResolvePistage(context.Background(), mapProvider{"TOKEN": "t0k3n", "REGISTRY_PASSWORD": "s3cr3t"}, p)
+	a.NoError(err)
+	a.Equal([]string{"s3cr3t", "t0k3n"}, values)
+	a.Equal("{{ secrets.TOKEN }}", resolved.RenderVars().Jobs["job1"].Environment["USER"])
+}
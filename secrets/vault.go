@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// ErrorVaultRequestFailed is returned when vault responds with an unexpected status.
+var ErrorVaultRequestFailed = errors.New("Vault request failed")
+
+// VaultProvider provides secrets from a Vault compatible KV version 2 engine,
+// all secrets are keys of the one secret at path under mount.
+type VaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	mount     string
+	path      string
+	client    *http.Client
+}
+
+// NewVaultProvider creates a VaultProvider, token is read from TokenFile if it's not given.
+func NewVaultProvider(config common.SecretsVaultConfig) (*VaultProvider, error) {
+	token := config.Token
+	if token == "" && config.TokenFile != "" {
+		content, err := ioutil.ReadFile(config.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(content))
+	}
+	return &VaultProvider{
+		address:   strings.TrimSuffix(config.Address, "/"),
+		token:     token,
+		namespace: config.Namespace,
+		mount:     strings.Trim(config.Mount, "/"),
+		path:      strings.Trim(config.Path, "/"),
+		client:    &http.Client{Timeout: time.Duration(config.TimeoutSecs) * time.Second},
+	}, nil
+}
+
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// Get implements SecretProvider.
+func (v *VaultProvider) Get(ctx context.Context, name string) (string, error) {
+	url := fmt.Sprintf("%s/v1/%s/data/%s", v.address, v.mount, v.path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", errors.WithMessagef(ErrorSecretNotFound, "secret: %s", name)
+	default:
+		return "", errors.WithMessagef(ErrorVaultRequestFailed, "path: %s/%s, status: %d", v.mount, v.path, resp.StatusCode)
+	}
+
+	var kv vaultKVResponse
+	if err := json.NewDecoder(resp.Body).Decode(&kv); err != nil {
+		return "", errors.WithMessagef(ErrorVaultRequestFailed, "path: %s/%s, %v", v.mount, v.path, err)
+	}
+	value, ok := kv.Data.Data[name]
+	if !ok || value == nil {
+		return "", errors.WithMessagef(ErrorSecretNotFound, "secret: %s", name)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,59 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func TestVaultProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/pistage/ci":
+			w.Write([]byte(`{"data":{"data":{"REGISTRY_PASSWORD":"s3cr3t","PORT":8080},"metadata":{"version":3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := common.SecretsVaultConfig{Address: server.URL + "/", Token: "root", Mount: "secret", Path: "/pistage/ci", TimeoutSecs: 5}
+	provider, err := NewVaultProvider(config)
+	assert.NoError(err)
+	ctx := context.Background()
+
+	value, err := provider.Get(ctx, "REGISTRY_PASSWORD")
+	assert.NoError(err)
+	assert.Equal("s3cr3t", value)
+
+	value, err = provider.Get(ctx, "PORT")
+	assert.NoError(err)
+	assert.Equal("8080", value)
+
+	_, err = provider.Get(ctx, "MISSING")
+	assert.True(errors.Is(err, ErrorSecretNotFound))
+
+	config.Path = "pistage/other"
+	provider, err = NewVaultProvider(config)
+	assert.NoError(err)
+	_, err = provider.Get(ctx, "REGISTRY_PASSWORD")
+	assert.True(errors.Is(err, ErrorSecretNotFound))
+
+	config.Token = "wrong"
+	provider, err = NewVaultProvider(config)
+	assert.NoError(err)
+	_, err = provider.Get(ctx, "REGISTRY_PASSWORD")
+	assert.True(errors.Is(err, ErrorVaultRequestFailed))
+}
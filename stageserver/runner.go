@@ -12,6 +12,7 @@ import (
 
 	"github.com/projecteru2/pistage/common"
 	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/secrets"
 	"github.com/projecteru2/pistage/store"
 )
 
@@ -20,6 +21,10 @@ type PistageRunner struct {
 	sync.Mutex
 	ctx context.Context
 
+	// task is the task of pistage, jobs of it are scheduled by scheduler.
+	task      *common.PistageTask
+	scheduler *Scheduler
+
 	// Pistage holds the pistage to execute.
 	p *common.Pistage
 
@@ -35,19 +40,47 @@ type PistageRunner struct {
 	jobRuns map[string]*common.JobRun
 	run     *common.Run
 
+	// secrets resolves {{ secrets.NAME }} in pistage,
+	// masks are the values resolved, they're masked in all logs.
+	secrets secrets.SecretProvider
+	masks   []string
+
 	timeout time.Duration
+	// cleanupTimeout limits the cleanup of each job, see cleanupContext.
+	cleanupTimeout time.Duration
 }
 
-func NewRunner(pt *common.PistageTask, store store.Store, timeoutSecs int) *PistageRunner {
+func NewRunner(pt *common.PistageTask, store store.Store, secrets secrets.SecretProvider, scheduler *Scheduler, timeoutSecs, cleanupTimeoutSecs int) *PistageRunner {
 	return &PistageRunner{
-		p:       pt.Pistage,
-		store:   store,
-		o:       pt.Output,
-		jobRuns: map[string]*common.JobRun{},
-		timeout: time.Duration(timeoutSecs) * time.Second,
+		p:         pt.Pistage,
+		task:      pt,
+		scheduler: scheduler,
+		store:     store,
+		o:         pt.Output,
+		secrets:   secrets,
+		jobRuns:   map[string]*common.JobRun{},
+		timeout:   time.Duration(timeoutSecs) * time.Second,
+
+		cleanupTimeout: time.Duration(cleanupTimeoutSecs) * time.Second,
 	}
 }
 
+// detachedContext keeps the values of Context, but is never canceled with it.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// cleanupContext returns the context to clean up a job with, it has the values of ctx,
+// e.g. the KhoriumSteps pinned, but not its deadline, since post commands and cleanup
+// must run even after the run times out or is canceled. It has its own timeout instead.
+func (r *PistageRunner) cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(detachedContext{ctx}, r.cleanupTimeout)
+}
+
 func (r *PistageRunner) runWithStream(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -66,6 +99,12 @@ func (r *PistageRunner) runWithStream(ctx context.Context) error {
 		return err
 	}
 
+	if err := r.resolveVars(); err != nil {
+		logger.WithError(err).Error("[Stager runWithStream] invalid inputs")
+		return err
+	}
+	p = r.p
+
 	runID, err := r.store.CreatePistageRun(p, version)
 	if err != nil {
 		logger.WithError(err).Error("[Stager runWithStream] fail to create Pistage")
@@ -78,6 +117,8 @@ func (r *PistageRunner) runWithStream(ctx context.Context) error {
 		WorkflowIdentifier: p.WorkflowIdentifier,
 		Start:              common.EpochMillis(),
 		Status:             common.RunStatusRunning,
+		Vars:               p.Vars,
+		Queued:             r.task.Queued,
 	}
 
 	defer func() {
@@ -95,6 +136,26 @@ func (r *PistageRunner) runWithStream(ctx context.Context) error {
 		return err
 	}
 
+	if err := r.resolveSecrets(ctx); err != nil {
+		r.run.Status = common.RunStatusFailed
+		logger.WithError(err).Error("[Stager runWithStream] fail to resolve secrets")
+		return err
+	}
+	p = r.p
+
+	ctx, err = r.pinKhoriumSteps(ctx)
+	if err != nil {
+		r.run.Status = common.RunStatusFailed
+		logger.WithError(err).Error("[Stager runWithStream] fail to prefetch KhoriumSteps")
+		return err
+	}
+
+	if err := r.validateKhoriumSteps(ctx); err != nil {
+		r.run.Status = common.RunStatusFailed
+		logger.WithError(err).Error("[Stager runWithStream] invalid KhoriumStep inputs")
+		return err
+	}
+
 	once := sync.Once{}
 	jobs, finished, finish := p.JobStream()
 	defer once.Do(finish)
@@ -126,6 +187,116 @@ func (r *PistageRunner) runWithStream(ctx context.Context) error {
 	return nil
 }
 
+// resolveVars replaces the pistage to run with a copy having inputs resolved,
+// the resolved values are stored with the Run.
+func (r *PistageRunner) resolveVars() error {
+	p, err := r.p.WithVars()
+	if err != nil {
+		return err
+	}
+	r.p = p
+	return nil
+}
+
+// resolveSecrets replaces the pistage to run with a copy having all secrets resolved,
+// this happens after the snapshot is created, so secrets are never saved.
+// Vars are rendered after, so values of vars are never resolved as secrets.
+func (r *PistageRunner) resolveSecrets(ctx context.Context) error {
+	p, values, err := secrets.ResolvePistage(ctx, r.secrets, r.p)
+	if err != nil {
+		return err
+	}
+	r.p = p.RenderVars()
+	r.masks = values
+	return nil
+}
+
+// newLogTracer creates a LogTracer writing to output, with all secrets masked.
+func (r *PistageRunner) newLogTracer(id string) *common.LogTracer {
+	tracer := common.NewLogTracer(id, r.o)
+	tracer.Mask(r.masks...)
+	return tracer
+}
+
+// pinKhoriumSteps prefetches all the KhoriumSteps used by the pistage,
+// and pins them in the returned context, so all jobs see the same versions.
+// The bundle of pistage is also set in context for steps relative to spec.
+func (r *PistageRunner) pinKhoriumSteps(ctx context.Context) (context.Context, error) {
+	ctx = store.WithKhoriumStepBundle(ctx, r.p.Bundle)
+
+	names := r.p.KhoriumStepNames()
+	if len(names) == 0 {
+		return ctx, nil
+	}
+
+	pins, err := r.store.PrefetchKhoriumSteps(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for name, commit := range pins {
+		logrus.WithFields(logrus.Fields{"pistage": r.p.WorkflowIdentifier, "name": name, "commit": commit}).Info("[Stager] KhoriumStep pinned")
+	}
+	return store.WithKhoriumStepPins(ctx, pins), nil
+}
+
+// validateKhoriumSteps checks the inputs of KhoriumSteps used by all jobs,
+// so invalid inputs fail the run before any workload is created.
+func (r *PistageRunner) validateKhoriumSteps(ctx context.Context) error {
+	var names []string
+	for name := range r.p.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs common.InputErrors
+	for _, name := range names {
+		job := r.p.Jobs[name]
+		steps := append(append([]*common.Step{}, job.Steps...), job.RollbackSteps...)
+		warnings, err := executors.ValidateKhoriumSteps(ctx, r.store, steps, r.p.Vars)
+		for _, warning := range warnings {
+			logrus.WithFields(logrus.Fields{"pistage": r.p.WorkflowIdentifier, "job": name}).Warn("[Stager] " + warning)
+		}
+		if err != nil {
+			errs = append(errs, errors.WithMessagef(err, "job: %s", name))
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// recordStepRuns records the KhoriumSteps used by steps of jobRun,
+// with the commits and digests they're pinned to, so what actually ran is known.
+// Composites are expanded, the steps they're made of are recorded instead.
+func (r *PistageRunner) recordStepRuns(ctx context.Context, jobRun *common.JobRun, steps []*common.Step, rollback bool) error {
+	steps, err := executors.ExpandSteps(ctx, r.store, steps, r.p.Vars)
+	if err != nil {
+		return err
+	}
+
+	var stepRuns []*common.StepRun
+	for _, step := range steps {
+		if step.Uses == "" {
+			continue
+		}
+		ks, err := r.store.GetRegisteredKhoriumStep(ctx, step.Uses)
+		if err != nil {
+			return err
+		}
+		stepRuns = append(stepRuns, &common.StepRun{
+			StepName: step.Name,
+			Uses:     step.Uses,
+			Commit:   ks.Commit,
+			Digest:   ks.Digest,
+			SignedBy: ks.SignedBy,
+			Rollback: rollback,
+		})
+	}
+	return r.store.CreateStepRuns(jobRun, stepRuns)
+}
+
 func (r *PistageRunner) runOneJob(ctx context.Context, job *common.Job) error {
 	p := r.p
 	logger := logrus.WithFields(logrus.Fields{"pistage": p.WorkflowIdentifier, "executor": p.Executor, "job": job.Name})
@@ -142,6 +313,18 @@ func (r *PistageRunner) runOneJob(ctx context.Context, job *common.Job) error {
 	}
 	r.jobRuns[job.Name] = jobRun
 
+	// the JobRun is pending until the scheduler lets it start.
+	release, err := r.scheduler.AcquireJob(ctx, r.task, job.Name)
+	if err != nil {
+		jobRun.Status = common.RunStatusCanceled
+		if err := r.store.UpdateJobRun(jobRun); err != nil {
+			logger.WithError(err).Errorf("[Stager runOneJob] error updating JobRun")
+		}
+		logger.WithError(err).Error("[Stager runOneJob] canceled before scheduled")
+		return err
+	}
+	defer release()
+
 	defer func() {
 		if jobRun.Status == common.RunStatusRunning {
 			jobRun.Status = common.RunStatusFinished
@@ -159,12 +342,18 @@ func (r *PistageRunner) runOneJob(ctx context.Context, job *common.Job) error {
 	// start JobRun
 	jobRun.Start = common.EpochMillis()
 	jobRun.Status = common.RunStatusRunning
-	jobRun.LogTracer = common.NewLogTracer(r.run.ID, r.o)
+	jobRun.LogTracer = r.newLogTracer(r.run.ID)
 	if err := r.store.UpdateJobRun(jobRun); err != nil {
 		logger.WithError(err).Errorf("[Stager runOneJob] error update JobRun")
 		return err
 	}
 
+	if err := r.recordStepRuns(ctx, jobRun, job.Steps, false); err != nil {
+		jobRun.Status = common.RunStatusFailed
+		logger.WithError(err).Errorf("[Stager runOneJob] fail to record StepRuns")
+		return err
+	}
+
 	executorProvider := executors.GetExecutorProvider(p.Executor)
 	if executorProvider == nil {
 		logger.Errorf("[Stager runOneJob] fail to get a provider")
@@ -178,6 +367,8 @@ func (r *PistageRunner) runOneJob(ctx context.Context, job *common.Job) error {
 	}
 
 	defer func() {
+		ctx, cancel := r.cleanupContext(ctx)
+		defer cancel()
 		if err := executor.Cleanup(ctx); err != nil {
 			logger.WithError(err).Errorf("[Stager runOneJob] error when CLEANUP")
 			return
@@ -189,6 +380,7 @@ func (r *PistageRunner) runOneJob(ctx context.Context, job *common.Job) error {
 		logger.WithError(err).Errorf("[Stager runOneJob] error when PREPARE")
 		return err
 	}
+	jobRun.Prepared = common.EpochMillis()
 
 	if err := executor.Execute(ctx); err != nil {
 		jobRun.Status = common.RunStatusFailed
@@ -217,6 +409,20 @@ func (r *PistageRunner) rollbackWithStream(ctx context.Context) error {
 		return err
 	}
 
+	// rollback with the same inputs as the run being rolled back, unless they're given
+	if len(p.Vars) == 0 {
+		p.Vars = pistageRun.Vars
+	}
+	if err := r.resolveVars(); err != nil {
+		logger.WithError(err).Errorf("[Stager rollback] invalid inputs")
+		return err
+	}
+	if err := r.resolveSecrets(ctx); err != nil {
+		logger.WithError(err).Errorf("[Stager rollback] fail to resolve secrets")
+		return err
+	}
+	p = r.p
+
 	id := pistageRun.ID
 
 	jobRuns, err := r.store.GetJobRunsByPistageRunId(id)
@@ -228,7 +434,7 @@ func (r *PistageRunner) rollbackWithStream(ctx context.Context) error {
 	finishedJobRuns := make([]*common.JobRun, 0)
 	for _, jobRun := range jobRuns {
 		if jobRun.Status == common.RunStatusFinished {
-			jobRun.LogTracer = common.NewLogTracer(id, r.o)
+			jobRun.LogTracer = r.newLogTracer(id)
 			finishedJobRuns = append(finishedJobRuns, jobRun)
 		}
 	}
@@ -237,6 +443,12 @@ func (r *PistageRunner) rollbackWithStream(ctx context.Context) error {
 		return nil
 	}
 
+	ctx, err = r.pinKhoriumSteps(ctx)
+	if err != nil {
+		logger.WithError(err).Errorf("[Stager rollback] fail to prefetch KhoriumSteps")
+		return err
+	}
+
 	// descending sort by start time, start firstly will roll back finally
 	sort.Slice(finishedJobRuns, func(i, j int) bool {
 		return finishedJobRuns[i].Start > finishedJobRuns[j].Start
@@ -256,6 +468,10 @@ func (r *PistageRunner) rollbackJobs(ctx context.Context, jobRuns []*common.JobR
 	logger := logrus.WithFields(logrus.Fields{"pistage": p.WorkflowIdentifier, "executor": p.Executor, "function": "rollback"})
 	for _, jobRun := range jobRuns {
 		if job, ok := p.Jobs[jobRun.JobName]; ok {
+			if err := r.recordStepRuns(ctx, jobRun, job.RollbackSteps, true); err != nil {
+				logger.WithError(err).Errorf("[Stager rollback] fail to record StepRuns")
+				return err
+			}
 			err := r.rollbackOneJob(ctx, job, pistageRunId)
 			if err != nil {
 				logger.WithError(err).Errorf("[Stager rollback] fail to rollback")
@@ -269,18 +485,42 @@ func (r *PistageRunner) rollbackJobs(ctx context.Context, jobRuns []*common.JobR
 func (r *PistageRunner) rollbackOneJob(ctx context.Context, job *common.Job, pistageRunId string) error {
 	p := r.p
 	logger := logrus.WithFields(logrus.Fields{"pistage": p.WorkflowIdentifier, "executor": p.Executor, "function": "rollback"})
+
+	release, err := r.scheduler.AcquireJob(ctx, r.task, job.Name)
+	if err != nil {
+		logger.WithError(err).Errorf("[Stager rollbackOneJob] canceled before scheduled")
+		return err
+	}
+	defer release()
+
 	executorProvider := executors.GetExecutorProvider(p.Executor)
 	if executorProvider == nil {
 		logger.Errorf("[Stager rollbackOneJob] fail to get a provider")
 		return errors.WithMessage(executors.ErrorExecuteProviderNotFound, p.WorkflowIdentifier)
 	}
 
-	executor, err := executorProvider.GetJobExecutor(job, p, common.NewLogTracer(pistageRunId, r.o))
+	// closed after cleanup, so the tail of logs held for masking is flushed.
+	tracer := r.newLogTracer(pistageRunId)
+	defer func() {
+		if err := tracer.Close(); err != nil {
+			logger.WithError(err).Errorf("[Stager rollback] error closing logtracer")
+		}
+	}()
+
+	executor, err := executorProvider.GetJobExecutor(job, p, tracer)
 	if err != nil {
 		logger.WithError(err).Errorf("[Stager rollback] fail to get a job executor")
 		return err
 	}
 
+	defer func() {
+		ctx, cancel := r.cleanupContext(ctx)
+		defer cancel()
+		if err := executor.Cleanup(ctx); err != nil {
+			logger.WithError(err).Errorf("[Stager rollback] error when CLEANUP")
+		}
+	}()
+
 	if err = executor.Prepare(ctx); err != nil {
 		logger.WithError(err).Errorf("[Stager rollback] error when PREPARE")
 		return err
@@ -290,10 +530,5 @@ func (r *PistageRunner) rollbackOneJob(ctx context.Context, job *common.Job, pis
 		logger.WithError(err).Errorf("[Stager rollback] error when EXECUTE")
 		return err
 	}
-
-	if err = executor.Cleanup(ctx); err != nil {
-		logger.WithError(err).Errorf("[Stager rollback] error when CLEANUP")
-		return err
-	}
 	return nil
 }
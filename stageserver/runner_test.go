@@ -0,0 +1,120 @@
+package stageserver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/executors"
+	"github.com/projecteru2/pistage/store"
+)
+
+// jobRunStore keeps nothing, other methods of store.Store are never called.
+type jobRunStore struct {
+	store.Store
+}
+
+func (jobRunStore) CreateJobRun(run *common.Run, jobRun *common.JobRun) error { return nil }
+func (jobRunStore) UpdateJobRun(jobRun *common.JobRun) error                  { return nil }
+func (jobRunStore) CreateStepRuns(jobRun *common.JobRun, stepRuns []*common.StepRun) error {
+	return nil
+}
+
+// blockingExecutor executes until ctx is done, a post is queued before,
+// errors of ctx post runs with are recorded.
+type blockingExecutor struct {
+	posts    executors.PostHooks
+	postErrs []error
+}
+
+func (b *blockingExecutor) GetName() string { return "blocking" }
+
+func (b *blockingExecutor) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
+	return b, nil
+}
+
+func (b *blockingExecutor) Prepare(ctx context.Context) error { return nil }
+
+func (b *blockingExecutor) Execute(ctx context.Context) error {
+	b.posts.Queue("step", &common.KhoriumStep{Run: &common.KhoriumStepRun{Post: "echo post"}}, nil, "")
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *blockingExecutor) Cleanup(ctx context.Context) error {
+	return b.posts.Run(ctx, nil, func(ctx context.Context, hook *executors.PostHook) error {
+		b.postErrs = append(b.postErrs, ctx.Err())
+		return nil
+	})
+}
+
+func (b *blockingExecutor) Rollback(ctx context.Context) error { return nil }
+
+func TestRunnerCleanupCanceled(t *testing.T) {
+	assert := assert.New(t)
+
+	executor := &blockingExecutor{}
+	executors.RegisterExecutorProvider(executor)
+
+	pt := testingTask("build", "canceled", 0)
+	pt.Pistage.Executor = "blocking"
+	r := NewRunner(pt, jobRunStore{}, nil, NewScheduler(common.SchedulerConfig{}), 10, 10)
+	r.run = &common.Run{ID: "run"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	// post hooks still run with a live context after the job is canceled.
+	assert.ErrorIs(r.runOneJob(ctx, &common.Job{Name: "job"}), context.Canceled)
+	assert.Equal([]error{nil}, executor.postErrs)
+}
+
+// rollbackExecutor writes message to the output in Rollback.
+type rollbackExecutor struct {
+	message string
+}
+
+func (e *rollbackExecutor) GetName() string { return "rollback" }
+
+func (e *rollbackExecutor) GetJobExecutor(job *common.Job, pistage *common.Pistage, output io.Writer) (executors.JobExecutor, error) {
+	return &rollbackJobExecutor{message: e.message, output: output}, nil
+}
+
+type rollbackJobExecutor struct {
+	message string
+	output  io.Writer
+}
+
+func (e *rollbackJobExecutor) Prepare(ctx context.Context) error { return nil }
+func (e *rollbackJobExecutor) Execute(ctx context.Context) error { return nil }
+func (e *rollbackJobExecutor) Cleanup(ctx context.Context) error { return nil }
+
+func (e *rollbackJobExecutor) Rollback(ctx context.Context) error {
+	_, err := io.WriteString(e.output, e.message)
+	return err
+}
+
+func TestRunnerRollbackFlush(t *testing.T) {
+	assert := assert.New(t)
+
+	executors.RegisterExecutorProvider(&rollbackExecutor{message: "rolled back s3"})
+
+	output := &bytes.Buffer{}
+	pt := testingTask("deploy", "rollback", 0)
+	pt.Pistage.Executor = "rollback"
+	pt.Output = common.DonCloseWriter{Writer: output}
+	r := NewRunner(pt, jobRunStore{}, nil, NewScheduler(common.SchedulerConfig{}), 10, 10)
+	r.masks = []string{"s3cr3t"}
+
+	// the tail held for masking is flushed after rollback.
+	assert.NoError(r.rollbackOneJob(context.Background(), &common.Job{Name: "job"}, "run"))
+	assert.Equal("rolled back s3", output.String())
+}
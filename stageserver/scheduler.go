@@ -0,0 +1,288 @@
+package stageserver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// Scheduler decides which pistage a free runner runs next, and when a job of a running pistage starts.
+// Pistages and jobs with higher priority go first, among the same priority,
+// the workflow identifier having the fewest running goes first so one can't starve others,
+// then the one queued earliest.
+// Jobs are limited by MaxConcurrentJobs of all pistages and by the quotas of workflow types.
+type Scheduler struct {
+	sync.Mutex
+	config common.SchedulerConfig
+
+	// tasks are pistages waiting for a runner, jobs are jobs waiting to start.
+	tasks []*schedulerItem
+	jobs  []*schedulerItem
+	seq   uint64
+
+	// running pistages and jobs by workflow identifier, and jobs by workflow type.
+	runningTasks map[string]int
+	runningJobs  map[string]int
+	runningTypes map[string]int
+	running      int
+
+	// notify is signaled when tasks are added or a pistage ends.
+	notify chan struct{}
+}
+
+// schedulerItem is a pistage or a job of it waiting in Scheduler.
+type schedulerItem struct {
+	task   *common.PistageTask
+	job    string
+	seq    uint64
+	queued int64
+
+	// ready is closed when the job can start.
+	ready chan struct{}
+}
+
+func (i *schedulerItem) identifier() string {
+	return i.task.Pistage.WorkflowIdentifier
+}
+
+func (i *schedulerItem) priority() int {
+	return i.task.Pistage.Priority
+}
+
+// QueueEntry is a pistage waiting for a runner, or a job waiting to start if Job is given.
+// Position starts from 1, among pistages or jobs.
+type QueueEntry struct {
+	WorkflowType       string
+	WorkflowIdentifier string
+	Job                string
+	Priority           int
+	Position           int
+	Queued             int64
+}
+
+// SchedulerStatus is what's waiting and running in Scheduler.
+type SchedulerStatus struct {
+	Pistages          []*QueueEntry
+	Jobs              []*QueueEntry
+	RunningPistages   int
+	RunningJobs       int
+	MaxConcurrentJobs int
+}
+
+func NewScheduler(config common.SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		config:       config,
+		runningTasks: map[string]int{},
+		runningJobs:  map[string]int{},
+		runningTypes: map[string]int{},
+		notify:       make(chan struct{}, 1),
+	}
+}
+
+// AddTask queues pt to be run by a runner.
+func (s *Scheduler) AddTask(pt *common.PistageTask) {
+	s.Lock()
+	defer s.Unlock()
+	s.seq++
+	s.tasks = append(s.tasks, &schedulerItem{task: pt, seq: s.seq, queued: pt.Queued})
+	s.signal()
+}
+
+// takeTask takes the pistage to run next out of the queue and counts it as running,
+// it returns nil if there is none. It's taken before sent to a runner, since the runner
+// may end it before the sender gets to count it, see requeueTask if it's not sent.
+// Pistages whose contexts are done are dropped, with their outputs closed.
+func (s *Scheduler) takeTask() *schedulerItem {
+	s.Lock()
+	defer s.Unlock()
+
+	tasks := s.tasks[:0]
+	for _, item := range s.tasks {
+		if item.task.Ctx != nil && item.task.Ctx.Err() != nil {
+			_ = item.task.Output.Close()
+			continue
+		}
+		tasks = append(tasks, item)
+	}
+	s.tasks = tasks
+
+	if len(s.tasks) == 0 {
+		return nil
+	}
+	next := schedulingOrder(s.tasks, s.runningTasks)[0]
+	for i, item := range s.tasks {
+		if item == next {
+			s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
+			break
+		}
+	}
+	s.runningTasks[next.identifier()]++
+	return next
+}
+
+// requeueTask puts item taken by takeTask back, when it's not sent to any runner.
+func (s *Scheduler) requeueTask(item *schedulerItem) {
+	s.Lock()
+	defer s.Unlock()
+	s.tasks = append(s.tasks, item)
+	decrease(s.runningTasks, item.identifier())
+}
+
+// endTask marks pt as ended by its runner.
+func (s *Scheduler) endTask(pt *common.PistageTask) {
+	s.Lock()
+	defer s.Unlock()
+	decrease(s.runningTasks, pt.Pistage.WorkflowIdentifier)
+	s.signal()
+}
+
+// signal wakes up the dispatcher to pick again, it's never blocked.
+func (s *Scheduler) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// AcquireJob blocks until job of pt can start, or ctx is done.
+// The returned release must be called when the job ends.
+func (s *Scheduler) AcquireJob(ctx context.Context, pt *common.PistageTask, job string) (func(), error) {
+	s.Lock()
+	s.seq++
+	item := &schedulerItem{task: pt, job: job, seq: s.seq, queued: common.EpochMillis(), ready: make(chan struct{})}
+	s.jobs = append(s.jobs, item)
+	s.grantJobs()
+	s.Unlock()
+
+	release := func() {
+		s.Lock()
+		defer s.Unlock()
+		s.releaseJob(item)
+	}
+
+	select {
+	case <-item.ready:
+		once := sync.Once{}
+		return func() { once.Do(release) }, nil
+	case <-ctx.Done():
+		s.Lock()
+		defer s.Unlock()
+		select {
+		case <-item.ready:
+			// granted before the lock is held.
+			s.releaseJob(item)
+		default:
+			s.removeJob(item)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// grantJobs starts waiting jobs in order, as long as limits allow.
+func (s *Scheduler) grantJobs() {
+	for _, item := range schedulingOrder(s.jobs, s.runningJobs) {
+		if s.config.MaxConcurrentJobs > 0 && s.running >= s.config.MaxConcurrentJobs {
+			return
+		}
+		workflowType := item.task.Pistage.WorkflowType
+		if quota := s.config.Quotas[workflowType]; quota > 0 && s.runningTypes[workflowType] >= quota {
+			continue
+		}
+
+		s.removeJob(item)
+		s.running++
+		s.runningJobs[item.identifier()]++
+		s.runningTypes[workflowType]++
+		close(item.ready)
+	}
+}
+
+func (s *Scheduler) releaseJob(item *schedulerItem) {
+	s.running--
+	decrease(s.runningJobs, item.identifier())
+	decrease(s.runningTypes, item.task.Pistage.WorkflowType)
+	s.grantJobs()
+}
+
+func (s *Scheduler) removeJob(item *schedulerItem) {
+	for i, job := range s.jobs {
+		if job == item {
+			s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Status returns what's waiting in the order they'll be scheduled, and how many are running.
+func (s *Scheduler) Status() *SchedulerStatus {
+	s.Lock()
+	defer s.Unlock()
+
+	status := &SchedulerStatus{
+		Pistages:          queueEntries(schedulingOrder(s.tasks, s.runningTasks)),
+		Jobs:              queueEntries(schedulingOrder(s.jobs, s.runningJobs)),
+		RunningJobs:       s.running,
+		MaxConcurrentJobs: s.config.MaxConcurrentJobs,
+	}
+	for _, count := range s.runningTasks {
+		status.RunningPistages += count
+	}
+	return status
+}
+
+func queueEntries(items []*schedulerItem) []*QueueEntry {
+	entries := make([]*QueueEntry, 0, len(items))
+	for i, item := range items {
+		entries = append(entries, &QueueEntry{
+			WorkflowType:       item.task.Pistage.WorkflowType,
+			WorkflowIdentifier: item.identifier(),
+			Job:                item.job,
+			Priority:           item.priority(),
+			Position:           i + 1,
+			Queued:             item.queued,
+		})
+	}
+	return entries
+}
+
+// schedulingOrder sorts items in the order they'll be scheduled, running counts the running ones
+// by workflow identifier. Each item picked counts as running for the items after it,
+// so identifiers of the same priority take turns.
+func schedulingOrder(items []*schedulerItem, running map[string]int) []*schedulerItem {
+	counts := map[string]int{}
+	for identifier, count := range running {
+		counts[identifier] = count
+	}
+	before := func(a, b *schedulerItem) bool {
+		if a.priority() != b.priority() {
+			return a.priority() > b.priority()
+		}
+		if counts[a.identifier()] != counts[b.identifier()] {
+			return counts[a.identifier()] < counts[b.identifier()]
+		}
+		return a.seq < b.seq
+	}
+
+	remaining := append([]*schedulerItem{}, items...)
+	ordered := make([]*schedulerItem, 0, len(items))
+	for len(remaining) > 0 {
+		best := 0
+		for i := 1; i < len(remaining); i++ {
+			if before(remaining[i], remaining[best]) {
+				best = i
+			}
+		}
+		item := remaining[best]
+		ordered = append(ordered, item)
+		counts[item.identifier()]++
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	return ordered
+}
+
+func decrease(counts map[string]int, key string) {
+	if counts[key]--; counts[key] <= 0 {
+		delete(counts, key)
+	}
+}
@@ -0,0 +1,119 @@
+package stageserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func testingTask(workflowType, identifier string, priority int) *common.PistageTask {
+	return &common.PistageTask{
+		Ctx:     context.Background(),
+		Pistage: &common.Pistage{WorkflowType: workflowType, WorkflowIdentifier: identifier, Priority: priority},
+		Output:  common.ClosableDiscard,
+	}
+}
+
+func TestSchedulerTasks(t *testing.T) {
+	assert := assert.New(t)
+	s := NewScheduler(common.SchedulerConfig{})
+
+	monorepo := testingTask("build", "monorepo", 0)
+	s.AddTask(monorepo)
+	s.AddTask(testingTask("build", "monorepo", 0))
+	s.AddTask(testingTask("build", "service", 0))
+	hotfix := testingTask("deploy", "hotfix", 10)
+	s.AddTask(hotfix)
+
+	// higher priority first, then identifiers take turns.
+	var order []string
+	for _, entry := range s.Status().Pistages {
+		order = append(order, entry.WorkflowIdentifier)
+	}
+	assert.Equal([]string{"hotfix", "monorepo", "service", "monorepo"}, order)
+	assert.Equal(hotfix, s.takeTask().task)
+	assert.Equal(monorepo, s.takeTask().task)
+	assert.Equal(2, s.Status().RunningPistages)
+
+	// put back if not sent to any runner.
+	item := s.takeTask()
+	assert.Equal("service", item.identifier())
+	assert.Equal(3, s.Status().RunningPistages)
+	s.requeueTask(item)
+	assert.Equal(2, s.Status().RunningPistages)
+	assert.Len(s.Status().Pistages, 2)
+
+	// ended by runner before sender returns, it's never counted again.
+	s.endTask(hotfix)
+	s.endTask(monorepo)
+	assert.Equal(0, s.Status().RunningPistages)
+
+	// canceled ones are dropped.
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := testingTask("build", "canceled", 100)
+	canceled.Ctx = ctx
+	s.AddTask(canceled)
+	cancel()
+	assert.Equal("monorepo", s.takeTask().identifier())
+	assert.Len(s.Status().Pistages, 1)
+}
+
+func TestSchedulerJobs(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	s := NewScheduler(common.SchedulerConfig{MaxConcurrentJobs: 2, Quotas: map[string]int{"build": 1}})
+
+	monorepo := testingTask("build", "monorepo", 0)
+	releaseBuild, err := s.AcquireJob(ctx, monorepo, "build")
+	assert.NoError(err)
+
+	// quota of build is used up.
+	acquired := make(chan string, 4)
+	acquire := func(pt *common.PistageTask, job string) {
+		release, err := s.AcquireJob(ctx, pt, job)
+		assert.NoError(err)
+		acquired <- job
+		release()
+	}
+	go acquire(monorepo, "test")
+	assert.Eventually(func() bool { return len(s.Status().Jobs) == 1 }, time.Second, time.Millisecond)
+
+	hotfix := testingTask("deploy", "hotfix", 10)
+	releaseDeploy, err := s.AcquireJob(ctx, hotfix, "deploy")
+	assert.NoError(err)
+	assert.Equal(2, s.Status().RunningJobs)
+
+	// global limit is reached, hotfix goes before lint queued earlier.
+	go acquire(testingTask("lint", "service", 0), "lint")
+	assert.Eventually(func() bool { return len(s.Status().Jobs) == 2 }, time.Second, time.Millisecond)
+	go acquire(hotfix, "verify")
+	assert.Eventually(func() bool { return len(s.Status().Jobs) == 3 }, time.Second, time.Millisecond)
+	jobs := s.Status().Jobs
+	assert.Equal("verify", jobs[0].Job)
+	assert.Equal(1, jobs[0].Position)
+
+	releaseDeploy()
+	releaseDeploy()
+	assert.Equal("verify", <-acquired)
+	assert.Equal("lint", <-acquired)
+	releaseBuild()
+	assert.Equal("test", <-acquired)
+
+	// canceled while waiting.
+	release, err := s.AcquireJob(ctx, hotfix, "a")
+	assert.NoError(err)
+	release2, err := s.AcquireJob(ctx, hotfix, "b")
+	assert.NoError(err)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = s.AcquireJob(ctx, hotfix, "c")
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	release()
+	release2()
+	assert.Empty(s.Status().Jobs)
+	assert.Equal(0, s.Status().RunningJobs)
+}
@@ -7,24 +7,29 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/projecteru2/pistage/common"
+	"github.com/projecteru2/pistage/secrets"
 	"github.com/projecteru2/pistage/store"
 )
 
 type StageServer struct {
-	config *common.Config
-	stages chan *common.PistageTask
-	stop   chan struct{}
-	store  store.Store
-	wg     sync.WaitGroup
+	config    *common.Config
+	scheduler *Scheduler
+	stages    chan *common.PistageTask
+	stop      chan struct{}
+	store     store.Store
+	secrets   secrets.SecretProvider
+	wg        sync.WaitGroup
 }
 
-func NewStageServer(config *common.Config, store store.Store) *StageServer {
+func NewStageServer(config *common.Config, store store.Store, secrets secrets.SecretProvider) *StageServer {
 	return &StageServer{
-		config: config,
-		stages: make(chan *common.PistageTask),
-		stop:   make(chan struct{}),
-		store:  store,
-		wg:     sync.WaitGroup{},
+		config:    config,
+		scheduler: NewScheduler(config.Scheduler),
+		stages:    make(chan *common.PistageTask),
+		stop:      make(chan struct{}),
+		store:     store,
+		secrets:   secrets,
+		wg:        sync.WaitGroup{},
 	}
 }
 
@@ -36,6 +41,12 @@ func (s *StageServer) Start() {
 			s.runner(id)
 		}(id)
 	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.dispatch()
+	}()
 }
 
 func (s *StageServer) Stop() {
@@ -45,8 +56,42 @@ func (s *StageServer) Stop() {
 	logrus.Info("[Stager] gracefully stopped")
 }
 
+// Add queues pt to be run by a free runner, in the order decided by Scheduler.
 func (s *StageServer) Add(pt *common.PistageTask) {
-	s.stages <- pt
+	if pt.Queued == 0 {
+		pt.Queued = common.EpochMillis()
+	}
+	s.scheduler.AddTask(pt)
+}
+
+// Status returns the pistages and jobs waiting in the scheduler, with their positions.
+func (s *StageServer) Status() *SchedulerStatus {
+	return s.scheduler.Status()
+}
+
+// dispatch sends the next pistage to a free runner, it picks again when the queue changes
+// before any runner is free, so a pistage with higher priority added meanwhile goes first.
+func (s *StageServer) dispatch() {
+	for {
+		item := s.scheduler.takeTask()
+		if item == nil {
+			select {
+			case <-s.scheduler.notify:
+				continue
+			case <-s.stop:
+				return
+			}
+		}
+
+		select {
+		case s.stages <- item.task:
+		case <-s.scheduler.notify:
+			s.scheduler.requeueTask(item)
+		case <-s.stop:
+			s.scheduler.requeueTask(item)
+			return
+		}
+	}
 }
 
 func (s *StageServer) runner(id int) {
@@ -57,7 +102,7 @@ func (s *StageServer) runner(id int) {
 			logrus.WithField("runner id", id).Info("[Stager] runner stopped")
 			return
 		case pt := <-s.stages:
-			r := NewRunner(pt, s.store, s.config.DefaultJobExecuteTimeoutSecs)
+			r := NewRunner(pt, s.store, s.secrets, s.scheduler, s.config.DefaultJobExecuteTimeoutSecs, s.config.DefaultJobCleanupTimeoutSecs)
 			// if err := s.runWithGraph(pt); err != nil {
 			// 	logrus.WithField("pistage", pt.Pistage.WorkflowIdentifier).WithError(err).Errorf("[Stager runner] error when running a pistage")
 			// }
@@ -80,6 +125,7 @@ func (s *StageServer) runner(id int) {
 			if err := pt.Output.Close(); err != nil {
 				logrus.WithField("pistage", pt.Pistage.WorkflowIdentifier).WithError(err).Errorf("[Stager runner] error when closing the output writer")
 			}
+			s.scheduler.endTask(pt)
 			runtime.GC()
 		}
 	}
@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// ErrorCacheNotFound is returned when no cache matches the key or restore keys.
+var ErrorCacheNotFound = errors.New("Cache not found")
+
+const cacheFileSuffix = ".tgz"
+
+// CacheManager manages job caches.
+// Each cache is a tarball stored under the cache dir,
+// with the escaped key as the file name, so a prefix of key
+// is still a prefix of the file name.
+// The modification time of the file is used as the last used time.
+type CacheManager struct {
+	mutex  sync.Mutex
+	config common.CacheConfig
+}
+
+func NewCacheManager(config common.CacheConfig) *CacheManager {
+	return &CacheManager{config: config}
+}
+
+type cacheEntry struct {
+	key     string
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Restore restores the cache with the given key.
+// If key misses, restoreKeys are used as prefixes to find the most recent cache
+// in the given order.
+// Returns the key of the restored cache and the tarball, which must be closed by the caller.
+func (c *CacheManager) Restore(ctx context.Context, key string, restoreKeys []string) (string, io.ReadCloser, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries, err := c.entries()
+	if err != nil {
+		return "", nil, err
+	}
+
+	entry := findCacheEntry(entries, key, restoreKeys)
+	if entry == nil {
+		return "", nil, errors.WithMessagef(ErrorCacheNotFound, "key: %s", key)
+	}
+
+	// an opened file is still readable after it's evicted.
+	f, err := os.Open(entry.path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// touch the cache, so it won't be evicted soon.
+	now := time.Now()
+	if err := os.Chtimes(entry.path, now, now); err != nil {
+		logrus.WithField("key", entry.key).WithError(err).Warn("[CacheManager] fail to touch cache")
+	}
+	return entry.key, f, nil
+}
+
+// Save saves the tarball read from content as the cache of key, caches exceed
+// the age or size limit are evicted after saving.
+func (c *CacheManager) Save(ctx context.Context, key string, content io.Reader) error {
+	if err := os.MkdirAll(c.config.Dir, 0755); err != nil {
+		return err
+	}
+
+	// write to a temp file then rename, readers never see a partial tarball.
+	// the temp file is never seen by others, so content is streamed without the lock.
+	f, err := ioutil.TempFile(c.config.Dir, ".pistage-cache-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := os.Rename(f.Name(), c.cachePath(key)); err != nil {
+		return err
+	}
+	return c.evict()
+}
+
+// evict removes caches not used for MaxAgeSecs,
+// then removes the least recently used caches until
+// the total size is within MaxSizeMB.
+func (c *CacheManager) evict() error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	var (
+		kept    []*cacheEntry
+		total   int64
+		maxAge  = time.Duration(c.config.MaxAgeSecs) * time.Second
+		maxSize = c.config.MaxSizeMB * 1024 * 1024
+	)
+	for _, entry := range entries {
+		if maxAge > 0 && time.Since(entry.modTime) > maxAge {
+			if err := os.Remove(entry.path); err != nil {
+				return err
+			}
+			continue
+		}
+		kept = append(kept, entry)
+		total += entry.size
+	}
+
+	// entries are sorted with the most recent first,
+	// so we remove from the tail.
+	for i := len(kept) - 1; i >= 0 && maxSize > 0 && total > maxSize; i-- {
+		if err := os.Remove(kept[i].path); err != nil {
+			return err
+		}
+		total -= kept[i].size
+	}
+	return nil
+}
+
+// entries returns all caches, the most recently used first.
+func (c *CacheManager) entries() ([]*cacheEntry, error) {
+	infos, err := ioutil.ReadDir(c.config.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []*cacheEntry
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, cacheFileSuffix) {
+			continue
+		}
+		key, err := url.QueryUnescape(strings.TrimSuffix(name, cacheFileSuffix))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, &cacheEntry{
+			key:     key,
+			path:    filepath.Join(c.config.Dir, name),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].modTime.After(entries[j].modTime)
+	})
+	return entries, nil
+}
+
+func (c *CacheManager) cachePath(key string) string {
+	return filepath.Join(c.config.Dir, url.QueryEscape(key)+cacheFileSuffix)
+}
+
+// findCacheEntry finds the entry with exactly the key first,
+// then the most recent entry prefixed with each restore key.
+// entries must be sorted with the most recent first.
+func findCacheEntry(entries []*cacheEntry, key string, restoreKeys []string) *cacheEntry {
+	for _, entry := range entries {
+		if entry.key == key {
+			return entry
+		}
+	}
+	for _, restoreKey := range restoreKeys {
+		if restoreKey == "" {
+			continue
+		}
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.key, restoreKey) {
+				return entry
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func TestCacheManagerRestore(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "pistage-cache-test-*")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cm := NewCacheManager(common.CacheConfig{Dir: dir, MaxAgeSecs: 3600, MaxSizeMB: 1})
+
+	_, _, err = cm.Restore(ctx, "go-abc", nil)
+	assert.ErrorIs(err, ErrorCacheNotFound)
+
+	assert.NoError(cm.Save(ctx, "go-abc", strings.NewReader("abc")))
+	assert.NoError(cm.Save(ctx, "linux/go-def", strings.NewReader("def")))
+
+	key, content, err := cm.Restore(ctx, "go-abc", nil)
+	assert.NoError(err)
+	assert.Equal(key, "go-abc")
+	assert.Equal(readCache(t, content), "abc")
+
+	key, content, err = cm.Restore(ctx, "linux/go-xyz", []string{"linux/go-", "go-"})
+	assert.NoError(err)
+	assert.Equal(key, "linux/go-def")
+	assert.Equal(readCache(t, content), "def")
+
+	key, content, err = cm.Restore(ctx, "go-xyz", []string{"npm-", "go-"})
+	assert.NoError(err)
+	assert.Equal(key, "go-abc")
+	assert.Equal(readCache(t, content), "abc")
+
+	// a failed save leaves the cache untouched.
+	assert.Error(cm.Save(ctx, "go-abc", iotest.ErrReader(io.ErrUnexpectedEOF)))
+	_, content, err = cm.Restore(ctx, "go-abc", nil)
+	assert.NoError(err)
+	assert.Equal(readCache(t, content), "abc")
+
+	// caches are restored while a slow one is being saved.
+	r, w := io.Pipe()
+	saved := make(chan error)
+	go func() {
+		saved <- cm.Save(ctx, "go-abc", r)
+	}()
+	w.Write([]byte("ab"))
+	_, content, err = cm.Restore(ctx, "go-abc", nil)
+	assert.NoError(err)
+	assert.Equal(readCache(t, content), "abc")
+	w.Write([]byte("cd"))
+	w.Close()
+	assert.NoError(<-saved)
+	_, content, err = cm.Restore(ctx, "go-abc", nil)
+	assert.NoError(err)
+	assert.Equal(readCache(t, content), "abcd")
+}
+
+func readCache(t *testing.T, content io.ReadCloser) string {
+	defer content.Close()
+	b, err := ioutil.ReadAll(content)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestCacheManagerEvict(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "pistage-cache-test-*")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cm := NewCacheManager(common.CacheConfig{Dir: dir, MaxAgeSecs: 3600, MaxSizeMB: 1})
+	halfMB := make([]byte, 512*1024)
+
+	assert.NoError(cm.Save(ctx, "old", strings.NewReader("old")))
+	past := time.Now().Add(-2 * time.Hour)
+	assert.NoError(os.Chtimes(cm.cachePath("old"), past, past))
+
+	assert.NoError(cm.Save(ctx, "k1", bytes.NewReader(halfMB)))
+	earlier := time.Now().Add(-time.Minute)
+	assert.NoError(os.Chtimes(cm.cachePath("k1"), earlier, earlier))
+	assert.NoError(cm.Save(ctx, "k2", bytes.NewReader(halfMB)))
+	assert.NoError(cm.Save(ctx, "k3", bytes.NewReader(halfMB)))
+
+	// old is expired, k1 is the least recently used one.
+	_, _, err = cm.Restore(ctx, "old", nil)
+	assert.ErrorIs(err, ErrorCacheNotFound)
+	_, _, err = cm.Restore(ctx, "k1", nil)
+	assert.ErrorIs(err, ErrorCacheNotFound)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+cacheFileSuffix))
+	assert.NoError(err)
+	assert.Len(files, 2)
+}
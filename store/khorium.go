@@ -1,27 +1,111 @@
 package store
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/projecteru2/pistage/common"
 )
 
+// ErrorKhoriumRefNotFound is returned when the version can't be resolved to a commit.
+var ErrorKhoriumRefNotFound = errors.New("KhoriumStep version not found")
+
+var commitRe = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
 // KhoriumManager manages khorium steps.
-// Currently it can download (git clone)
-// repo identified by khorium step name from
-// GitLab and GitHub.
+// It can download (git clone) repo identified by khorium step name
+// from GitLab and GitHub, or load steps from local sources and tarballs,
+// see GetKhoriumStep for all the sources.
+//
+// Repositories are mirrored under cache dir, and fetched only when
+// a version needs to be resolved again.
+// Each commit is checked out once under cache dir, and loaded once,
+// so the same step used by many jobs costs nothing after the first use.
+// Steps from other sources are cached by digest, and expire if not used for StepTTLSecs.
+// Steps returned are copies, callers can never change the cached ones.
 type KhoriumManager struct {
+	mutex  sync.Mutex
 	config common.KhoriumConfig
+
+	// repositoryURL returns the url to clone from by the name of step.
+	repositoryURL func(name string) string
+
+	// repoLocks serializes git operations on the same mirror.
+	repoLocks map[string]*sync.Mutex
+	// refs holds the resolved commits, keyed by name@version.
+	refs map[string]*khoriumRef
+	// steps holds the loaded steps, keyed by name@commit.
+	steps map[string]*khoriumEntry
+}
+
+// khoriumEntry is a loaded step, usedAt is when it's last used,
+// steps not from git expire, their keys are digests of contents,
+// which change on every edit.
+type khoriumEntry struct {
+	ks     *common.KhoriumStep
+	git    bool
+	usedAt time.Time
+}
+
+// khoriumRef is a version resolved to commit.
+// Tags and commits are immutable, branches expire after TTL.
+type khoriumRef struct {
+	commit     string
+	immutable  bool
+	resolvedAt time.Time
 }
 
 func NewKhoriumManager(config common.KhoriumConfig) *KhoriumManager {
-	return &KhoriumManager{config: config}
+	k := &KhoriumManager{
+		config:    config,
+		repoLocks: map[string]*sync.Mutex{},
+		refs:      map[string]*khoriumRef{},
+		steps:     map[string]*khoriumEntry{},
+	}
+	k.repositoryURL = k.defaultRepositoryURL
+	return k
+}
+
+type khoriumPinsKey struct{}
+
+// WithKhoriumStepPins returns a context with KhoriumSteps pinned to commits,
+// pins are names of steps to commits, as returned by Prefetch.
+// GetKhoriumStep with this context uses the pinned commits instead of resolving versions,
+// so all jobs in a run see the same version of a step.
+func WithKhoriumStepPins(ctx context.Context, pins map[string]string) context.Context {
+	return context.WithValue(ctx, khoriumPinsKey{}, pins)
+}
+
+func pinnedCommit(ctx context.Context, name string) (string, bool) {
+	pins, ok := ctx.Value(khoriumPinsKey{}).(map[string]string)
+	if !ok {
+		return "", false
+	}
+	commit, ok := pins[name]
+	return commit, ok
+}
+
+// splitKhoriumName splits name into repository and version,
+// version defaults to master.
+func splitKhoriumName(name string) (string, string) {
+	ps := strings.SplitN(name, "@", 2)
+	if len(ps) == 2 {
+		return ps[0], ps[1]
+	}
+	return name, "master"
 }
 
 // GetKhoriumStep gets a khorium step from code repository.
@@ -36,60 +120,357 @@ func NewKhoriumManager(config common.KhoriumConfig) *KhoriumManager {
 // the access token and username to clone repository.
 // The "@" symbol represents at which tag / commit / branch, will be used
 // like "git checkout @symbol".
+// The content can be pinned by digest too, like github.com/test/checkout@v2.1.1@sha256:...,
+// it fails if Digest of the step loaded doesn't match.
+// If the step is pinned in ctx, the pinned commit is used.
+//
+// Steps can also be loaded from other sources:
+//   - ./steps/deploy, relative to the bundle of spec in ctx
+//   - file:///opt/khorium/deploy, a directory under file roots
+//   - https://host/deploy.tar.gz#sha256=..., a tarball with its digest
+// For these sources, Commit of the step is the digest of its content.
+//
+// Git and tarball steps must be under allowed namespaces,
+// and all steps except bundled ones are verified against public keys, see KhoriumConfig.
 func (k *KhoriumManager) GetKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
-	var (
-		khoriumName    string
-		khoriumVersion string
-	)
+	ks, err := k.getKhoriumStep(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return ks.Copy(), nil
+}
 
-	ps := strings.SplitN(name, "@", 2)
-	if len(ps) == 2 {
-		khoriumName = ps[0]
-		khoriumVersion = ps[1]
-	} else {
-		khoriumName = name
-		khoriumVersion = "master"
+// getKhoriumStep returns the cached step, it must never be changed.
+func (k *KhoriumManager) getKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	if IsBundledKhoriumStep(name) {
+		return k.getBundledKhoriumStep(ctx, name)
+	}
+	if !strings.HasPrefix(name, "file://") {
+		if err := k.checkNamespace(name); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case isTarballKhoriumStep(name):
+		return k.getTarballKhoriumStep(ctx, name)
+	case strings.HasPrefix(name, "file://"):
+		return k.getFileKhoriumStep(ctx, name)
 	}
 
-	dir, err := ioutil.TempDir("", "pistage-khoriumstep-*")
+	repository, version := splitKhoriumName(name)
+	version, digest := splitKhoriumDigest(version)
+
+	commit, ok := pinnedCommit(ctx, name)
+	if !ok {
+		var err error
+		if commit, err = k.resolve(ctx, repository, version); err != nil {
+			return nil, err
+		}
+	}
+
+	ks, err := k.load(ctx, repository, commit)
 	if err != nil {
 		return nil, err
 	}
-	defer os.RemoveAll(dir)
+	if digest != "" && ks.Digest != digest {
+		return nil, errors.WithMessagef(ErrorKhoriumDigestMismatch, "name: %s, commit: %s, digest: %s", name, commit, ks.Digest)
+	}
+	return ks, nil
+}
+
+// Prefetch resolves and loads all the steps,
+// returns the names of steps to the resolved commits,
+// which can be pinned by WithKhoriumStepPins.
+// Steps used by composite steps are prefetched too,
+// except those templated with inputs, which are only known when expanded.
+func (k *KhoriumManager) Prefetch(ctx context.Context, names []string) (map[string]string, error) {
+	pins := map[string]string{}
+	for len(names) != 0 {
+		name := names[0]
+		names = names[1:]
+		if _, ok := pins[name]; ok {
+			continue
+		}
+
+		ks, err := k.GetKhoriumStep(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		pins[name] = ks.Commit
+
+		if !ks.IsComposite() {
+			continue
+		}
+		for _, step := range ks.Run.Steps {
+			if step.Uses != "" && !strings.Contains(step.Uses, "{{") {
+				names = append(names, step.Uses)
+			}
+		}
+	}
+	return pins, nil
+}
+
+func (k *KhoriumManager) repoLock(repository string) *sync.Mutex {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
 
-	if err := k.cloneRepository(ctx, khoriumName, khoriumVersion, dir); err != nil {
+	lock, ok := k.repoLocks[repository]
+	if !ok {
+		lock = &sync.Mutex{}
+		k.repoLocks[repository] = lock
+	}
+	return lock
+}
+
+// resolve resolves version of repository to a commit.
+// Resolved tags and commits are reused forever, branches are reused within TTL.
+// The mirror is fetched only if the version isn't an immutable ref already in mirror.
+func (k *KhoriumManager) resolve(ctx context.Context, repository, version string) (string, error) {
+	lock := k.repoLock(repository)
+	lock.Lock()
+	defer lock.Unlock()
+
+	key := repository + "@" + version
+	k.mutex.Lock()
+	ref, ok := k.refs[key]
+	k.mutex.Unlock()
+	if ok && (ref.immutable || time.Since(ref.resolvedAt) < time.Duration(k.config.RefTTLSecs)*time.Second) {
+		return ref.commit, nil
+	}
+
+	mirror, cloned, err := k.ensureMirror(ctx, repository)
+	if err != nil {
+		return "", err
+	}
+
+	commit, immutable, err := revParse(ctx, mirror, version)
+	if !cloned && (err != nil || !immutable) {
+		if err := k.remoteGit(ctx, repository, mirror, "fetch", "--prune", "--force", "origin"); err != nil {
+			return "", err
+		}
+		commit, immutable, err = revParse(ctx, mirror, version)
+	}
+	if err != nil {
+		return "", errors.WithMessagef(ErrorKhoriumRefNotFound, "name: %s", key)
+	}
+
+	k.mutex.Lock()
+	k.refs[key] = &khoriumRef{commit: commit, immutable: immutable, resolvedAt: time.Now()}
+	k.mutex.Unlock()
+
+	logrus.WithFields(logrus.Fields{"name": key, "commit": commit}).Debug("[KhoriumManager] version resolved")
+	return commit, nil
+}
+
+// ensureMirror clones the mirror of repository if it doesn't exist,
+// returns the path of mirror, and whether it's just cloned.
+func (k *KhoriumManager) ensureMirror(ctx context.Context, repository string) (string, bool, error) {
+	mirror := filepath.Join(k.config.CacheDir, "repos", url.PathEscape(repository)+".git")
+	if _, err := os.Stat(mirror); err == nil {
+		return mirror, false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(mirror), 0755); err != nil {
+		return "", false, err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(mirror), "clone-*")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := k.remoteGit(ctx, repository, "", "clone", "--mirror", "--quiet", k.repositoryURL(repository), tmp); err != nil {
+		return "", false, err
+	}
+	return mirror, true, os.Rename(tmp, mirror)
+}
+
+// revParse resolves version to a commit in mirror,
+// tags are tried first, then branches, then commits.
+func revParse(ctx context.Context, mirror, version string) (string, bool, error) {
+	if commit, err := gitOutput(ctx, mirror, "rev-parse", "--verify", "--quiet", "refs/tags/"+version+"^{commit}"); err == nil {
+		return commit, true, nil
+	}
+	if commit, err := gitOutput(ctx, mirror, "rev-parse", "--verify", "--quiet", "refs/heads/"+version+"^{commit}"); err == nil {
+		return commit, false, nil
+	}
+	if !commitRe.MatchString(version) {
+		return "", false, ErrorKhoriumRefNotFound
+	}
+	commit, err := gitOutput(ctx, mirror, "rev-parse", "--verify", "--quiet", version+"^{commit}")
+	return commit, true, err
+}
+
+// load loads the step of repository at commit,
+// the commit is checked out under cache dir if it's not yet.
+func (k *KhoriumManager) load(ctx context.Context, repository, commit string) (*common.KhoriumStep, error) {
+	key := repository + "@" + commit
+	if ks, ok := k.cachedStep(key); ok {
+		return ks, nil
+	}
+
+	lock := k.repoLock(repository)
+	lock.Lock()
+	defer lock.Unlock()
+
+	dir := filepath.Join(k.config.CacheDir, "steps", url.PathEscape(repository), commit)
+	if _, err := os.Stat(dir); err != nil {
+		mirror, _, err := k.ensureMirror(ctx, repository)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkout(ctx, mirror, commit, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	ks, err := k.loadKhoriumStepFromFilesystem(dir)
+	if err != nil {
+		return nil, err
+	}
+	ks.Commit = commit
+	if err := k.verifySignature(repository+"@"+commit, ks); err != nil {
 		return nil, err
 	}
-	return k.loadKhoriumStepFromFilesystem(dir)
+
+	k.cacheStep(key, ks, true)
+	return ks, nil
+}
+
+// cachedStep returns the step cached with key, and marks it used.
+func (k *KhoriumManager) cachedStep(key string) (*common.KhoriumStep, bool) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	entry, ok := k.steps[key]
+	if !ok {
+		return nil, false
+	}
+	entry.usedAt = time.Now()
+	return entry.ks, true
 }
 
-func (k *KhoriumManager) cloneRepository(ctx context.Context, name, version, path string) error {
-	var url string
-	if strings.HasPrefix(name, "github.com") {
-		url = fmt.Sprintf("https://%s.git", name)
-	} else {
-		url = fmt.Sprintf("https://%s:%s@%s.git", k.config.GitLabUsername, k.config.GitLabAccessToken, name)
+// cacheStep caches ks with key, git tells if it's from git,
+// steps not from git which are expired are evicted.
+func (k *KhoriumManager) cacheStep(key string, ks *common.KhoriumStep, git bool) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	ttl := time.Duration(k.config.StepTTLSecs) * time.Second
+	for key, entry := range k.steps {
+		if !entry.git && time.Since(entry.usedAt) >= ttl {
+			delete(k.steps, key)
+		}
 	}
+	k.steps[key] = &khoriumEntry{ks: ks, git: git, usedAt: time.Now()}
+}
 
-	// clone
-	if err := exec.CommandContext(ctx, "git", "clone", url, path).Run(); err != nil {
+// checkout extracts files of commit in mirror into dir.
+// Files are extracted into a temp dir first, so dir is either complete or absent.
+func checkout(ctx context.Context, mirror, commit, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(dir), "checkout-*")
+	if err != nil {
 		return err
 	}
+	defer os.RemoveAll(tmp)
 
-	// checkout
-	checkout := exec.CommandContext(ctx, "git", "checkout", version)
-	checkout.Dir = path
-	if err := checkout.Run(); err != nil {
+	archive := exec.CommandContext(ctx, "git", "archive", "--format=tar", commit)
+	archive.Dir = mirror
+	extract := exec.CommandContext(ctx, "tar", "-x", "-C", tmp)
+	if extract.Stdin, err = archive.StdoutPipe(); err != nil {
 		return err
 	}
+	if err := extract.Start(); err != nil {
+		return err
+	}
+	if err := archive.Run(); err != nil {
+		extract.Wait()
+		return errors.WithMessagef(err, "git archive %s", commit)
+	}
+	if err := extract.Wait(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+// envCredentialHelper is the git credential helper answers
+// the username and password in environment variables.
+const envCredentialHelper = `!f() { test "$1" = get && echo "username=$PISTAGE_GIT_USERNAME" && echo "password=$PISTAGE_GIT_PASSWORD"; }; f`
+
+// remoteGit runs git commands talking to the remote of repository,
+// credentials are given by credential helper or SSH key, never in the url.
+// Prompts are disabled, so a missing credential fails instead of hanging.
+func (k *KhoriumManager) remoteGit(ctx context.Context, repository, dir string, args ...string) error {
+	var (
+		configs []string
+		env     = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	)
+
+	switch {
+	case k.config.CredentialHelper != "":
+		configs = []string{"-c", "credential.helper=", "-c", "credential.helper=" + k.config.CredentialHelper}
+	case k.config.GitLabAccessToken != "" && !strings.HasPrefix(repository, "github.com"):
+		configs = []string{"-c", "credential.helper=", "-c", "credential.helper=" + envCredentialHelper}
+		env = append(env, "PISTAGE_GIT_USERNAME="+k.config.GitLabUsername, "PISTAGE_GIT_PASSWORD="+k.config.GitLabAccessToken)
+	}
 
-	cleanup := exec.CommandContext(ctx, "rm", "-rf", ".git")
-	cleanup.Dir = path
-	return cleanup.Run()
+	if k.config.SSHKey != "" || k.config.SSHKnownHosts != "" {
+		env = append(env, "GIT_SSH_COMMAND="+k.sshCommand())
+	}
+
+	_, err := runGit(ctx, dir, env, append(configs, args...)...)
+	return err
+}
+
+// sshCommand builds the ssh command git uses,
+// host keys are always checked.
+func (k *KhoriumManager) sshCommand() string {
+	cmd := "ssh -o BatchMode=yes -o StrictHostKeyChecking=yes"
+	if k.config.SSHKey != "" {
+		cmd += fmt.Sprintf(" -o IdentitiesOnly=yes -i '%s'", k.config.SSHKey)
+	}
+	if k.config.SSHKnownHosts != "" {
+		cmd += fmt.Sprintf(" -o UserKnownHostsFile='%s'", k.config.SSHKnownHosts)
+	}
+	return cmd
+}
+
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	return runGit(ctx, dir, nil, args...)
+}
+
+// runGit runs git with args in dir, env is inherited if it's nil.
+func runGit(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.WithMessagef(err, "git: %s", strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// defaultRepositoryURL returns the url of repository,
+// git@host:path.git for SSH hosts, https://host/path.git for others.
+func (k *KhoriumManager) defaultRepositoryURL(name string) string {
+	parts := strings.SplitN(name, "/", 2)
+	for _, host := range k.config.SSHHosts {
+		if len(parts) == 2 && parts[0] == host {
+			return fmt.Sprintf("git@%s:%s.git", parts[0], parts[1])
+		}
+	}
+	return fmt.Sprintf("https://%s.git", name)
 }
 
 func (k *KhoriumManager) loadKhoriumStepFromFilesystem(path string) (*common.KhoriumStep, error) {
-	content, err := ioutil.ReadFile(filepath.Join(path, "khoriumstep.yml"))
+	content, err := ioutil.ReadFile(filepath.Join(path, khoriumStepSpecFile))
 	if err != nil {
 		return nil, err
 	}
@@ -117,5 +498,6 @@ func (k *KhoriumManager) loadKhoriumStepFromFilesystem(path string) (*common.Kho
 	if err := filepath.Walk(path, traverse); err != nil {
 		return nil, err
 	}
+	ks.Digest = filesDigest(ks.Files)
 	return ks, nil
 }
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// List lists the KhoriumSteps known by the cache, with names starting with prefix.
+// Git steps are the repositories mirrored, described by the spec at HEAD,
+// other steps are the tarballs and directories loaded since started.
+// Steps bundled with specs are never listed.
+func (k *KhoriumManager) List(ctx context.Context, prefix string) ([]*common.KhoriumStepSummary, error) {
+	summaries := map[string]*common.KhoriumStepSummary{}
+
+	entries, err := ioutil.ReadDir(filepath.Join(k.config.CacheDir, "repos"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".git") {
+			continue
+		}
+		repository, err := url.PathUnescape(strings.TrimSuffix(entry.Name(), ".git"))
+		if err != nil || !strings.HasPrefix(repository, prefix) {
+			continue
+		}
+
+		mirror := filepath.Join(k.config.CacheDir, "repos", entry.Name())
+		summary := &common.KhoriumStepSummary{Name: repository}
+		if content, err := gitOutput(ctx, mirror, "show", "HEAD:"+khoriumStepSpecFile); err == nil {
+			if ks, err := common.LoadKhoriumStep([]byte(content)); err == nil {
+				summary.Description = ks.Description
+			}
+		}
+		if summary.Versions, err = tags(ctx, mirror); err != nil {
+			return nil, err
+		}
+		summaries[repository] = summary
+	}
+
+	k.mutex.Lock()
+	for key, entry := range k.steps {
+		name := key[:strings.LastIndex(key, "@")]
+		if !strings.HasPrefix(name, prefix) || !(strings.HasPrefix(name, "file://") || isTarballKhoriumStep(name)) {
+			continue
+		}
+		summaries[name] = &common.KhoriumStepSummary{Name: name, Description: entry.ks.Description}
+	}
+	k.mutex.Unlock()
+
+	result := make([]*common.KhoriumStepSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// Versions returns the tags of the repository of a git step, the newest first,
+// the repository is mirrored if it's not yet.
+// Steps from other sources have no versions.
+func (k *KhoriumManager) Versions(ctx context.Context, name string) ([]string, error) {
+	if IsBundledKhoriumStep(name) || isTarballKhoriumStep(name) || strings.HasPrefix(name, "file://") {
+		return nil, nil
+	}
+	if err := k.checkNamespace(name); err != nil {
+		return nil, err
+	}
+
+	repository, _ := splitKhoriumName(name)
+	lock := k.repoLock(repository)
+	lock.Lock()
+	defer lock.Unlock()
+
+	mirror, _, err := k.ensureMirror(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	return tags(ctx, mirror)
+}
+
+// tags lists tags in mirror, sorted by version descending.
+func tags(ctx context.Context, mirror string) ([]string, error) {
+	output, err := gitOutput(ctx, mirror, "for-each-ref", "--sort=-v:refname", "--format=%(refname:short)", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func TestKhoriumManagerCatalog(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	repo, commit := newTestRepository(t)
+	commit("echo v1")
+	for _, tag := range []string{"v1.2.0", "v1.10.0", "v1.9.1"} {
+		assert.NoError(exec.Command("git", "-C", repo, "tag", tag).Run())
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "deploy")
+	assert.NoError(os.MkdirAll(dir, 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "khoriumstep.yml"), []byte("name: deploy\ndescription: deploy it\nrun:\n  main: deploy\n"), 0644))
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), FileRoots: []string{root}, StepTTLSecs: 3600})
+	k.repositoryURL = func(name string) string { return repo }
+
+	summaries, err := k.List(ctx, "")
+	assert.NoError(err)
+	assert.Empty(summaries)
+
+	versions, err := k.Versions(ctx, "example.com/test/step@v1.2.0")
+	assert.NoError(err)
+	assert.Equal([]string{"v1.10.0", "v1.9.1", "v1.2.0"}, versions)
+
+	_, err = k.GetKhoriumStep(ctx, "file://"+dir)
+	assert.NoError(err)
+	ctx = WithKhoriumStepBundle(ctx, map[string][]byte{"steps/deploy/khoriumstep.yml": []byte(testKhoriumStepSpec)})
+	_, err = k.GetKhoriumStep(ctx, "./steps/deploy")
+	assert.NoError(err)
+
+	summaries, err = k.List(ctx, "")
+	assert.NoError(err)
+	assert.Equal([]*common.KhoriumStepSummary{
+		{Name: "example.com/test/step", Versions: []string{"v1.10.0", "v1.9.1", "v1.2.0"}},
+		{Name: "file://" + dir, Description: "deploy it"},
+	}, summaries)
+
+	summaries, err = k.List(ctx, "file://")
+	assert.NoError(err)
+	assert.Len(summaries, 1)
+
+	versions, err = k.Versions(ctx, "file://"+dir)
+	assert.NoError(err)
+	assert.Empty(versions)
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+var (
+	// ErrorKhoriumNamespaceNotAllowed is returned when a step is not under any allowed namespace.
+	ErrorKhoriumNamespaceNotAllowed = errors.New("KhoriumStep namespace not allowed")
+
+	// ErrorKhoriumDigestMismatch is returned when the digest of step doesn't match the pinned one.
+	ErrorKhoriumDigestMismatch = errors.New("KhoriumStep digest mismatch")
+
+	// ErrorKhoriumSignatureRequired is returned when a step has no signature but it's required.
+	ErrorKhoriumSignatureRequired = errors.New("KhoriumStep signature required")
+
+	// ErrorKhoriumSignatureInvalid is returned when the signature of step is not signed by any public key.
+	ErrorKhoriumSignatureInvalid = errors.New("KhoriumStep signature invalid")
+)
+
+// khoriumStepSignatureFile holds the base64 ed25519 signature of the digest of step.
+const khoriumStepSignatureFile = "khoriumstep.yml.sig"
+
+// splitKhoriumDigest splits the pinned digest from version,
+// version can be sha256:<digest> or <version>@sha256:<digest>,
+// version defaults to master if only digest is given.
+func splitKhoriumDigest(version string) (string, string) {
+	if strings.HasPrefix(version, "sha256:") {
+		return "master", version
+	}
+	if i := strings.Index(version, "@sha256:"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+// checkNamespace checks if name is under one of the allowed namespaces,
+// names with . or .. in path are never allowed, since they may escape the namespace.
+func (k *KhoriumManager) checkNamespace(name string) error {
+	if len(k.config.AllowedNamespaces) == 0 {
+		return nil
+	}
+
+	target, _ := splitKhoriumName(name)
+	if u, err := url.Parse(name); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		target = u.Host + u.Path
+	}
+	if path.Clean(target) == target {
+		for _, namespace := range k.config.AllowedNamespaces {
+			namespace = strings.TrimSuffix(namespace, "/")
+			if target == namespace || strings.HasPrefix(target, namespace+"/") {
+				return nil
+			}
+		}
+	}
+	return errors.WithMessagef(ErrorKhoriumNamespaceNotAllowed, "name: %s", name)
+}
+
+// verifySignature verifies the signature of ks against public keys,
+// SignedBy of ks is set to the fingerprint of the key if it's verified.
+func (k *KhoriumManager) verifySignature(name string, ks *common.KhoriumStep) error {
+	content, ok := ks.Files[khoriumStepSignatureFile]
+	switch {
+	case !ok && k.config.RequireSignature:
+		return errors.WithMessagef(ErrorKhoriumSignatureRequired, "name: %s", name)
+	case len(k.config.PublicKeys) == 0 && k.config.RequireSignature:
+		// nothing can verify the signature, it's never trusted.
+		return errors.WithMessagef(ErrorKhoriumSignatureInvalid, "name: %s, no public keys", name)
+	case !ok || len(k.config.PublicKeys) == 0:
+		return nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return errors.WithMessagef(ErrorKhoriumSignatureInvalid, "name: %s, %v", name, err)
+	}
+	keys, err := k.publicKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, []byte(ks.Digest), signature) {
+			ks.SignedBy = keyFingerprint(key)
+			return nil
+		}
+	}
+	return errors.WithMessagef(ErrorKhoriumSignatureInvalid, "name: %s, digest: %s", name, ks.Digest)
+}
+
+// publicKeys reads the ed25519 public keys from PEM files.
+func (k *KhoriumManager) publicKeys() ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, file := range k.config.PublicKeys {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(content)
+		if block == nil {
+			return nil, errors.Errorf("no PEM data in %s", file)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "public key: %s", file)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.Errorf("%s is not an ed25519 public key", file)
+		}
+		keys = append(keys, edKey)
+	}
+	return keys, nil
+}
+
+// keyFingerprint returns the fingerprint of key, like SHA256:<hex>.
+func keyFingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return "SHA256:" + hex.EncodeToString(sum[:])
+}
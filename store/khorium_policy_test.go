@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+func TestKhoriumManagerDigestPin(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	repo, commit := newTestRepository(t)
+	commit("echo v1")
+	assert.NoError(exec.Command("git", "-C", repo, "tag", "v1").Run())
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), RefTTLSecs: 0})
+	k.repositoryURL = func(name string) string { return repo }
+
+	ks, err := k.GetKhoriumStep(ctx, "example.com/test/step@v1")
+	assert.NoError(err)
+	digest := ks.Digest
+	assert.Equal(filesDigest(ks.Files), digest)
+
+	ks, err = k.GetKhoriumStep(ctx, "example.com/test/step@v1@"+digest)
+	assert.NoError(err)
+	assert.Equal(digest, ks.Digest)
+	_, err = k.GetKhoriumStep(ctx, "example.com/test/step@"+digest)
+	assert.NoError(err)
+
+	// master moves, so the content no longer matches.
+	commit("echo v2")
+	_, err = k.GetKhoriumStep(ctx, "example.com/test/step@"+digest)
+	assert.ErrorIs(err, ErrorKhoriumDigestMismatch)
+	_, err = k.GetKhoriumStep(ctx, "example.com/test/step@v1@sha256:0000")
+	assert.ErrorIs(err, ErrorKhoriumDigestMismatch)
+}
+
+func TestKhoriumManagerNamespace(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), AllowedNamespaces: []string{"github.com/projecteru2/", "example.com/steps"}})
+	assert.NoError(k.checkNamespace("github.com/projecteru2/checkout@v1"))
+	assert.NoError(k.checkNamespace("https://example.com/steps/deploy.tar.gz#sha256=00"))
+
+	for _, name := range []string{
+		"github.com/projecteru2-evil/checkout",
+		"github.com/projecteru2/../evil/checkout",
+		"github.com/other/checkout@v1",
+		"https://evil.com/steps/deploy.tar.gz#sha256=00",
+	} {
+		_, err := k.GetKhoriumStep(ctx, name)
+		assert.ErrorIs(err, ErrorKhoriumNamespaceNotAllowed, name)
+	}
+}
+
+func TestKhoriumManagerSignature(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	assert.NoError(err)
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	assert.NoError(ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+
+	root := t.TempDir()
+	newStep := func(name string, sign func(digest string) string) string {
+		dir := filepath.Join(root, name)
+		files := map[string][]byte{"khoriumstep.yml": []byte(testKhoriumStepSpec), "main.sh": []byte("echo " + name)}
+		assert.NoError(os.MkdirAll(dir, 0755))
+		for file, content := range files {
+			assert.NoError(ioutil.WriteFile(filepath.Join(dir, file), content, 0644))
+		}
+		if sign != nil {
+			signature := sign(filesDigest(files))
+			assert.NoError(ioutil.WriteFile(filepath.Join(dir, khoriumStepSignatureFile), []byte(signature), 0644))
+		}
+		return "file://" + dir
+	}
+	signed := newStep("signed", func(digest string) string {
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(digest)))
+	})
+	forged := newStep("forged", func(digest string) string {
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte("sha256:other")))
+	})
+	unsigned := newStep("unsigned", nil)
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), FileRoots: []string{root}, PublicKeys: []string{keyFile}})
+	ks, err := k.GetKhoriumStep(ctx, signed)
+	assert.NoError(err)
+	assert.Equal(keyFingerprint(public), ks.SignedBy)
+	_, err = k.GetKhoriumStep(ctx, forged)
+	assert.ErrorIs(err, ErrorKhoriumSignatureInvalid)
+	ks, err = k.GetKhoriumStep(ctx, unsigned)
+	assert.NoError(err)
+	assert.Empty(ks.SignedBy)
+
+	k.config.RequireSignature = true
+	_, err = k.GetKhoriumStep(ctx, unsigned)
+	assert.ErrorIs(err, ErrorKhoriumSignatureRequired)
+
+	// signatures can't be verified without public keys.
+	noKeys := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), FileRoots: []string{root}, RequireSignature: true})
+	_, err = noKeys.GetKhoriumStep(ctx, forged)
+	assert.ErrorIs(err, ErrorKhoriumSignatureInvalid)
+	_, err = noKeys.GetKhoriumStep(ctx, signed)
+	assert.ErrorIs(err, ErrorKhoriumSignatureInvalid)
+	_, err = noKeys.GetKhoriumStep(ctx, unsigned)
+	assert.ErrorIs(err, ErrorKhoriumSignatureRequired)
+
+	// bundled steps belong to the spec, they're never checked.
+	ctx = WithKhoriumStepBundle(ctx, map[string][]byte{"steps/deploy/khoriumstep.yml": []byte(testKhoriumStepSpec)})
+	_, err = k.GetKhoriumStep(ctx, "./steps/deploy")
+	assert.NoError(err)
+}
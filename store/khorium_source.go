@@ -0,0 +1,324 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+var (
+	// ErrorKhoriumStepNotInBundle is returned when a relative step isn't found in the bundle of spec.
+	ErrorKhoriumStepNotInBundle = errors.New("KhoriumStep not found in bundle")
+
+	// ErrorKhoriumSourceNotAllowed is returned when a file:// step is not under any file root.
+	ErrorKhoriumSourceNotAllowed = errors.New("KhoriumStep source not allowed")
+
+	// ErrorKhoriumChecksumRequired is returned when a tarball step has no sha256.
+	ErrorKhoriumChecksumRequired = errors.New("KhoriumStep tarball requires sha256")
+
+	// ErrorKhoriumChecksumMismatch is returned when the digest of tarball doesn't match sha256.
+	ErrorKhoriumChecksumMismatch = errors.New("KhoriumStep tarball checksum mismatch")
+)
+
+const khoriumStepSpecFile = "khoriumstep.yml"
+
+type khoriumBundleKey struct{}
+
+// WithKhoriumStepBundle returns a context with the bundle of spec,
+// bundle is the files of steps relative to spec, with the relative paths as keys,
+// e.g. steps/deploy/khoriumstep.yml for step ./steps/deploy.
+func WithKhoriumStepBundle(ctx context.Context, bundle map[string][]byte) context.Context {
+	return context.WithValue(ctx, khoriumBundleKey{}, bundle)
+}
+
+// IsBundledKhoriumStep tells if name is relative to spec,
+// like ./steps/deploy, such steps are sent along with spec in bundle.
+func IsBundledKhoriumStep(name string) bool {
+	return strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../")
+}
+
+// isTarballKhoriumStep tells if name is a tarball,
+// all http(s) urls are tarballs, file:// urls are tarballs if they have sha256.
+func isTarballKhoriumStep(name string) bool {
+	return strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "http://") ||
+		(strings.HasPrefix(name, "file://") && strings.Contains(name, "#sha256="))
+}
+
+// pinnedStep returns the step loaded before with the pinned digest.
+func (k *KhoriumManager) pinnedStep(ctx context.Context, name string) (*common.KhoriumStep, bool) {
+	digest, ok := pinnedCommit(ctx, name)
+	if !ok {
+		return nil, false
+	}
+
+	return k.cachedStep(name + "@" + digest)
+}
+
+// getBundledKhoriumStep loads the step from the bundle in ctx.
+func (k *KhoriumManager) getBundledKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	if ks, ok := k.pinnedStep(ctx, name); ok {
+		return ks, nil
+	}
+
+	bundle, _ := ctx.Value(khoriumBundleKey{}).(map[string][]byte)
+	prefix := path.Clean(name) + "/"
+	files := map[string][]byte{}
+	for file, content := range bundle {
+		if strings.HasPrefix(file, prefix) {
+			files[strings.TrimPrefix(file, prefix)] = content
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.WithMessagef(ErrorKhoriumStepNotInBundle, "name: %s", name)
+	}
+
+	ks, err := loadKhoriumStepFromFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	k.cacheStep(name+"@"+ks.Commit, ks, false)
+	return ks, nil
+}
+
+// getFileKhoriumStep loads the step from a directory under file roots.
+// The directory is read every time unless pinned, so changes take effect immediately,
+// a pinned step fails if the directory is changed since pinned.
+func (k *KhoriumManager) getFileKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	if ks, ok := k.pinnedStep(ctx, name); ok {
+		return ks, nil
+	}
+
+	dir, err := k.allowedFile(strings.TrimPrefix(name, "file://"))
+	if err != nil {
+		return nil, err
+	}
+
+	ks, err := k.loadKhoriumStepFromFilesystem(dir)
+	if err != nil {
+		return nil, err
+	}
+	ks.Commit = ks.Digest
+	// the pinned step may be expired, the directory must be unchanged since pinned.
+	if digest, ok := pinnedCommit(ctx, name); ok && digest != ks.Digest {
+		return nil, errors.WithMessagef(ErrorKhoriumDigestMismatch, "name: %s, digest: %s", name, ks.Digest)
+	}
+	if err := k.verifySignature(name, ks); err != nil {
+		return nil, err
+	}
+	k.cacheStep(name+"@"+ks.Commit, ks, false)
+	return ks, nil
+}
+
+// allowedFile returns the cleaned path if it's under one of the file roots.
+func (k *KhoriumManager) allowedFile(file string) (string, error) {
+	file = filepath.Clean(file)
+	for _, root := range k.config.FileRoots {
+		root = filepath.Clean(root)
+		if file == root || strings.HasPrefix(file, root+string(filepath.Separator)) {
+			return file, nil
+		}
+	}
+	return "", errors.WithMessagef(ErrorKhoriumSourceNotAllowed, "path: %s", file)
+}
+
+// getTarballKhoriumStep loads the step from a tarball.
+// Since tarball is identified by its sha256, it's downloaded and extracted
+// under cache dir only once.
+func (k *KhoriumManager) getTarballKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error) {
+	u, err := url.Parse(name)
+	if err != nil {
+		return nil, err
+	}
+	digest := strings.ToLower(strings.TrimPrefix(u.Fragment, "sha256="))
+	if !strings.HasPrefix(u.Fragment, "sha256=") || digest == "" {
+		return nil, errors.WithMessagef(ErrorKhoriumChecksumRequired, "name: %s", name)
+	}
+	commit := "sha256:" + digest
+
+	if ks, ok := k.cachedStep(name + "@" + commit); ok {
+		return ks, nil
+	}
+
+	lock := k.repoLock(commit)
+	lock.Lock()
+	defer lock.Unlock()
+
+	dir := filepath.Join(k.config.CacheDir, "tarballs", digest)
+	if _, err := os.Stat(dir); err != nil {
+		u.Fragment = ""
+		content, err := k.downloadTarball(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != digest {
+			return nil, errors.WithMessagef(ErrorKhoriumChecksumMismatch, "name: %s, sha256: %s", name, hex.EncodeToString(sum[:]))
+		}
+		if err := extractTarball(content, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	// tarballs like GitHub archives have all files under a top level dir.
+	root := dir
+	if _, err := os.Stat(filepath.Join(dir, khoriumStepSpecFile)); err != nil {
+		entries, err := ioutil.ReadDir(dir)
+		if err == nil && len(entries) == 1 && entries[0].IsDir() {
+			root = filepath.Join(dir, entries[0].Name())
+		}
+	}
+
+	ks, err := k.loadKhoriumStepFromFilesystem(root)
+	if err != nil {
+		return nil, err
+	}
+	ks.Commit = commit
+	if err := k.verifySignature(name, ks); err != nil {
+		return nil, err
+	}
+	k.cacheStep(name+"@"+ks.Commit, ks, false)
+	return ks, nil
+}
+
+func (k *KhoriumManager) downloadTarball(ctx context.Context, u *url.URL) ([]byte, error) {
+	if u.Scheme == "file" {
+		file, err := k.allowedFile(u.Path)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadFile(file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", u.String(), resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// extractTarball extracts a gzipped or plain tarball into dir.
+// Files are extracted into a temp dir first, so dir is either complete or absent.
+// Only regular files and dirs are extracted, paths are kept inside dir.
+func extractTarball(content []byte, dir string) error {
+	var reader io.Reader = bytes.NewReader(content)
+	if len(content) > 2 && content[0] == 0x1f && content[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(dir), "extract-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean("/" + header.Name)
+		if name == "/" {
+			continue
+		}
+		target := filepath.Join(tmp, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode)&0755|0600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, tr); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Rename(tmp, dir)
+}
+
+// loadKhoriumStepFromFiles loads the step from files,
+// with the digest of files as commit.
+func loadKhoriumStepFromFiles(files map[string][]byte) (*common.KhoriumStep, error) {
+	content, ok := files[khoriumStepSpecFile]
+	if !ok {
+		return nil, errors.WithMessagef(os.ErrNotExist, "file: %s", khoriumStepSpecFile)
+	}
+
+	ks, err := common.LoadKhoriumStep(content)
+	if err != nil {
+		return nil, err
+	}
+	ks.Files = files
+	ks.Digest = filesDigest(files)
+	ks.Commit = ks.Digest
+	return ks, nil
+}
+
+// filesDigest returns the sha256 digest of files, names are included,
+// the signature file is excluded since it signs the digest.
+func filesDigest(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if name != khoriumStepSignatureFile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(files[name]))
+		h.Write(files[name])
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
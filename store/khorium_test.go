@@ -0,0 +1,311 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// newTestRepository creates a git repository with a KhoriumStep,
+// returns the path and a function to commit main.sh with the given content.
+func newTestRepository(t *testing.T) (string, func(content string) string) {
+	repo := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@test"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+		return string(output)
+	}
+
+	run("init", "--quiet", "--initial-branch=master")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "khoriumstep.yml"), []byte("name: test\nrun:\n  main: sh main.sh\n"), 0644))
+
+	commit := func(content string) string {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "main.sh"), []byte(content), 0644))
+		run("add", ".")
+		run("commit", "--quiet", "-m", content)
+		sha, err := gitOutput(context.Background(), repo, "rev-parse", "HEAD")
+		assert.NoError(t, err)
+		return sha
+	}
+	return repo, commit
+}
+
+func TestKhoriumManager(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	repo, commit := newTestRepository(t)
+	v1 := commit("echo v1")
+	assert.NoError(exec.Command("git", "-C", repo, "tag", "v1").Run())
+	v2 := commit("echo v2")
+
+	clones := 0
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), RefTTLSecs: 3600})
+	k.repositoryURL = func(name string) string {
+		clones++
+		return repo
+	}
+
+	ks, err := k.GetKhoriumStep(ctx, "example.com/test/step@v1")
+	assert.NoError(err)
+	assert.Equal(v1, ks.Commit)
+	assert.Equal("echo v1", string(ks.Files["main.sh"]))
+	assert.Contains(ks.Files, "khoriumstep.yml")
+
+	ks, err = k.GetKhoriumStep(ctx, "example.com/test/step")
+	assert.NoError(err)
+	assert.Equal(v2, ks.Commit)
+	assert.Equal("echo v2", string(ks.Files["main.sh"]))
+
+	ks, err = k.GetKhoriumStep(ctx, "example.com/test/step@"+v1[:10])
+	assert.NoError(err)
+	assert.Equal(v1, ks.Commit)
+
+	_, err = k.GetKhoriumStep(ctx, "example.com/test/step@unknown")
+	assert.ErrorIs(err, ErrorKhoriumRefNotFound)
+
+	// branch is reused within TTL, tag is immutable even if moved.
+	v3 := commit("echo v3")
+	assert.NoError(exec.Command("git", "-C", repo, "tag", "-f", "v1").Run())
+	ks, err = k.GetKhoriumStep(ctx, "example.com/test/step@master")
+	assert.NoError(err)
+	assert.Equal(v2, ks.Commit)
+
+	k.refs["example.com/test/step@master"].resolvedAt = time.Now().Add(-time.Hour)
+	ks, err = k.GetKhoriumStep(ctx, "example.com/test/step@master")
+	assert.NoError(err)
+	assert.Equal(v3, ks.Commit)
+	assert.Equal("echo v3", string(ks.Files["main.sh"]))
+
+	ks, err = k.GetKhoriumStep(ctx, "example.com/test/step@v1")
+	assert.NoError(err)
+	assert.Equal(v1, ks.Commit)
+
+	// repository is cloned only once.
+	assert.Equal(1, clones)
+}
+
+func TestKhoriumManagerPrefetch(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	repo, commit := newTestRepository(t)
+	v1 := commit("echo v1")
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), RefTTLSecs: 0})
+	k.repositoryURL = func(name string) string { return repo }
+
+	pins, err := k.Prefetch(ctx, []string{"example.com/test/step"})
+	assert.NoError(err)
+	assert.Equal(map[string]string{"example.com/test/step": v1}, pins)
+
+	// TTL is 0, so branch is resolved every time unless pinned.
+	v2 := commit("echo v2")
+	ks, err := k.GetKhoriumStep(WithKhoriumStepPins(ctx, pins), "example.com/test/step")
+	assert.NoError(err)
+	assert.Equal(v1, ks.Commit)
+	assert.Equal("echo v1", string(ks.Files["main.sh"]))
+
+	ks, err = k.GetKhoriumStep(ctx, "example.com/test/step")
+	assert.NoError(err)
+	assert.Equal(v2, ks.Commit)
+}
+
+const testKhoriumStepSpec = "name: test\nrun:\n  main: sh main.sh\n"
+
+func TestKhoriumManagerBundle(t *testing.T) {
+	assert := assert.New(t)
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir()})
+	ctx := WithKhoriumStepBundle(context.Background(), map[string][]byte{
+		"steps/deploy/khoriumstep.yml": []byte(testKhoriumStepSpec),
+		"steps/deploy/main.sh":         []byte("echo deploy"),
+		"steps/other/main.sh":          []byte("echo other"),
+	})
+
+	ks, err := k.GetKhoriumStep(ctx, "./steps/deploy")
+	assert.NoError(err)
+	assert.Equal(map[string][]byte{"khoriumstep.yml": []byte(testKhoriumStepSpec), "main.sh": []byte("echo deploy")}, ks.Files)
+	assert.True(strings.HasPrefix(ks.Commit, "sha256:"))
+
+	_, err = k.GetKhoriumStep(ctx, "./steps/missing")
+	assert.ErrorIs(err, ErrorKhoriumStepNotInBundle)
+	_, err = k.GetKhoriumStep(context.Background(), "./steps/deploy")
+	assert.ErrorIs(err, ErrorKhoriumStepNotInBundle)
+}
+
+func TestKhoriumManagerPrefetchComposite(t *testing.T) {
+	assert := assert.New(t)
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir()})
+	ctx := WithKhoriumStepBundle(context.Background(), map[string][]byte{
+		"steps/deploy/khoriumstep.yml": []byte(testKhoriumStepSpec),
+		"steps/release/khoriumstep.yml": []byte(`
+name: release
+inputs:
+  target:
+    default: deploy
+run:
+  steps:
+    - uses: ./steps/deploy
+    - uses: ./steps/{{ inputs.target }}
+    - uses: ./steps/release
+`),
+	})
+
+	// steps used by composite are prefetched, templated ones are skipped.
+	pins, err := k.Prefetch(ctx, []string{"./steps/release"})
+	assert.NoError(err)
+	assert.Len(pins, 2)
+	assert.Contains(pins, "./steps/deploy")
+}
+
+func TestKhoriumManagerFile(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "deploy")
+	assert.NoError(os.MkdirAll(dir, 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "khoriumstep.yml"), []byte(testKhoriumStepSpec), 0644))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "main.sh"), []byte("echo v1"), 0644))
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), FileRoots: []string{root}, StepTTLSecs: 3600})
+
+	pins, err := k.Prefetch(ctx, []string{"file://" + dir})
+	assert.NoError(err)
+
+	_, err = k.GetKhoriumStep(ctx, "file://"+root+"/../etc")
+	assert.ErrorIs(err, ErrorKhoriumSourceNotAllowed)
+
+	// changes take effect immediately, unless pinned.
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "main.sh"), []byte("echo v2"), 0644))
+	ks, err := k.GetKhoriumStep(ctx, "file://"+dir)
+	assert.NoError(err)
+	assert.Equal("echo v2", string(ks.Files["main.sh"]))
+	assert.NotEqual(pins["file://"+dir], ks.Commit)
+
+	ks, err = k.GetKhoriumStep(WithKhoriumStepPins(ctx, pins), "file://"+dir)
+	assert.NoError(err)
+	assert.Equal("echo v1", string(ks.Files["main.sh"]))
+
+	// steps returned are copies.
+	ks.Run.Main = "changed"
+	delete(ks.Files, "main.sh")
+	ks, err = k.GetKhoriumStep(WithKhoriumStepPins(ctx, pins), "file://"+dir)
+	assert.NoError(err)
+	assert.NotEqual("changed", ks.Run.Main)
+	assert.Equal("echo v1", string(ks.Files["main.sh"]))
+
+	// expired steps are evicted, the pinned one is gone with the directory changed.
+	k.config.StepTTLSecs = 0
+	_, err = k.GetKhoriumStep(ctx, "file://"+dir)
+	assert.NoError(err)
+	assert.Len(k.steps, 1)
+	_, err = k.GetKhoriumStep(WithKhoriumStepPins(ctx, pins), "file://"+dir)
+	assert.ErrorIs(err, ErrorKhoriumDigestMismatch)
+}
+
+func newTestTarball(t *testing.T, files map[string]string) ([]byte, string) {
+	buffer := &bytes.Buffer{}
+	gz := gzip.NewWriter(buffer)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	sum := sha256.Sum256(buffer.Bytes())
+	return buffer.Bytes(), hex.EncodeToString(sum[:])
+}
+
+func TestKhoriumManagerTarball(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	tarball, digest := newTestTarball(t, map[string]string{
+		"deploy-v1/khoriumstep.yml":           testKhoriumStepSpec,
+		"deploy-v1/main.sh":                   "echo deploy",
+		"deploy-v1/../../deploy-v1/escape.sh": "echo escape",
+	})
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		w.Write(tarball)
+	}))
+	defer server.Close()
+
+	root := t.TempDir()
+	assert.NoError(ioutil.WriteFile(filepath.Join(root, "deploy.tar.gz"), tarball, 0644))
+
+	cacheDir := t.TempDir()
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: cacheDir, FileRoots: []string{root}})
+
+	_, err := k.GetKhoriumStep(ctx, server.URL+"/deploy.tar.gz")
+	assert.ErrorIs(err, ErrorKhoriumChecksumRequired)
+	_, err = k.GetKhoriumStep(ctx, server.URL+"/deploy.tar.gz#sha256="+strings.Repeat("0", 64))
+	assert.ErrorIs(err, ErrorKhoriumChecksumMismatch)
+
+	ks, err := k.GetKhoriumStep(ctx, server.URL+"/deploy.tar.gz#sha256="+digest)
+	assert.NoError(err)
+	assert.Equal("sha256:"+digest, ks.Commit)
+	assert.Equal("echo deploy", string(ks.Files["main.sh"]))
+	assert.Equal("echo escape", string(ks.Files["escape.sh"]))
+	assert.NoFileExists(filepath.Join(filepath.Dir(cacheDir), "escape.sh"))
+
+	// tarball is downloaded only once, even by a new manager.
+	k = NewKhoriumManager(common.KhoriumConfig{CacheDir: cacheDir})
+	_, err = k.GetKhoriumStep(ctx, server.URL+"/deploy.tar.gz#sha256="+digest)
+	assert.NoError(err)
+	assert.Equal(2, downloads)
+
+	k = NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), FileRoots: []string{root}})
+	ks, err = k.GetKhoriumStep(ctx, "file://"+root+"/deploy.tar.gz#sha256="+digest)
+	assert.NoError(err)
+	assert.Equal("echo deploy", string(ks.Files["main.sh"]))
+}
+
+func TestKhoriumManagerGitTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	k := NewKhoriumManager(common.KhoriumConfig{
+		GitLabUsername:    "user",
+		GitLabAccessToken: "token",
+		SSHHosts:          []string{"git.example.com"},
+	})
+	assert.Equal("https://github.com/test/step.git", k.repositoryURL("github.com/test/step"))
+	assert.Equal("https://gitlab.example.com/test/step.git", k.repositoryURL("gitlab.example.com/test/step"))
+	assert.Equal("git@git.example.com:test/step.git", k.repositoryURL("git.example.com/test/step"))
+
+	// credentials are answered by the helper instead of url.
+	cmd := exec.Command("git", "-c", "credential.helper=", "-c", "credential.helper="+envCredentialHelper, "credential", "fill")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "PISTAGE_GIT_USERNAME=user", "PISTAGE_GIT_PASSWORD=token")
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=gitlab.example.com\n\n")
+	output, err := cmd.Output()
+	assert.NoError(err)
+	assert.Contains(string(output), "username=user\npassword=token\n")
+
+	k = NewKhoriumManager(common.KhoriumConfig{SSHKey: "/keys/id_ed25519", SSHKnownHosts: "/keys/known_hosts"})
+	assert.Equal("ssh -o BatchMode=yes -o StrictHostKeyChecking=yes -o IdentitiesOnly=yes -i '/keys/id_ed25519' -o UserKnownHostsFile='/keys/known_hosts'", k.sshCommand())
+}
@@ -23,6 +23,7 @@ type JobRunModel struct {
 	PistageRunID       int64  `gorm:"pistage_run_id"`
 	JobName            string `gorm:"job_name"`
 	RunStatus          string `gorm:"run_status"`
+	PrepareTime        int64  `gorm:"column:prepare_time"`
 }
 
 func (JobRunModel) TableName() string {
@@ -63,9 +64,10 @@ func (ms *MySQLStore) GetJobRun(id string) (run *common.JobRun, err error) {
 
 func (ms *MySQLStore) UpdateJobRun(jobRun *common.JobRun) error {
 	return ms.db.Model(&JobRunModel{}).Where("id = ?", jobRun.ID).Updates(map[string]interface{}{
-		"start_time": jobRun.Start,
-		"end_time":   jobRun.End,
-		"run_status": string(jobRun.Status),
+		"start_time":   jobRun.Start,
+		"end_time":     jobRun.End,
+		"run_status":   string(jobRun.Status),
+		"prepare_time": jobRun.Prepared,
 	}).Error
 }
 
@@ -92,5 +94,6 @@ func (m *JobRunModel) toDTO() *common.JobRun {
 		Status:             common.RunStatus(m.RunStatus),
 		Start:              m.StartTime,
 		End:                m.EndTime,
+		Prepared:           m.PrepareTime,
 	}
 }
@@ -37,6 +37,7 @@ func (s *MySQLStoreTestSuite) TestJobRun() {
 
 	jobRun2.Status = common.RunStatusFailed
 	jobRun2.End = common.EpochMillis() + 1
+	jobRun2.Prepared = jobRun2.End - 1
 	s.NoError(s.ms.UpdateJobRun(jobRun2))
 
 	jobRun2, err := s.ms.GetJobRun(jobRun2.ID)
@@ -46,4 +47,5 @@ func (s *MySQLStoreTestSuite) TestJobRun() {
 	s.Equal("testing-type", jobRun2.WorkflowType)
 	s.Equal(common.RunStatusFailed, jobRun2.Status)
 	s.Greater(jobRun2.End, jobRun2.Start)
+	s.Equal(jobRun2.End-1, jobRun2.Prepared)
 }
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	"github.com/bwmarrin/snowflake"
@@ -18,10 +19,11 @@ type MySQLStore struct {
 	mutex          sync.Mutex
 	snowflake      *snowflake.Node
 	khoriumManager *store.KhoriumManager
+	cacheManager   *store.CacheManager
 	db             *gorm.DB
 }
 
-func NewMySQLStore(c *common.SQLDataSourceConfig, khoriumManager *store.KhoriumManager) (*MySQLStore, error) {
+func NewMySQLStore(c *common.SQLDataSourceConfig, khoriumManager *store.KhoriumManager, cacheManager *store.CacheManager) (*MySQLStore, error) {
 	gormDB, err := gorm.Open(mysql.Open(c.DSN()), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
@@ -42,6 +44,7 @@ func NewMySQLStore(c *common.SQLDataSourceConfig, khoriumManager *store.KhoriumM
 	return &MySQLStore{
 		snowflake:      sn,
 		khoriumManager: khoriumManager,
+		cacheManager:   cacheManager,
 		db:             gormDB,
 	}, nil
 }
@@ -58,6 +61,30 @@ func (ms *MySQLStore) GetRegisteredKhoriumStep(ctx context.Context, name string)
 	return ms.khoriumManager.GetKhoriumStep(ctx, name)
 }
 
+func (ms *MySQLStore) PrefetchKhoriumSteps(ctx context.Context, names []string) (map[string]string, error) {
+	return ms.khoriumManager.Prefetch(ctx, names)
+}
+
+func (ms *MySQLStore) ListKhoriumSteps(ctx context.Context, prefix string) ([]*common.KhoriumStepSummary, error) {
+	return ms.khoriumManager.List(ctx, prefix)
+}
+
+func (ms *MySQLStore) GetKhoriumStepVersions(ctx context.Context, name string) ([]string, error) {
+	return ms.khoriumManager.Versions(ctx, name)
+}
+
+func (ms *MySQLStore) GetRepositoryFile(ctx context.Context, name string) ([]byte, error) {
+	return ms.khoriumManager.GetFile(ctx, name)
+}
+
+func (ms *MySQLStore) RestoreCache(ctx context.Context, key string, restoreKeys []string) (string, io.ReadCloser, error) {
+	return ms.cacheManager.Restore(ctx, key, restoreKeys)
+}
+
+func (ms *MySQLStore) SaveCache(ctx context.Context, key string, content io.Reader) error {
+	return ms.cacheManager.Save(ctx, key, content)
+}
+
 // findWithPagination calls conn.Find(dst) while also returning the total number of results in the query
 // This method will not work if conn contains a .Distinct(table.*) statement because COUNT(DISTINCT table.*) is invalid SQL
 // If this is required, a workaround is to instead use:
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"encoding/json"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -62,6 +63,10 @@ type PistageRunModel struct {
 	WorkflowIdentifier string `gorm:"workflow_identifier"`
 	SnapshotVersion    int64  `gorm:"snapshot_version"`
 	RunStatus          string `gorm:"run_status"`
+	QueueTime          int64  `gorm:"column:queue_time"`
+
+	// Vars is the JSON of the values of inputs.
+	Vars string `gorm:"vars"`
 }
 
 func (PistageRunModel) TableName() string {
@@ -76,11 +81,16 @@ func (m *PistageRunModel) BeforeCreate(db *gorm.DB) error {
 
 func (ms *MySQLStore) CreatePistageRun(pistage *common.Pistage, version string) (id string, err error) {
 	snapshotVersion, _ := strconv.ParseInt(version, 10, 64)
+	vars, err := json.Marshal(pistage.Vars)
+	if err != nil {
+		return
+	}
 	run := PistageRunModel{
 		WorkflowType:       pistage.WorkflowType,
 		WorkflowIdentifier: pistage.WorkflowIdentifier,
 		SnapshotVersion:    snapshotVersion,
 		RunStatus:          string(common.RunStatusPending),
+		Vars:               string(vars),
 	}
 	if err = ms.db.Create(&run).Error; err == nil {
 		id = strconv.FormatInt(run.ID, 10)
@@ -112,6 +122,7 @@ func (ms *MySQLStore) UpdatePistageRun(run *common.Run) error {
 		"start_time": run.Start,
 		"end_time":   run.End,
 		"run_status": run.Status,
+		"queue_time": run.Queued,
 	}).Error
 }
 
@@ -127,6 +138,10 @@ func (ms *MySQLStore) GetPaginatedPistageRunsByWorkflowIdentifier(workflowIdenti
 }
 
 func (m *PistageRunModel) toDTO() *common.Run {
+	// runs created before inputs have no vars
+	var vars map[string]string
+	_ = json.Unmarshal([]byte(m.Vars), &vars)
+
 	return &common.Run{
 		ID:                 strconv.FormatInt(m.ID, 10),
 		UUID:               m.UUID,
@@ -135,6 +150,9 @@ func (m *PistageRunModel) toDTO() *common.Run {
 		Status:             common.RunStatus(m.RunStatus),
 		Start:              m.StartTime,
 		End:                m.EndTime,
+		Vars:               vars,
+		SnapshotID:         strconv.FormatInt(m.SnapshotVersion, 10),
+		Queued:             m.QueueTime,
 	}
 }
 
@@ -73,9 +73,11 @@ func (s *MySQLStoreTestSuite) TestPistageRun() {
 	s.NotEmpty(run.UUID)
 	s.Equal("test-type", run.WorkflowType)
 	s.Equal(common.RunStatusPending, run.Status)
+	s.Equal("1", run.SnapshotID)
 
 	run.Status = common.RunStatusRunning
 	run.Start = common.EpochMillis()
+	run.Queued = run.Start - 1
 	s.NoError(s.ms.UpdatePistageRun(run))
 
 	run, err = s.ms.GetPistageRun(id)
@@ -85,15 +87,22 @@ func (s *MySQLStoreTestSuite) TestPistageRun() {
 	s.Equal("test-type", run.WorkflowType)
 	s.Equal(common.RunStatusRunning, run.Status)
 	s.Greater(run.Start, int64(0))
+	s.Equal(run.Start-1, run.Queued)
 
 	lastRun, err := s.ms.GetLatestPistageRunByWorkflowIdentifier(run.WorkflowIdentifier)
 	s.NoError(err)
 	s.Equal(id, lastRun.ID)
 
-	id2, err := s.ms.CreatePistageRun(testingPistage(), "2")
+	pistage := testingPistage()
+	pistage.Vars = map[string]string{"version": "v1"}
+	id2, err := s.ms.CreatePistageRun(pistage, "2")
 	s.NoError(err)
 	s.NotEmpty(id2)
 
+	run2, err := s.ms.GetPistageRun(id2)
+	s.NoError(err)
+	s.Equal(map[string]string{"version": "v1"}, run2.Vars)
+
 	runs, cnt, err := s.ms.GetPaginatedPistageRunsByWorkflowIdentifier(run.WorkflowIdentifier, 20, 1)
 	s.NoError(err)
 	s.EqualValues(cnt, 2)
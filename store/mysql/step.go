@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+type StepRunModel struct {
+	ID int64 `gorm:"primaryKey"`
+
+	CreateTime int64 `gorm:"column:create_time;autoCreateTime:milli"`
+
+	JobRunID int64  `gorm:"job_run_id"`
+	StepName string `gorm:"step_name"`
+	Uses     string `gorm:"uses"`
+	Commit   string `gorm:"commit"`
+	Digest   string `gorm:"digest"`
+	SignedBy string `gorm:"signed_by"`
+	Rollback bool   `gorm:"rollback"`
+}
+
+func (StepRunModel) TableName() string {
+	return "step_run_tab"
+}
+
+func (ms *MySQLStore) CreateStepRuns(jobRun *common.JobRun, stepRuns []*common.StepRun) error {
+	if len(stepRuns) == 0 {
+		return nil
+	}
+
+	jobRunID, _ := strconv.ParseInt(jobRun.ID, 10, 64)
+	models := make([]*StepRunModel, 0, len(stepRuns))
+	for _, stepRun := range stepRuns {
+		models = append(models, &StepRunModel{
+			JobRunID: jobRunID,
+			StepName: stepRun.StepName,
+			Uses:     stepRun.Uses,
+			Commit:   stepRun.Commit,
+			Digest:   stepRun.Digest,
+			SignedBy: stepRun.SignedBy,
+			Rollback: stepRun.Rollback,
+		})
+	}
+	if err := ms.db.Create(models).Error; err != nil {
+		return err
+	}
+	for i, model := range models {
+		stepRuns[i].ID = strconv.FormatInt(model.ID, 10)
+		stepRuns[i].JobRunID = jobRun.ID
+	}
+	return nil
+}
+
+func (ms *MySQLStore) GetStepRunsByJobRunId(jobRunId string) ([]*common.StepRun, error) {
+	var models []StepRunModel
+	err := ms.db.Where("job_run_id = ?", jobRunId).Order("id").Find(&models).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	result := make([]*common.StepRun, 0)
+	for _, model := range models {
+		result = append(result, model.toDTO())
+	}
+	return result, nil
+}
+
+func (m *StepRunModel) toDTO() *common.StepRun {
+	return &common.StepRun{
+		ID:       strconv.FormatInt(m.ID, 10),
+		JobRunID: strconv.FormatInt(m.JobRunID, 10),
+		StepName: m.StepName,
+		Uses:     m.Uses,
+		Commit:   m.Commit,
+		Digest:   m.Digest,
+		SignedBy: m.SignedBy,
+		Rollback: m.Rollback,
+	}
+}
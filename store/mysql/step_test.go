@@ -0,0 +1,20 @@
+package mysql
+
+import "github.com/projecteru2/pistage/common"
+
+func (s *MySQLStoreTestSuite) TestStepRun() {
+	jobRun := testingJobRun("job1")
+	s.NoError(s.ms.CreateJobRun(testingRun(), jobRun))
+
+	stepRuns := []*common.StepRun{
+		{StepName: "checkout", Uses: "github.com/test/checkout@v1", Commit: "0123456789abcdef", Digest: "sha256:aa"},
+		{StepName: "rollback", Uses: "./steps/rollback", Commit: "sha256:bb", Digest: "sha256:bb", SignedBy: "SHA256:cc", Rollback: true},
+	}
+	s.NoError(s.ms.CreateStepRuns(jobRun, stepRuns))
+	s.NotEmpty(stepRuns[0].ID)
+	s.Equal(jobRun.ID, stepRuns[1].JobRunID)
+
+	got, err := s.ms.GetStepRunsByJobRunId(jobRun.ID)
+	s.NoError(err)
+	s.Equal(stepRuns, got)
+}
@@ -15,7 +15,8 @@ func TruncateTables(db *gorm.DB) error {
 		err  error
 		sqls = `TRUNCATE TABLE pistage_snapshot_tab
 TRUNCATE TABLE pistage_run_tab
-TRUNCATE TABLE job_run_tab`
+TRUNCATE TABLE job_run_tab
+TRUNCATE TABLE step_run_tab`
 	)
 	for _, sql := range strings.Split(sqls, "\n") {
 		if terr := db.Exec(sql).Error; terr != nil {
@@ -42,5 +43,5 @@ func NewTestingMySQLStore() (*MySQLStore, error) {
 		Database:     getEnvDefault("PISTAGE_MYSQL_DATABASE", "pistagetest"),
 		MaxConns:     10,
 		MaxIdleConns: 5,
-	}, nil)
+	}, nil, nil)
 }
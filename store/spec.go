@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+// ErrorSpecIncludeNotFound is returned when the spec included doesn't exist.
+var ErrorSpecIncludeNotFound = errors.New("Included spec not found")
+
+// GetFile gets the content of a file in git repository,
+// name is like github.com/org/pipelines//go/service.yml@v1,
+// the file go/service.yml of repository github.com/org/pipelines at v1, version defaults to master.
+// Repositories are mirrored and resolved the same as KhoriumSteps, under the same namespaces.
+func (k *KhoriumManager) GetFile(ctx context.Context, name string) ([]byte, error) {
+	target, version := splitKhoriumName(name)
+	parts := strings.SplitN(target, "//", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errors.WithMessagef(ErrorSpecIncludeNotFound, "name: %s", name)
+	}
+	repository, file := parts[0], parts[1]
+	if err := k.checkNamespace(repository); err != nil {
+		return nil, err
+	}
+
+	commit, err := k.resolve(ctx, repository, version)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := k.repoLock(repository)
+	lock.Lock()
+	defer lock.Unlock()
+
+	mirror, _, err := k.ensureMirror(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	content, err := gitOutput(ctx, mirror, "show", commit+":"+file)
+	if err != nil {
+		return nil, errors.WithMessagef(ErrorSpecIncludeNotFound, "name: %s, %v", name, err)
+	}
+	return []byte(content), nil
+}
+
+// SpecIncludeLoader loads the specs included by spec on server,
+// local includes are read from the bundle of spec,
+// git includes from repositories, templates from the template dirs in config.
+type SpecIncludeLoader struct {
+	store        Store
+	bundle       map[string][]byte
+	templateDirs []string
+}
+
+// NewSpecIncludeLoader creates a SpecIncludeLoader for spec with bundle.
+func NewSpecIncludeLoader(store Store, bundle map[string][]byte, config common.SpecConfig) *SpecIncludeLoader {
+	return &SpecIncludeLoader{
+		store:        store,
+		bundle:       bundle,
+		templateDirs: config.TemplateDirs,
+	}
+}
+
+// LoadInclude implements common.IncludeLoader.
+func (s *SpecIncludeLoader) LoadInclude(ctx context.Context, include common.Include) ([]byte, error) {
+	switch {
+	case include.Git != "":
+		return s.store.GetRepositoryFile(ctx, include.Git)
+	case include.Template != "":
+		return s.loadTemplate(include.Template)
+	default:
+		content, ok := s.bundle[include.Local]
+		if !ok {
+			return nil, errors.WithMessagef(ErrorSpecIncludeNotFound, "local: %s", include.Local)
+		}
+		return content, nil
+	}
+}
+
+// loadTemplate reads template name under template dirs, the first one found wins,
+// name without extension is name.yml.
+func (s *SpecIncludeLoader) loadTemplate(name string) ([]byte, error) {
+	if path.Ext(name) == "" {
+		name += ".yml"
+	}
+	if path.IsAbs(name) || path.Clean(name) != name || strings.HasPrefix(name, "../") {
+		return nil, errors.WithMessagef(ErrorSpecIncludeNotFound, "template: %s", name)
+	}
+
+	for _, dir := range s.templateDirs {
+		content, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		return content, err
+	}
+	return nil, errors.WithMessagef(ErrorSpecIncludeNotFound, "template: %s", name)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/projecteru2/pistage/common"
+)
+
+type specTestStore struct {
+	Store
+	k *KhoriumManager
+}
+
+func (s *specTestStore) GetRepositoryFile(ctx context.Context, name string) ([]byte, error) {
+	return s.k.GetFile(ctx, name)
+}
+
+func TestSpecIncludeLoader(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	repo, commit := newTestRepository(t)
+	assert.NoError(os.MkdirAll(filepath.Join(repo, "go"), 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(repo, "go", "service.yml"), []byte("executor: docker\n"), 0644))
+	commit("echo v1")
+	assert.NoError(exec.Command("git", "-C", repo, "tag", "v1").Run())
+
+	k := NewKhoriumManager(common.KhoriumConfig{CacheDir: t.TempDir(), AllowedNamespaces: []string{"example.com/org"}})
+	k.repositoryURL = func(name string) string { return repo }
+
+	templates := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(templates, "go"), 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(templates, "go", "lib.yml"), []byte("env:\n  A: b\n"), 0644))
+
+	loader := NewSpecIncludeLoader(&specTestStore{k: k}, map[string][]byte{"ci/base.yml": []byte("jobs: {}")}, common.SpecConfig{TemplateDirs: []string{t.TempDir(), templates}})
+
+	content, err := loader.LoadInclude(ctx, common.Include{Git: "example.com/org/pipelines//go/service.yml@v1"})
+	assert.NoError(err)
+	assert.Equal("executor: docker", string(content))
+
+	_, err = loader.LoadInclude(ctx, common.Include{Git: "example.com/org/pipelines//go/missing.yml@v1"})
+	assert.ErrorIs(err, ErrorSpecIncludeNotFound)
+
+	_, err = loader.LoadInclude(ctx, common.Include{Git: "example.com/other/pipelines//go/service.yml@v1"})
+	assert.ErrorIs(err, ErrorKhoriumNamespaceNotAllowed)
+
+	content, err = loader.LoadInclude(ctx, common.Include{Template: "go/lib"})
+	assert.NoError(err)
+	assert.Equal("env:\n  A: b\n", string(content))
+
+	_, err = loader.LoadInclude(ctx, common.Include{Template: "../go/lib"})
+	assert.ErrorIs(err, ErrorSpecIncludeNotFound)
+
+	content, err = loader.LoadInclude(ctx, common.Include{Local: "ci/base.yml"})
+	assert.NoError(err)
+	assert.Equal("jobs: {}", string(content))
+
+	_, err = loader.LoadInclude(ctx, common.Include{Local: "ci/missing.yml"})
+	assert.ErrorIs(err, ErrorSpecIncludeNotFound)
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"io"
 
 	"github.com/projecteru2/pistage/common"
 )
@@ -25,8 +26,22 @@ type Store interface {
 	UpdateJobRun(jobRun *common.JobRun) error
 	GetJobRunsByPistageRunId(id string) ([]*common.JobRun, error)
 
+	// StepRun
+	CreateStepRuns(jobRun *common.JobRun, stepRuns []*common.StepRun) error
+	GetStepRunsByJobRunId(id string) ([]*common.StepRun, error)
+
 	// Register
 	GetRegisteredKhoriumStep(ctx context.Context, name string) (*common.KhoriumStep, error)
+	PrefetchKhoriumSteps(ctx context.Context, names []string) (map[string]string, error)
+	ListKhoriumSteps(ctx context.Context, prefix string) ([]*common.KhoriumStepSummary, error)
+	GetKhoriumStepVersions(ctx context.Context, name string) ([]string, error)
+
+	// Spec
+	GetRepositoryFile(ctx context.Context, name string) ([]byte, error)
+
+	// Cache
+	RestoreCache(ctx context.Context, key string, restoreKeys []string) (string, io.ReadCloser, error)
+	SaveCache(ctx context.Context, key string, content io.Reader) error
 
 	Close() error
 }